@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"strings"
+	"time"
 	"symphony-api/internal/handlers"
 	chat_handlers "symphony-api/internal/handlers/chat"
 	community_handlers "symphony-api/internal/handlers/community"
@@ -9,8 +12,20 @@ import (
 	music_handlers "symphony-api/internal/handlers/music"
 	playlist_handlers "symphony-api/internal/handlers/playlist"
 	artist_handlers "symphony-api/internal/handlers/artist"
+	media_handlers "symphony-api/internal/handlers/media"
+	moderation_handlers "symphony-api/internal/handlers/moderation"
+	story_handlers "symphony-api/internal/handlers/story"
+	account_handlers "symphony-api/internal/handlers/account"
+	events_handlers "symphony-api/internal/handlers/events"
+	notification_handlers "symphony-api/internal/handlers/notification"
+	webhook_handlers "symphony-api/internal/handlers/webhook"
 	mongo_repository "symphony-api/internal/persistence/repository/mongo"
 	"symphony-api/internal/persistence/connectors/neo4j"
+	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/persistence/service"
+	"symphony-api/internal/persistence/storage"
+	"symphony-api/internal/realtime"
+	"symphony-api/internal/webhook"
 
 	"symphony-api/internal/persistence/connectors/postgres"
 	"symphony-api/internal/server"
@@ -33,15 +48,77 @@ func main() {
 	songRepo := mongo_repository.NewSongRepository(mongoConnection)
 	artistRepo := mongo_repository.NewArtistRepository(mongoConnection)
 	playlistRepo := mongo_repository.NewPlaylistRepository(mongoConnection)
+	storyRepo := mongo_repository.NewStoryRepository(mongoConnection)
+
+	// Serviços
+	attachmentService := service.NewAttachmentService(songRepo, artistRepo, playlistRepo)
+	mediaService := service.NewMediaService(
+		storage.NewStorage(),
+		config.GetEnvInt("MEDIA_MAX_UPLOAD_BYTES", 5<<20),
+		config.GetEnv("MEDIA_PUBLIC_URL", "/media"),
+	)
+
+	hub := realtime.NewHub(
+		realtime.NewLocalBroker(),
+		int(config.GetEnvInt("REALTIME_SUBSCRIBER_BUFFER", realtime.DEFAULT_SUBSCRIBER_BUFFER)),
+	)
+	// Without a secret the streams can not be authenticated and are not served.
+	streamAuthenticator := realtime.NewAuthenticator(
+		config.GetEnv("REALTIME_TOKEN_SECRET", ""),
+		strings.Split(config.GetEnv("REALTIME_ALLOWED_ORIGINS", ""), ","),
+	)
+	eventService := service.NewEventService(repository.NewUserEventRepository(postgresConnection), hub)
+	// Webhooks may only target the local network when explicitly allowed,
+	// for development.
+	webhookGuard := webhook.NewGuard(config.GetEnvBool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false))
+	webhookRepository := repository.NewWebhookRepository(postgresConnection)
+	webhookService := service.NewWebhookService(
+		webhookRepository,
+		repository.NewUserRepository(postgresConnection, neo4jConnection),
+		webhookGuard,
+	)
 
 	// Handlers
-	userCrud := user_handlers.NewUserHandler(postgresConnection, neo4jConnection)
-	postCrud := handlers.NewPostCrud(postgresConnection)
-	communityCrud := community_handlers.NewCommunityHandler(postgresConnection, neo4jConnection)
-    chatCrud := chat_handlers.NewChatHandler(postgresConnection, neo4jConnection)
+	userCrud := user_handlers.NewUserHandler(postgresConnection, neo4jConnection, artistRepo, playlistRepo, storyRepo, eventService, webhookService)
+	postCrud := handlers.NewPostCrud(postgresConnection, neo4jConnection, attachmentService, eventService, webhookService)
+	communityCrud := community_handlers.NewCommunityHandler(postgresConnection, neo4jConnection, webhookService)
+    chatCrud := chat_handlers.NewChatHandler(postgresConnection, neo4jConnection, hub, attachmentService, eventService, streamAuthenticator)
     songHandler := music_handlers.NewSongHandler(songRepo)
 	artistHandler := artist_handlers.NewArtistHandler(artistRepo)
-	playlistHandler := playlist_handlers.NewPlaylistHandler(playlistRepo)
+	playlistHandler := playlist_handlers.NewPlaylistHandler(playlistRepo, mediaService)
+	mediaHandler := media_handlers.NewMediaHandler(mediaService)
+	moderationHandler := moderation_handlers.NewModerationHandler(postgresConnection, neo4jConnection)
+	storyHandler := story_handlers.NewStoryHandler(postgresConnection, neo4jConnection, storyRepo, attachmentService)
+	accountHandler := account_handlers.NewAccountHandler(
+		postgresConnection,
+		neo4jConnection,
+		playlistRepo,
+		storyRepo,
+		storage.NewExportStorage(),
+		streamAuthenticator,
+	)
+	eventsHandler := events_handlers.NewEventsHandler(postgresConnection, neo4jConnection, eventService, hub, streamAuthenticator)
+	notificationHandler := notification_handlers.NewNotificationHandler(postgresConnection, neo4jConnection)
+	webhookHandler := webhook_handlers.NewWebhookHandler(webhookService)
+
+	// Keeps the event log from growing forever.
+	go eventService.RunRetention(
+		context.Background(),
+		time.Duration(config.GetEnvInt("USER_EVENT_RETENTION_HOURS", 7*24))*time.Hour,
+		time.Hour,
+	)
+
+	// Sends the queued webhook deliveries in the background.
+	webhookDispatcher := service.NewWebhookDispatcher(
+		webhookRepository,
+		webhookGuard,
+		time.Duration(config.GetEnvInt("WEBHOOK_TIMEOUT_SECONDS", 10))*time.Second,
+		int32(config.GetEnvInt("WEBHOOK_MAX_ATTEMPTS", 10)),
+	)
+	go webhookDispatcher.Run(
+		context.Background(),
+		time.Duration(config.GetEnvInt("WEBHOOK_POLL_SECONDS", 5))*time.Second,
+	)
 
 	// Create a new server instance
 	srv := server.NewServer(config.GetEnv("API_PORT", "8080"))
@@ -55,6 +132,13 @@ func main() {
 	songHandler.AddRoutes(srv)
 	artistHandler.AddRoutes(srv)
 	playlistHandler.AddRoutes(srv)
+	mediaHandler.AddRoutes(srv)
+	moderationHandler.AddRoutes(*srv)
+	storyHandler.AddRoutes(*srv)
+	accountHandler.AddRoutes(srv)
+	eventsHandler.AddRoutes(*srv)
+	notificationHandler.AddRoutes(*srv)
+	webhookHandler.AddRoutes(*srv)
 
 	// Swagger
 	srv.AddRoute("/swagger/*", httpSwagger.Handler(
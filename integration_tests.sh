@@ -92,19 +92,55 @@ post_and_assert "http://localhost:8080/api/chat/create" "{
 
 post_and_assert "http://localhost:8080/api/chat/list_chats?username=$username" "{}" "List chat of user"
 
+post_and_assert "http://localhost:8080/api/chat/inbox?username=$username" "{}" "Get chat inbox"
+
 post_and_assert "http://localhost:8080/api/chat/list_users?chat_id=1" "{}" "List users of chat"
 
+post_and_assert "http://localhost:8080/api/chat/list_messages?chat_id=1&order=oldest" "{}" "List messages of chat"
+
+post_and_assert "http://localhost:8080/api/chat/search_messages?username=$username&q=hello" "{}" "Search chat messages"
+
+post_and_assert "http://localhost:8080/api/chat/mark_read" "{
+ \"chat_id\": 1,
+ \"username\": \"$username\"
+}" "Mark chat as read"
+
+post_and_assert "http://localhost:8080/api/chat/create_group" "{
+ \"username\": \"$username\",
+ \"name\": \"Test group\",
+ \"participants\": [\"$username2\", \"$username3\"]
+}" "Create group chat"
+
 echo "🎉 All tests passed successfully!"
 
-post_and_assert "http://localhost:8080/api/user/create_friendship" "{
- \"username1\": \"$username\",
- \"username2\": \"$username2\"
-}" "Create friendship"
+post_and_assert "http://localhost:8080/api/user/send_friend_request" "{
+ \"username\": \"$username\",
+ \"target\": \"$username2\"
+}" "Send friend request"
+
+post_and_assert "http://localhost:8080/api/user/list_incoming_friend_requests?username=$username2" "{}" "List incoming friend requests"
+
+post_and_assert "http://localhost:8080/api/user/accept_friend_request" "{
+ \"username\": \"$username2\",
+ \"requester\": \"$username\"
+}" "Accept friend request"
 
 post_and_assert "http://localhost:8080/api/user/get_by_username?username=$username2" "{}" "Get user by username"
 
 post_and_assert "http://localhost:8080/api/user/list_friends?username=$username" "{}" "List friends of user"
 
+post_and_assert "http://localhost:8080/api/notification/list?username=$username2" "{}" "List notifications"
+
+post_and_assert "http://localhost:8080/api/notification/set_preference" "{
+ \"username\": \"$username2\",
+ \"type\": \"chat_message\",
+ \"muted\": true
+}" "Mute chat notifications"
+
+post_and_assert "http://localhost:8080/api/notification/mark_all_read" "{
+ \"username\": \"$username2\"
+}" "Mark all notifications as read"
+
 post_and_assert "http://localhost:8080/api/user/like_genre" "{
  \"username\": \"$username\",
  \"genre_name\": \"metal\"
@@ -118,3 +154,6 @@ post_and_assert "http://localhost:8080/api/user/like_genre" "{
 post_and_assert "http://localhost:8080/api/user/list_liked_genres?username=$username" "{}" "List liked genres"
 
 post_and_assert "http://localhost:8080/api/user/get_friends_recommendations_on_genre?username=$username" "{}" "Get recommendations"
+post_and_assert "http://localhost:8080/api/user/recommendations?username=$username&limit=10" "{}" "Get scored recommendations"
+post_and_assert "http://localhost:8080/api/user/connection?username=$username&target=$username2" "{}" "Get social connection"
+post_and_assert "http://localhost:8080/api/user/search?q=test_user&viewer=$username" "{}" "Search users"
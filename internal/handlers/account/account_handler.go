@@ -0,0 +1,207 @@
+package account_handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	base_handlers "symphony-api/internal/handlers/base"
+	request_model "symphony-api/internal/handlers/model"
+	"symphony-api/internal/persistence/connectors/neo4j"
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	mongo_repository "symphony-api/internal/persistence/repository/mongo"
+	"symphony-api/internal/persistence/service"
+	"symphony-api/internal/persistence/storage"
+	"symphony-api/internal/realtime"
+	"symphony-api/pkg/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AccountHandler struct {
+	accountService *service.AccountService
+	authenticator  *realtime.Authenticator
+}
+
+func NewAccountHandler(
+	connection postgres.PostgreConnection,
+	neo4jConnection neo4j.Neo4jConnection,
+	playlistRepository *mongo_repository.PlaylistRepository,
+	storyRepository *mongo_repository.StoryRepository,
+	exportStorage storage.Storage,
+	authenticator *realtime.Authenticator,
+) *AccountHandler {
+	return &AccountHandler{
+		accountService: service.NewAccountService(
+			repository.NewUserRepository(connection, neo4jConnection),
+			repository.NewPostRepository(connection),
+			repository.NewChatRepository(connection),
+			repository.NewDataExportRepository(connection),
+			playlistRepository,
+			storyRepository,
+			exportStorage,
+			int(config.GetEnvInt("EXPORT_MAX_CONCURRENT", 2)),
+		),
+		authenticator: authenticator,
+	}
+}
+
+func (handler *AccountHandler) AddRoutes(server interface {
+	AddRoute(pattern string, handler http.HandlerFunc)
+	AddGroup(pattern string, fn func(r chi.Router))
+}) {
+	// These act on the account of the caller, so they are only served when
+	// requests can be authenticated.
+	if handler.authenticator == nil {
+		log.Printf("REALTIME_TOKEN_SECRET is not set, account deletion and data exports are disabled")
+		return
+	}
+	server.AddGroup("/api/user/me", func(r chi.Router) {
+		r.Get("/", base_handlers.CreateAuthenticatedHandler(handler.authenticator, handler.GetAccount))
+		r.Delete("/", base_handlers.CreateAuthenticatedHandler(handler.authenticator, handler.DeleteAccount))
+	})
+	server.AddRoute("/api/user/export", base_handlers.CreateAuthenticatedHandler(handler.authenticator, handler.RequestDataExport))
+	server.AddRoute("/api/user/export/status", base_handlers.CreateAuthenticatedHandler(handler.authenticator, handler.GetDataExport))
+	server.AddRoute("/api/user/export/download", handler.DownloadDataExport)
+}
+
+// GetAccount returns the profile of the authenticated user with every field.
+//	@Summary		Get the own account
+//	@Description	Returns the profile of the authenticated user, telephone included. Endpoints naming the viewer in a parameter never return it.
+//	@Tags			User
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Bearer token"
+//	@Success		200		{object}	request_model.UserResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		401		{object}	map[string]string	"Unauthorized"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Router			/api/user/me [get]
+func (handler *AccountHandler) GetAccount(userId int32, request request_model.GetAccountRequest) (*request_model.UserResponse, error) {
+	user, err := handler.accountService.GetAccount(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewUserResponse(user, model.USER_VIEW_SELF), nil
+}
+
+// DeleteAccount deletes the account of the authenticated user and all their
+// data.
+//	@Summary		Delete an account
+//	@Description	Deletes the authenticated user with their posts, likes, comments, chat participation, social graph, playlists and stories. Messages sent to others are kept without author.
+//	@Tags			User
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Bearer token"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		401		{object}	map[string]string	"Unauthorized"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/me [delete]
+func (handler *AccountHandler) DeleteAccount(userId int32, request request_model.DeleteAccountRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.accountService.DeleteAccount(context.Background(), userId)
+	if err != nil {
+		log.Printf("Error deleting account: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully deleted account"), nil
+}
+
+// RequestDataExport starts an export of the data of the authenticated user.
+//	@Summary		Request a data export
+//	@Description	Starts bundling the profile, posts, messages, friends, liked genres and playlists of the authenticated user into a ZIP of JSON files. Poll the status until it is done, then download it.
+//	@Tags			User
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Bearer token"
+//	@Success		200		{object}	request_model.DataExportResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		401		{object}	map[string]string	"Unauthorized"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/export [post]
+func (handler *AccountHandler) RequestDataExport(userId int32, request request_model.RequestDataExportRequest) (*request_model.DataExportResponse, error) {
+	export, err := handler.accountService.RequestExport(userId)
+	if err != nil {
+		log.Printf("Error requesting data export: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewDataExportResponse(export), nil
+}
+
+// GetDataExport returns the status of a data export of the authenticated
+// user.
+//	@Summary		Get a data export
+//	@Description	Returns the status of a data export: pending, done or failed.
+//	@Tags			User
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"Bearer token"
+//	@Param			export_id	query		int		true	"Export ID"
+//	@Success		200		{object}	request_model.DataExportResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		401		{object}	map[string]string	"Unauthorized"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/export/status [get]
+func (handler *AccountHandler) GetDataExport(userId int32, request request_model.GetDataExportRequest) (*request_model.DataExportResponse, error) {
+	export, err := handler.accountService.GetExport(userId, request.ExportId)
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewDataExportResponse(export), nil
+}
+
+// DownloadDataExport returns the ZIP of a finished data export of the
+// authenticated user. Browsers can not set headers on downloads started by
+// links, so the token may also be passed as access_token.
+//	@Summary		Download a data export
+//	@Description	Downloads the ZIP of JSON files of a finished data export.
+//	@Tags			User
+//	@Produce		application/zip
+//	@Param			Authorization	header		string	false	"Bearer token"
+//	@Param			access_token	query		string	false	"Token, when the Authorization header can not be set"
+//	@Param			export_id	query		int		true	"Export ID"
+//	@Success		200		{file}		binary
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		401		{object}	map[string]string	"Unauthorized"
+//	@Failure		403		{object}	map[string]string	"Forbidden"
+//	@Failure		404		{object}	map[string]string	"Export Not Found"
+//	@Router			/api/user/export/download [get]
+func (handler *AccountHandler) DownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	if err := handler.authenticator.CheckOrigin(r); err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	userId, err := handler.authenticator.Authenticate(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	request, err := base_handlers.MapUrlValues[request_model.GetDataExportRequest](r)
+	if err != nil {
+		http.Error(w, "Invalid Input", http.StatusBadRequest)
+		return
+	}
+
+	reader, err := handler.accountService.OpenExport(context.Background(), userId, request.ExportId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	defer reader.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="symphony-export-%d.zip"`, request.ExportId))
+	w.Header().Set("Cache-Control", "private, no-store")
+
+	if _, err := io.Copy(w, reader); err != nil {
+		log.Printf("Error sending export %d: %v", request.ExportId, err)
+	}
+}
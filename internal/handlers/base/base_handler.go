@@ -33,4 +33,45 @@ func createHandler[In any, Out any](
 
 		MustEncodeAnswer(response, w)
 	}
-}
\ No newline at end of file
+}
+// Authenticator tells which user sends a request, from a credential that can
+// not be forged, and whether the page sending it may do so.
+type Authenticator interface {
+	CheckOrigin(r *http.Request) error
+	Authenticate(r *http.Request) (int32, error)
+}
+
+// CreateAuthenticatedHandler is like CreateGetMethodHandler, but the handler
+// also gets the id of the user the request is authenticated as. Requests from
+// pages of other origins get 403 and unauthenticated ones 401.
+func CreateAuthenticatedHandler[In any, Out any](
+	authenticator Authenticator,
+	handler func(int32, In) (Out, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := authenticator.CheckOrigin(r); err != nil {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		userId, err := authenticator.Authenticate(r)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		request, err := MapUrlValues[In](r)
+		if err != nil {
+			http.Error(w, "Invalid Input", http.StatusBadRequest)
+			return
+		}
+
+		response, err := handler(userId, *request)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		MustEncodeAnswer(response, w)
+	}
+}
@@ -2,6 +2,8 @@ package base_handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 
@@ -45,3 +47,25 @@ func MustEncodeAnswer(data any, w http.ResponseWriter) {
 		return
 	}
 }
+
+// OpenMultipartFile returns a reader over the file sent in the given field of a
+// multipart/form-data request. The request body is limited to maxBytes plus a
+// small allowance for the multipart envelope.
+func OpenMultipartFile(w http.ResponseWriter, r *http.Request, field string, maxBytes int64) (io.Reader, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+64*1024)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, errors.New("missing file field: " + field)
+		}
+		if part.FormName() == field {
+			return part, nil
+		}
+	}
+}
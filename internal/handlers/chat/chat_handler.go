@@ -7,23 +7,47 @@ import (
 	request_model "symphony-api/internal/handlers/model"
 	"symphony-api/internal/persistence/connectors/neo4j"
 	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/model"
 	"symphony-api/internal/persistence/repository"
 	"symphony-api/internal/persistence/service"
+	"symphony-api/internal/realtime"
 	"symphony-api/internal/server"
 )
 
 type ChatHandler struct {
     chatRepository   *repository.ChatRepository
     chatService      *service.ChatService
+    userRepository   *repository.UserRepository
+    hub              *realtime.Hub
+    authenticator    *realtime.Authenticator
 }
 
-func NewChatHandler(connection postgres.PostgreConnection, neo4jConnection neo4j.Neo4jConnection) *ChatHandler {
+func NewChatHandler(
+    connection postgres.PostgreConnection,
+    neo4jConnection neo4j.Neo4jConnection,
+    hub *realtime.Hub,
+    attachmentService *service.AttachmentService,
+    eventService *service.EventService,
+    authenticator *realtime.Authenticator,
+) *ChatHandler {
     chatRepository := repository.NewChatRepository(connection)
-    chatService := service.NewChatService(chatRepository, repository.NewUserRepository(connection, neo4jConnection))
+    userRepository := repository.NewUserRepository(connection, neo4jConnection)
+    chatService := service.NewChatService(
+        chatRepository,
+        userRepository,
+        service.NewPrivacyService(userRepository),
+        attachmentService,
+        eventService,
+        service.NewNotificationService(repository.NewNotificationRepository(connection), userRepository),
+        hub,
+    )
 
     return &ChatHandler{
         chatRepository: chatRepository,
         chatService:    chatService,
+        userRepository: userRepository,
+        hub:            hub,
+        authenticator:  authenticator,
     }
 }
 
@@ -33,7 +57,28 @@ func (handler *ChatHandler) AddRoutes(server server.Server) {
     server.AddRoute("/api/chat/list_users", base_handlers.CreateGetMethodHandler(handler.ListUsersFromChat))
     server.AddRoute("/api/chat/list_chats", base_handlers.CreateGetMethodHandler(handler.ListChatsFromUser))
     server.AddRoute("/api/chat/list_messages", base_handlers.CreateGetMethodHandler(handler.ListChatMessages))
+    server.AddRoute("/api/chat/search_messages", base_handlers.CreateGetMethodHandler(handler.SearchChatMessages))
     server.AddRoute("/api/chat/add_message", base_handlers.CreatePostMethodHandler(handler.AddMessageToChat))
+    server.AddRoute("/api/chat/edit_message", base_handlers.CreatePostMethodHandler(handler.EditChatMessage))
+    server.AddRoute("/api/chat/delete_message", base_handlers.CreatePostMethodHandler(handler.DeleteChatMessage))
+    server.AddRoute("/api/chat/message_history", base_handlers.CreateGetMethodHandler(handler.ListMessageEdits))
+    server.AddRoute("/api/chat/react", base_handlers.CreatePostMethodHandler(handler.AddReaction))
+    server.AddRoute("/api/chat/unreact", base_handlers.CreatePostMethodHandler(handler.RemoveReaction))
+    server.AddRoute("/api/chat/inbox", base_handlers.CreateGetMethodHandler(handler.GetInbox))
+    server.AddRoute("/api/chat/mark_read", base_handlers.CreatePostMethodHandler(handler.MarkChatRead))
+    // The socket streams private messages, so it is only served when
+    // connections can be authenticated.
+    if handler.authenticator != nil {
+        server.AddRoute("/api/chat/ws", handler.ServeSocket)
+    } else {
+        log.Printf("REALTIME_TOKEN_SECRET is not set, /api/chat/ws is disabled")
+    }
+    server.AddRoute("/api/chat/create_group", base_handlers.CreatePostMethodHandler(handler.CreateGroupChat))
+    server.AddRoute("/api/chat/add_participant", base_handlers.CreatePostMethodHandler(handler.AddParticipant))
+    server.AddRoute("/api/chat/remove_participant", base_handlers.CreatePostMethodHandler(handler.RemoveParticipant))
+    server.AddRoute("/api/chat/set_role", base_handlers.CreatePostMethodHandler(handler.SetParticipantRole))
+    server.AddRoute("/api/chat/rename", base_handlers.CreatePostMethodHandler(handler.RenameChat))
+    server.AddRoute("/api/chat/leave", base_handlers.CreatePostMethodHandler(handler.LeaveChat))
 }
 
 // CreateChat handles the creation of a new chat between two users.
@@ -54,7 +99,7 @@ func (handler *ChatHandler) CreateChat(request request_model.CreateChatRequest)
         return nil, err
     }
 
-    return request_model.NewBaseChatData(chat.ChatId, chat.CreatedAt), nil
+    return request_model.NewBaseChatData(chat), nil
 }
 
 // GetChatById retrieves a chat by its ID
@@ -77,12 +122,12 @@ func (handler *ChatHandler) GetChatById(request request_model.GetChatByIdRequest
         return nil, errors.New("chat does not exist")
     }
 
-    return request_model.NewBaseChatData(chat.ChatId, chat.CreatedAt), nil
+    return request_model.NewBaseChatData(chat), nil
 }
 
-// ListUsersFromChat retrieves the usernames of the first two users in a chat.
+// ListUsersFromChat retrieves the participants of a chat.
 //	@Summary		List users from chat
-//	@Description	Retrieves the usernames of the first two users in a chat.
+//	@Description	Retrieves the chat with all its participants and their roles.
 //	@Tags			chat
 //	@Accept			json
 //	@Produce		json
@@ -93,22 +138,12 @@ func (handler *ChatHandler) GetChatById(request request_model.GetChatByIdRequest
 //	@Failure		500		{object}	map[string]string	"Internal Server Error"
 //  @Router			/api/chat/list_users [get]
 func (handler *ChatHandler) ListUsersFromChat(request request_model.ListUsersFromChatRequest) (*request_model.ListUsersFromChatResponse, error) {
-    
-    users, err := handler.chatService.ListUsersFromChat(request.ChatId)
-    if err != nil {
-        log.Printf("Error listing users of chat: %s", err)
-        return nil, errors.New("could not find any user")
-    }
-   
-    return &request_model.ListUsersFromChatResponse{
-        Username1: users[0].Username,
-        Username2: users[1].Username,
-    }, nil
+    return handler.chatResponse(request.ChatId)
 }
 
-// ListChatsFromUser retrieves all chat IDs associated with a specific user.
+// ListChatsFromUser retrieves all chats associated with a specific user.
 //	@Summary		List chats from user
-//	@Description	Retrieves all chat IDs associated with a specific user.
+//	@Description	Retrieves all chats associated with a specific user, with the number of unread messages of each.
 //	@Tags			chat
 //	@Accept			json
 //	@Produce		json
@@ -125,18 +160,19 @@ func (handler *ChatHandler) ListChatsFromUser(request request_model.ListChatsFro
         log.Printf("Error listing chats of a user: %s", err)
         return nil, errors.New("error listing chats of user")
     }
-    chatIds := make([]int32, len(chats))
-    for i, chat := range chats {
-        chatIds[i] = chat.ChatId
+
+    unread, err := handler.chatService.CountUnread(request.Username)
+    if err != nil {
+        log.Printf("Error counting unread messages of a user: %s", err)
+        return nil, errors.New("error listing chats of user")
     }
-    return &request_model.ListChatsFromUserResponse{
-        ChatIds: chatIds,
-    }, nil
+
+    return request_model.NewListChatsFromUserResponse(chats, unread), nil
 }
 
 // AddMessageToChat adds a message to a chat and returns the message details.
 //	@Summary		Add message to chat
-//	@Description	Adds a message to a chat and returns the message details.
+//	@Description	Adds a message to a chat and returns the message details. The message can share a song, artist or playlist through its attachment.
 //	@Tags			chat
 //	@Accept			json
 //	@Produce		json
@@ -147,28 +183,44 @@ func (handler *ChatHandler) ListChatsFromUser(request request_model.ListChatsFro
 //	@Failure		500		{object}	map[string]string	"Internal Server Error"
 //  @Router			/api/chat/add_message [post]
 func (handler *ChatHandler) AddMessageToChat(request request_model.AddMessageToChatRequest) (*request_model.AddMessageToChatResponse, error) {
-    message, err := handler.chatService.AddMessageToChatAndReturn(request.ChatId, request.AuthorId, request.Message)
+    var attachment *model.Attachment
+    if request.Attachment != nil {
+        var err error
+        attachment, err = request.Attachment.ToAttachment()
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    message, err := handler.chatService.AddMessageToChatAndReturn(request.ChatId, request.AuthorId, request.Message, attachment)
     if err != nil {
         log.Printf("Error adding message to chat: %s", err)
-        return nil, errors.New("could not add message to chat")
+        return nil, err
     }
 
-    return request_model.NewAddMessageToChatResponse(
+    response := request_model.NewAddMessageToChatResponse(
         message.MessageId,
         message.AuthorId,
         message.ChatId,
         message.SentAt,
-    ), nil
+    )
+    response.Attachment = request_model.NewAttachmentResponse(message.Attachment)
+
+    return response, nil
 }
 
 // ListChatMessages retrieves messages from a chat with a specified limit.
 //	@Summary		List messages from chat
-//	@Description	Retrieves messages from a chat with a specified limit.
+//	@Description	Retrieves a page of the messages of a chat the user takes part in. Without cursors the newest messages are returned; before returns the messages older than a message and after the ones newer than it. Use oldest_message_id as the before of the previous page and newest_message_id as the after of the next one.
 //	@Tags			chat
 //	@Accept			json
 //	@Produce		json
 //	@Param			chat_id	query		int32	true	"ID of the chat to list messages from"
-//	@Param			limit	query		int32	false	"Number of messages to retrieve (default is 10)"
+//	@Param			username	query	string	true	"User reading the chat"
+//	@Param			limit	query		int32	false	"Number of messages to retrieve (default is 10, at most 100)"
+//	@Param			before	query		int32	false	"Only messages older than this message ID"
+//	@Param			after	query		int32	false	"Only messages newer than this message ID"
+//	@Param			order	query		string	false	"Order of the page: newest (default) or oldest first"
 //	@Success		200		{object}	request_model.ListMessagesFromChatResponse
 //	@Failure		400		{object}	map[string]string	"Invalid Input"
 //	@Failure		404		{object}	map[string]string	"Chat Not Found"
@@ -179,11 +231,335 @@ func (handler *ChatHandler) ListChatMessages(request request_model.ListMessagesF
     if limit <= 0 {
         limit = 10
     }
-    messages, err := handler.chatService.ListChatMessages(request.ChatId, limit)    
+    page, err := handler.chatService.ListChatMessages(request.ChatId, request.Username, &model.ChatHistoryQuery{
+        Before: request.Before,
+        After: request.After,
+        Limit: limit,
+        Order: request.Order,
+    })
     if err != nil {
         log.Printf("Error listing messages from chat: %s", err)
+        return nil, err
+    }
+
+    states, err := handler.chatService.ListReadStates(request.ChatId, request.Username)
+    if err != nil {
+        log.Printf("Error listing read states of chat: %s", err)
+        return nil, errors.New("could not list messages from chat")
+    }
+
+    reactions, err := handler.chatService.ListReactions(page.Messages)
+    if err != nil {
+        log.Printf("Error listing reactions of chat messages: %s", err)
         return nil, errors.New("could not list messages from chat")
     }
 
-    return request_model.MapsToMessagesFromChat(messages), nil
+    return request_model.MapsToMessagesFromChat(request.ChatId, page, states, reactions), nil
+}
+
+// EditChatMessage replaces the text of a message.
+//	@Summary		Edit chat message
+//	@Description	Replaces the text of a message. Only its author can edit it; the previous text is kept in the history of the message.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.EditChatMessageRequest	true	"Message, its author and the new text"
+//	@Success		200		{object}	request_model.EditChatMessageResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/edit_message [post]
+func (handler *ChatHandler) EditChatMessage(request request_model.EditChatMessageRequest) (*request_model.EditChatMessageResponse, error) {
+    message, err := handler.chatService.EditMessage(request.MessageId, request.Username, request.Message)
+    if err != nil {
+        log.Printf("Error editing chat message: %s", err)
+        return nil, err
+    }
+
+    return request_model.NewEditChatMessageResponse(message), nil
+}
+
+// DeleteChatMessage deletes a message for every participant.
+//	@Summary		Delete chat message
+//	@Description	Deletes a message for every participant of the chat. Only its author can delete it. A tombstone is left in its place.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.DeleteChatMessageRequest	true	"Message and its author"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/delete_message [post]
+func (handler *ChatHandler) DeleteChatMessage(request request_model.DeleteChatMessageRequest) (*request_model.SuccessCreationResponse, error) {
+    if err := handler.chatService.DeleteMessage(request.MessageId, request.Username); err != nil {
+        log.Printf("Error deleting chat message: %s", err)
+        return nil, err
+    }
+
+    return request_model.NewSuccessCreationResponse("Message deleted"), nil
+}
+
+// ListMessageEdits lists the previous versions of a message.
+//	@Summary		Chat message history
+//	@Description	Lists the previous versions of an edited message, oldest first. Only participants of the chat can see it.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			message_id	query		int32	true	"ID of the message"
+//	@Param			username	query		string	true	"Username of a participant of the chat"
+//	@Success		200			{object}	request_model.ListMessageEditsResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/message_history [get]
+func (handler *ChatHandler) ListMessageEdits(request request_model.ListMessageEditsRequest) (*request_model.ListMessageEditsResponse, error) {
+    edits, err := handler.chatService.ListMessageEdits(request.MessageId, request.Username)
+    if err != nil {
+        log.Printf("Error listing edits of chat message: %s", err)
+        return nil, err
+    }
+
+    return request_model.NewListMessageEditsResponse(request.MessageId, edits), nil
+}
+
+// AddReaction reacts to a message with an emoji.
+//	@Summary		React to chat message
+//	@Description	Reacts to a message with an emoji on behalf of a participant and returns the reactions of the message.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.ChatReactionRequest	true	"Message, participant and emoji"
+//	@Success		200		{object}	request_model.ChatReactionsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/react [post]
+func (handler *ChatHandler) AddReaction(request request_model.ChatReactionRequest) (*request_model.ChatReactionsResponse, error) {
+    return handler.react(request, true)
+}
+
+// RemoveReaction removes a reaction to a message.
+//	@Summary		Remove chat message reaction
+//	@Description	Removes the reaction of a participant to a message and returns the reactions of the message.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.ChatReactionRequest	true	"Message, participant and emoji"
+//	@Success		200		{object}	request_model.ChatReactionsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/unreact [post]
+func (handler *ChatHandler) RemoveReaction(request request_model.ChatReactionRequest) (*request_model.ChatReactionsResponse, error) {
+    return handler.react(request, false)
+}
+
+func (handler *ChatHandler) react(request request_model.ChatReactionRequest, add bool) (*request_model.ChatReactionsResponse, error) {
+    reactions, err := handler.chatService.React(request.MessageId, request.Username, request.Emoji, add)
+    if err != nil {
+        log.Printf("Error reacting to chat message: %s", err)
+        return nil, err
+    }
+
+    return &request_model.ChatReactionsResponse{
+        MessageId: request.MessageId,
+        Reactions: request_model.NewChatReactionResponses(reactions),
+    }, nil
+}
+
+// SearchChatMessages runs a full text search over the messages of a user.
+//	@Summary		Search chat messages
+//	@Description	Searches the messages of the chats the user participates in, or of one of them when chat_id is given. Supports quoted phrases, OR and -excluded words. The best matches come first.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username of the user searching"
+//	@Param			q			query		string	true	"Text to search for"
+//	@Param			chat_id		query		int32	false	"Only search this chat"
+//	@Param			limit		query		int32	false	"Number of results to retrieve (default is 20, at most 100)"
+//	@Success		200			{object}	request_model.SearchChatMessagesResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/search_messages [get]
+func (handler *ChatHandler) SearchChatMessages(request request_model.SearchChatMessagesRequest) (*request_model.SearchChatMessagesResponse, error) {
+    results, err := handler.chatService.SearchMessages(request.Username, request.Query, request.ChatId, request.Limit)
+    if err != nil {
+        log.Printf("Error searching chat messages: %s", err)
+        return nil, err
+    }
+
+    return request_model.NewSearchChatMessagesResponse(results), nil
+}
+
+// MarkChatRead marks a chat as read by a participant.
+//	@Summary		Mark chat as read
+//	@Description	Marks the chat as read by the user up to message_id, or up to the newest message when message_id is omitted. The read marker never moves back.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.MarkChatReadRequest	true	"Chat, user and last message read"
+//	@Success		200		{object}	request_model.MarkChatReadResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/mark_read [post]
+func (handler *ChatHandler) MarkChatRead(request request_model.MarkChatReadRequest) (*request_model.MarkChatReadResponse, error) {
+    lastRead, err := handler.chatService.MarkRead(request.ChatId, request.Username, request.MessageId)
+    if err != nil {
+        return nil, err
+    }
+
+    return &request_model.MarkChatReadResponse{
+        ChatId: request.ChatId,
+        LastReadMessageId: lastRead,
+    }, nil
+}
+
+// CreateGroupChat creates a named chat with several participants.
+//	@Summary		Create a group chat
+//	@Description	Creates a named chat between the user, who becomes its admin, and the given participants.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.CreateGroupChatRequest	true	"Creator, name and participants of the chat"
+//	@Success		200		{object}	request_model.ListUsersFromChatResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/create_group [post]
+func (handler *ChatHandler) CreateGroupChat(request request_model.CreateGroupChatRequest) (*request_model.ListUsersFromChatResponse, error) {
+    chat, err := handler.chatService.CreateGroupChat(request.Username, request.Name, request.Participants)
+    if err != nil {
+        log.Printf("Error creating group chat: %s", err)
+        return nil, err
+    }
+
+    return handler.chatResponse(chat.ChatId)
+}
+
+// AddParticipant adds a user to a group chat.
+//	@Summary		Add participant to group chat
+//	@Description	Adds the target user to a group chat. Only admins of the chat can add participants.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.ChatParticipantRequest	true	"Chat, admin and user to add"
+//	@Success		200		{object}	request_model.ListUsersFromChatResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/add_participant [post]
+func (handler *ChatHandler) AddParticipant(request request_model.ChatParticipantRequest) (*request_model.ListUsersFromChatResponse, error) {
+    if err := handler.chatService.AddParticipant(request.ChatId, request.Username, request.Target); err != nil {
+        return nil, err
+    }
+
+    return handler.chatResponse(request.ChatId)
+}
+
+// RemoveParticipant removes a user from a group chat.
+//	@Summary		Remove participant from group chat
+//	@Description	Removes the target user from a group chat. Only admins of the chat can remove participants.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.ChatParticipantRequest	true	"Chat, admin and user to remove"
+//	@Success		200		{object}	request_model.ListUsersFromChatResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/remove_participant [post]
+func (handler *ChatHandler) RemoveParticipant(request request_model.ChatParticipantRequest) (*request_model.ListUsersFromChatResponse, error) {
+    if err := handler.chatService.RemoveParticipant(request.ChatId, request.Username, request.Target); err != nil {
+        return nil, err
+    }
+
+    return handler.chatResponse(request.ChatId)
+}
+
+// SetParticipantRole changes the role of a participant of a group chat.
+//	@Summary		Set role of participant
+//	@Description	Makes the target user an admin or a member of a group chat. Only admins can change roles, and the chat always keeps one admin.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.SetChatRoleRequest	true	"Chat, admin, user and role (admin or member)"
+//	@Success		200		{object}	request_model.ListUsersFromChatResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/set_role [post]
+func (handler *ChatHandler) SetParticipantRole(request request_model.SetChatRoleRequest) (*request_model.ListUsersFromChatResponse, error) {
+    if err := handler.chatService.SetRole(request.ChatId, request.Username, request.Target, request.Role); err != nil {
+        return nil, err
+    }
+
+    return handler.chatResponse(request.ChatId)
+}
+
+// RenameChat changes the name of a group chat.
+//	@Summary		Rename group chat
+//	@Description	Changes the name of a group chat. Only admins of the chat can rename it.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.RenameChatRequest	true	"Chat, admin and new name"
+//	@Success		200		{object}	request_model.ListUsersFromChatResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/rename [post]
+func (handler *ChatHandler) RenameChat(request request_model.RenameChatRequest) (*request_model.ListUsersFromChatResponse, error) {
+    if err := handler.chatService.RenameGroup(request.ChatId, request.Username, request.Name); err != nil {
+        return nil, err
+    }
+
+    return handler.chatResponse(request.ChatId)
+}
+
+// LeaveChat takes the user out of a group chat.
+//	@Summary		Leave group chat
+//	@Description	Takes the user out of a group chat. When the last admin leaves, the oldest participant becomes admin; when the last participant leaves, the chat is deleted.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		request_model.LeaveChatRequest	true	"Chat and user leaving it"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/leave [post]
+func (handler *ChatHandler) LeaveChat(request request_model.LeaveChatRequest) (*request_model.SuccessCreationResponse, error) {
+    if err := handler.chatService.LeaveChat(request.ChatId, request.Username); err != nil {
+        return nil, err
+    }
+
+    return request_model.NewSuccessCreationResponse("Left chat"), nil
+}
+
+func (handler *ChatHandler) chatResponse(chatId int32) (*request_model.ListUsersFromChatResponse, error) {
+    chat, err := handler.chatService.GetChatById(chatId)
+    if err != nil {
+        return nil, err
+    }
+
+    members, err := handler.chatService.ListMembers(chatId)
+    if err != nil {
+        log.Printf("Error listing users of chat: %s", err)
+        return nil, errors.New("could not find any user")
+    }
+
+    return request_model.NewListUsersFromChatResponse(chat, members), nil
+}
+
+// GetInbox lists the chats of a user as shown in their inbox.
+//	@Summary		Chat inbox
+//	@Description	Lists the chats of the user, most recently active first, each with its participants, a preview of its newest message and the number of unread messages.
+//	@Tags			chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username of the owner of the inbox"
+//	@Param			limit		query		int32	false	"Number of chats to retrieve (default is 20)"
+//	@Param			offset		query		int32	false	"Number of chats to skip (default is 0)"
+//	@Success		200			{object}	request_model.GetInboxResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//  @Router			/api/chat/inbox [get]
+func (handler *ChatHandler) GetInbox(request request_model.GetInboxRequest) (*request_model.GetInboxResponse, error) {
+    entries, err := handler.chatService.GetInbox(request.Username, request.Limit, request.Offset)
+    if err != nil {
+        log.Printf("Error listing inbox of a user: %s", err)
+        return nil, err
+    }
+
+    return request_model.NewGetInboxResponse(entries), nil
 }
\ No newline at end of file
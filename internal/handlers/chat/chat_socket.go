@@ -0,0 +1,194 @@
+package chat_handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	request_model "symphony-api/internal/handlers/model"
+	"symphony-api/internal/realtime"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	SOCKET_PING_INTERVAL   = 25 * time.Second
+	SOCKET_READ_TIMEOUT    = 60 * time.Second
+	SOCKET_WRITE_TIMEOUT   = 10 * time.Second
+	SOCKET_MAX_FRAME_BYTES = 4096
+)
+
+// ServeSocket upgrades the request to a WebSocket that streams the events of
+// the chats of the user the stream token was issued to. Pages of origins that
+// are not allowed can not open it (see realtime.Authenticator).
+//
+// The connection starts subscribed to every chat the user takes part in.
+// Clients send JSON commands: {"action":"subscribe","chat_id":1} and
+// {"action":"unsubscribe","chat_id":1} change the subscriptions (only
+// participants can subscribe), {"action":"ping"} is answered with
+// {"type":"pong"}. The server sends {"type":"ping"} every 25 seconds and
+// closes connections that stay silent for a minute, so clients must answer
+// with {"action":"pong"}. Connections that do not read their events fast
+// enough are closed with {"type":"error"}.
+//
+//	@Summary		Chat WebSocket
+//	@Description	Upgrades to a WebSocket that delivers the messages of the chats of the user as soon as they are sent.
+//	@Tags			chat
+//	@Param			access_token	query	string	false	"Stream token, when the Authorization header can not be set"
+//	@Param			Authorization	header	string	false	"Bearer stream token"
+//	@Success		101
+//	@Failure		401	{object}	map[string]string	"Unauthorized"
+//	@Failure		403	{object}	map[string]string	"Forbidden"
+//	@Router			/api/chat/ws [get]
+func (handler *ChatHandler) ServeSocket(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.authenticator.Authenticate(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := handler.userRepository.GetById(int64(userId))
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	username := user.Username
+
+	chats, err := handler.chatService.ListChatsByUser(username)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chatIds := make([]int32, 0, len(chats))
+	for _, chat := range chats {
+		chatIds = append(chatIds, chat.ChatId)
+	}
+
+	server := websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			return handler.authenticator.CheckOrigin(r)
+		},
+		Handler: func(conn *websocket.Conn) {
+			handler.runSocket(conn, username, chatIds)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+func (handler *ChatHandler) runSocket(conn *websocket.Conn, username string, chatIds []int32) {
+	defer conn.Close()
+	conn.MaxPayloadBytes = SOCKET_MAX_FRAME_BYTES
+
+	subscriber := handler.hub.Subscribe()
+	defer handler.hub.Unsubscribe(subscriber)
+
+	for _, chatId := range chatIds {
+		handler.hub.Join(subscriber, realtime.ChatTopic(chatId))
+	}
+
+	if err := send(conn, &request_model.ChatSocketReply{Type: "ready", Chats: chatIds}); err != nil {
+		return
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		handler.readCommands(conn, username, subscriber)
+	}()
+
+	ticker := time.NewTicker(SOCKET_PING_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-subscriber.Events():
+			if !ok {
+				if subscriber.Err() != nil {
+					send(conn, &request_model.ChatSocketReply{Type: "error", Error: subscriber.Err().Error()})
+				}
+				return
+			}
+			if err := send(conn, event); err != nil {
+				return
+			}
+			if removedFromChat(event, username) {
+				handler.hub.Leave(subscriber, event.Topic)
+			}
+		case <-ticker.C:
+			if err := send(conn, &request_model.ChatSocketReply{Type: request_model.CHAT_SOCKET_PING}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readCommands handles the commands of the client until the connection is
+// closed or stays silent for longer than SOCKET_READ_TIMEOUT.
+func (handler *ChatHandler) readCommands(conn *websocket.Conn, username string, subscriber *realtime.Subscriber) {
+	for {
+		conn.SetReadDeadline(time.Now().Add(SOCKET_READ_TIMEOUT))
+
+		var command request_model.ChatSocketCommand
+		if err := websocket.JSON.Receive(conn, &command); err != nil {
+			return
+		}
+
+		reply := handler.handleCommand(username, subscriber, &command)
+		if reply == nil {
+			continue
+		}
+		if err := send(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (handler *ChatHandler) handleCommand(username string, subscriber *realtime.Subscriber, command *request_model.ChatSocketCommand) *request_model.ChatSocketReply {
+	switch command.Action {
+	case request_model.CHAT_SOCKET_PONG:
+		return nil
+	case request_model.CHAT_SOCKET_PING:
+		return &request_model.ChatSocketReply{Type: request_model.CHAT_SOCKET_PONG}
+	case request_model.CHAT_SOCKET_SUBSCRIBE:
+		isParticipant, err := handler.chatService.IsParticipant(command.ChatId, username)
+		if err != nil {
+			log.Printf("Error checking participants of chat %d: %s", command.ChatId, err)
+			return &request_model.ChatSocketReply{Type: "error", ChatId: command.ChatId, Error: "could not subscribe to chat"}
+		}
+		if !isParticipant {
+			return &request_model.ChatSocketReply{Type: "error", ChatId: command.ChatId, Error: "user is not part of the chat"}
+		}
+		handler.hub.Join(subscriber, realtime.ChatTopic(command.ChatId))
+		return &request_model.ChatSocketReply{Type: "subscribed", ChatId: command.ChatId}
+	case request_model.CHAT_SOCKET_UNSUBSCRIBE:
+		handler.hub.Leave(subscriber, realtime.ChatTopic(command.ChatId))
+		return &request_model.ChatSocketReply{Type: "unsubscribed", ChatId: command.ChatId}
+	default:
+		return &request_model.ChatSocketReply{Type: "error", Error: "unknown action: " + command.Action}
+	}
+}
+
+// removedFromChat tells whether the event takes the user out of its chat, in
+// which case the connection must stop receiving the events of that chat.
+func removedFromChat(event *realtime.Event, username string) bool {
+	if event.Type != realtime.EVENT_CHAT_MEMBER_REMOVED {
+		return false
+	}
+
+	var member struct {
+		Username string `json:"username"`
+	}
+	if err := json.Unmarshal(event.Data, &member); err != nil {
+		return false
+	}
+
+	return member.Username == username
+}
+
+func send(conn *websocket.Conn, frame any) error {
+	conn.SetWriteDeadline(time.Now().Add(SOCKET_WRITE_TIMEOUT))
+	return websocket.JSON.Send(conn, frame)
+}
@@ -10,21 +10,33 @@ import (
 	"symphony-api/internal/persistence/repository"
 	"symphony-api/internal/persistence/service"
 	"symphony-api/internal/server"
+	"symphony-api/internal/webhook"
 )
 
 type CommunityHandler struct {
 	communityRepository *repository.CommunityRepository
 	communityService *service.CommunityService
+	privacyService *service.PrivacyService
+	webhookService *service.WebhookService
 }
 
-func NewCommunityHandler(connection postgres.PostgreConnection, neo4jConnection neo4j.Neo4jConnection) *CommunityHandler {
+func NewCommunityHandler(
+	connection postgres.PostgreConnection,
+	neo4jConnection neo4j.Neo4jConnection,
+	webhookService *service.WebhookService,
+) *CommunityHandler {
 	communityRepository := repository.NewCommunityRepository(connection)
+	userRepository := repository.NewUserRepository(connection, neo4jConnection)
 	return &CommunityHandler{
 		communityRepository: communityRepository,
 		communityService: service.NewCommunityService(
 			communityRepository,
-			repository.NewUserRepository(connection, neo4jConnection),
+			userRepository,
+			service.NewNotificationService(repository.NewNotificationRepository(connection), userRepository),
+			webhookService,
 		),
+		privacyService: service.NewPrivacyService(userRepository),
+		webhookService: webhookService,
 	}
 }
 
@@ -54,6 +66,10 @@ func (handler *CommunityHandler) CreateCommunity(request request_model.CreateCom
 		return nil, errors.New("error creating community")
 	}
 
+	handler.webhookService.Publish(webhook.EVENT_COMMUNITY_CREATED, map[string]any{
+		"community_name": request.CommunityName,
+	})
+
 	return request_model.NewSuccessCreationResponse("Successfully created community"), nil
 }
 
@@ -98,11 +114,12 @@ func (handler *CommunityHandler) AddUserToCommunity(request request_model.AddUse
 
 // List all users that belongs to a community
 //	@Summary		List user of a community
-//	@Description	List all user data of a community
+//	@Description	List all user data of a community, leaving out members whose profile the viewer can not see
 //	@Tags			community
 //	@Accept			json
 //	@Produce		json
 //	@Param			community_name	query		string	true	"Community Name"1'
+//	@Param			viewer			query		string	false	"User looking at the list"
 //	@Success		200		{object}	request_model.ListUsersOfCommunityResponse
 //	@Failure		400		{object}	map[string]string	"Invalid Input"
 //	@Failure		500		{object}	map[string]string	"Internal Server Error"
@@ -110,13 +127,23 @@ func (handler *CommunityHandler) AddUserToCommunity(request request_model.AddUse
 func (handler *CommunityHandler) ListUsersFromCommunity(request request_model.ListUsersOfCommunityRequest) (*request_model.ListUsersOfCommunityResponse, error) {
 	users, err := handler.communityService.ListUsersFromCommunity(request.CommunityName)
 
-	usersResponse := make([]*request_model.UserResponse, 0)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, user := range users {
-		usersResponse = append(usersResponse, request_model.NewUserResponse(user))
+	users, err = handler.privacyService.FilterVisibleUsers(request.Viewer, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := handler.privacyService.ResolveViews(request.Viewer, users)
+
+	if err != nil {
+		return nil, err
 	}
 
 	return &request_model.ListUsersOfCommunityResponse{
-		Users: usersResponse,
-	}, err
+		Users: request_model.NewUserResponses(users, views),
+	}, nil
 }
\ No newline at end of file
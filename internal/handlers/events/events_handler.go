@@ -0,0 +1,207 @@
+package events_handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"symphony-api/internal/persistence/connectors/neo4j"
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/persistence/service"
+	"symphony-api/internal/realtime"
+	"symphony-api/internal/server"
+	"time"
+)
+
+const (
+	EVENTS_KEEPALIVE_INTERVAL = 20 * time.Second
+	EVENTS_WRITE_TIMEOUT      = 10 * time.Second
+	EVENTS_RETRY_MILLISECONDS = 3000
+)
+
+type EventsHandler struct {
+	userRepository *repository.UserRepository
+	eventService   *service.EventService
+	hub            *realtime.Hub
+	authenticator  *realtime.Authenticator
+}
+
+func NewEventsHandler(
+	connection postgres.PostgreConnection,
+	neo4jConnection neo4j.Neo4jConnection,
+	eventService *service.EventService,
+	hub *realtime.Hub,
+	authenticator *realtime.Authenticator,
+) *EventsHandler {
+	return &EventsHandler{
+		userRepository: repository.NewUserRepository(connection, neo4jConnection),
+		eventService:   eventService,
+		hub:            hub,
+		authenticator:  authenticator,
+	}
+}
+
+func (handler *EventsHandler) AddRoutes(server server.Server) {
+	// The stream carries private data, so it is only served when
+	// connections can be authenticated.
+	if handler.authenticator == nil {
+		log.Printf("REALTIME_TOKEN_SECRET is not set, /api/events is disabled")
+		return
+	}
+	server.AddRoute("/api/events", handler.ServeEvents)
+}
+
+// ServeEvents streams the events addressed to the user the stream token was
+// issued to as Server-Sent Events. Pages of origins that are not allowed can
+// not open it (see realtime.Authenticator).
+//
+// Every event has an id. New streams start with the live events. When the
+// connection drops, browsers reconnect on their own sending the id of the
+// last event they got in the Last-Event-ID header, and the events they
+// missed are replayed from the event log before the live ones. Clients that
+// can not set headers may pass last_event_id instead. When the missed events
+// were pruned or are too many, a stream.reset event tells the client to
+// reload its state instead. A comment is sent every 20 seconds so idle
+// connections stay open.
+//
+// Resuming is best-effort: ids are taken when events are stored, so with
+// concurrent writers an event may get a smaller id than one sent before it.
+// If the connection drops between the two, the replay starts after the
+// greater id and the other event is not sent again.
+//
+//	@Summary		Event stream
+//	@Description	Streams the events of the user of the stream token (friend requests, mentions, chat messages...) as Server-Sent Events, resuming after Last-Event-ID.
+//	@Tags			events
+//	@Produce		text/event-stream
+//	@Param			access_token	query	string	false	"Stream token, when the Authorization header can not be set"
+//	@Param			Authorization	header	string	false	"Bearer stream token"
+//	@Param			last_event_id	query	int64	false	"Resume after this event when the Last-Event-ID header is not sent"
+//	@Param			Last-Event-ID	header	int64	false	"Resume after this event"
+//	@Success		200
+//	@Failure		400	{object}	map[string]string	"Invalid Input"
+//	@Failure		401	{object}	map[string]string	"Unauthorized"
+//	@Failure		403	{object}	map[string]string	"Forbidden"
+//	@Failure		500	{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/events [get]
+func (handler *EventsHandler) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := handler.authenticator.CheckOrigin(r); err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	userId, err := handler.authenticator.Authenticate(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lastEventId, err := parseLastEventId(r)
+	if err != nil {
+		http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+		return
+	}
+
+	user, err := handler.userRepository.GetById(int64(userId))
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Subscribe before reading the log, so no event falls between the replay
+	// and the live ones. Live events that were replayed are skipped.
+	subscriber := handler.hub.Subscribe()
+	defer handler.hub.Unsubscribe(subscriber)
+	handler.hub.Join(subscriber, realtime.UserTopic(user.UserId))
+
+	missed, resumed, err := handler.eventService.Replay(user.UserId, lastEventId)
+	if err != nil {
+		log.Printf("Error replaying events of user %d: %v", user.UserId, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(frame func() error) bool {
+		controller.SetWriteDeadline(time.Now().Add(EVENTS_WRITE_TIMEOUT))
+		if err := frame(); err != nil {
+			return false
+		}
+		return controller.Flush() == nil
+	}
+
+	if !write(func() error { return writeRetry(w) }) {
+		return
+	}
+	if !resumed {
+		reset := &realtime.Event{Type: realtime.EVENT_STREAM_RESET, Data: []byte("{}")}
+		if !write(func() error { return realtime.WriteSSE(w, reset) }) {
+			return
+		}
+	}
+	// Ids are taken when events are stored, not when they are published, so
+	// live events may come out of order. Only the ids sent by the replay are
+	// known to be duplicates; comparing with the last id would drop an event
+	// published after one with a greater id.
+	replayed := make(map[int64]bool, len(missed))
+	for _, event := range missed {
+		if !write(func() error { return realtime.WriteSSE(w, event) }) {
+			return
+		}
+		replayed[event.Id] = true
+	}
+
+	ticker := time.NewTicker(EVENTS_KEEPALIVE_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscriber.Events():
+			if !ok {
+				// Dropped for being too slow. The client reconnects and the
+				// events it lost are replayed from the log.
+				return
+			}
+			if replayed[event.Id] {
+				delete(replayed, event.Id)
+				continue
+			}
+			if !write(func() error { return realtime.WriteSSE(w, event) }) {
+				return
+			}
+		case <-ticker.C:
+			if !write(func() error { return realtime.WriteSSEComment(w, "keepalive") }) {
+				return
+			}
+		}
+	}
+}
+
+func parseLastEventId(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func writeRetry(w http.ResponseWriter) error {
+	_, err := w.Write([]byte("retry: " + strconv.Itoa(EVENTS_RETRY_MILLISECONDS) + "\n\n"))
+	return err
+}
@@ -0,0 +1,138 @@
+package media
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	base_handlers "symphony-api/internal/handlers/base"
+	request_model "symphony-api/internal/handlers/model"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/service"
+	"symphony-api/internal/persistence/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type MediaHandler struct {
+	service *service.MediaService
+}
+
+func NewMediaHandler(service *service.MediaService) *MediaHandler {
+	return &MediaHandler{service: service}
+}
+
+func (h *MediaHandler) AddRoutes(server interface {
+	AddRoute(pattern string, handler http.HandlerFunc)
+	AddGroup(pattern string, fn func(r chi.Router))
+}) {
+	server.AddRoute("/api/media/upload", h.Upload)
+	server.AddGroup("/media", func(r chi.Router) {
+		r.Get("/{key}", h.Serve)
+	})
+}
+
+// Upload stores an image sent by a client
+// @Summary Upload an image
+// @Description Upload a jpeg, png or gif image as the "file" field of a multipart form. The returned url can be used as url_foto of a post or image_url of a playlist.
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Image"
+// @Success 201 {object} request_model.MediaResponse
+// @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/media/upload [post]
+func (h *MediaHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	media, ok := UploadFromRequest(w, r, h.service)
+	if !ok {
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(request_model.NewMediaResponse(
+		media,
+		h.service.URL(media.Key),
+		h.service.URL(media.ThumbnailKey),
+	)); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Serve returns a stored image
+// @Summary Download an image
+// @Description Download an uploaded image or thumbnail. Keys are derived from the content, so responses can be cached forever.
+// @Tags media
+// @Produce image/jpeg,image/png,image/gif
+// @Param key path string true "Media key"
+// @Success 200 {file} binary
+// @Success 304 "Not Modified"
+// @Failure 404 {object} map[string]string
+// @Router /media/{key} [get]
+func (h *MediaHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	etag := `"` + key + `"`
+
+	// The object is opened even for conditional requests, so media that was
+	// deleted is reported as missing instead of as not modified.
+	reader, err := h.service.Open(context.Background(), key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) && !errors.Is(err, storage.ErrInvalidKey) {
+			log.Printf("Error opening media %s: %v", key, err)
+		}
+		http.Error(w, "Media not found", http.StatusNotFound)
+		return
+	}
+	defer reader.Close()
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", etag)
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", service.ContentTypeOfKey(key))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if _, err := io.Copy(w, reader); err != nil {
+		log.Printf("Error serving media %s: %v", key, err)
+	}
+}
+
+// UploadFromRequest reads the "file" field of a multipart request and stores
+// it. When it fails the error is already written to w and ok is false.
+func UploadFromRequest(w http.ResponseWriter, r *http.Request, mediaService *service.MediaService) (media *model.Media, ok bool) {
+	file, err := base_handlers.OpenMultipartFile(w, r, "file", mediaService.MaxBytes())
+	if err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return nil, false
+	}
+
+	media, err = mediaService.Upload(context.Background(), file)
+
+	var maxBytesError *http.MaxBytesError
+	switch {
+	case err == nil:
+		return media, true
+	case errors.Is(err, service.ErrMediaTooLarge), errors.As(err, &maxBytesError):
+		http.Error(w, "File is too large", http.StatusRequestEntityTooLarge)
+	case errors.Is(err, service.ErrUnsupportedMedia):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	default:
+		log.Printf("Error uploading media: %v", err)
+		http.Error(w, "Failed to upload file", http.StatusInternalServerError)
+	}
+	return nil, false
+}
@@ -0,0 +1,32 @@
+package request_model
+
+import (
+	"symphony-api/internal/persistence/model"
+	"time"
+)
+
+type GetAccountRequest struct{}
+
+type DeleteAccountRequest struct{}
+
+type RequestDataExportRequest struct{}
+
+type GetDataExportRequest struct {
+	ExportId int32  `schema:"export_id,required"`
+}
+
+type DataExportResponse struct {
+	ExportId    int32      `json:"export_id" binding:"required"`
+	Status      string     `json:"status" binding:"required"`
+	RequestedAt time.Time  `json:"requested_at" binding:"required"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func NewDataExportResponse(export *model.DataExport) *DataExportResponse {
+	return &DataExportResponse{
+		ExportId:    export.ExportId,
+		Status:      export.Status,
+		RequestedAt: export.RequestedAt,
+		CompletedAt: export.CompletedAt,
+	}
+}
@@ -0,0 +1,36 @@
+package request_model
+
+import (
+	"symphony-api/internal/persistence/model"
+)
+
+type AttachmentRequest struct {
+	Type string `json:"type" binding:"required"`
+	Id   string `json:"id" binding:"required"`
+}
+
+func (request *AttachmentRequest) ToAttachment() (*model.Attachment, error) {
+	return model.NewAttachment(request.Type, request.Id)
+}
+
+type AttachmentResponse struct {
+	Type       string `json:"type" binding:"required"`
+	Id         string `json:"id" binding:"required"`
+	Title      string `json:"title,omitempty"`
+	ArtistName string `json:"artist_name,omitempty"`
+	CoverUrl   string `json:"cover_url,omitempty"`
+}
+
+func NewAttachmentResponse(attachment *model.Attachment) *AttachmentResponse {
+	if attachment == nil {
+		return nil
+	}
+
+	return &AttachmentResponse{
+		Type:       attachment.Type,
+		Id:         attachment.Id,
+		Title:      attachment.Title,
+		ArtistName: attachment.ArtistName,
+		CoverUrl:   attachment.CoverUrl,
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 type BaseChatData struct {
 	ChatId int32 `json:"chat_id" binding:"required"`
+	Name string `json:"name,omitempty"`
+	IsGroup bool `json:"is_group"`
 	CreatedAt time.Time `json:"created_at" binding:"required"`
 }
 
@@ -23,23 +25,176 @@ type ListUsersFromChatRequest struct {
 	ChatId int32 `schema:"chat_id,required"`
 }
 
+type ChatParticipantResponse struct {
+	Username string `json:"username" binding:"required"`
+	Fullname string `json:"fullname"`
+	AvatarUrl string `json:"avatar_url,omitempty"`
+	Role string `json:"role" binding:"required"`
+}
+
 type ListUsersFromChatResponse struct {
-	Username1 string `json:"username1" binding:"required"`
-	Username2 string `json:"username2" binding:"required"`
+	*BaseChatData
+	Participants []*ChatParticipantResponse `json:"participants" binding:"required"`
+}
+
+func NewChatParticipantResponses(members []*model.ChatMember) []*ChatParticipantResponse {
+	participants := make([]*ChatParticipantResponse, 0, len(members))
+	for _, member := range members {
+		participants = append(participants, &ChatParticipantResponse{
+			Username: member.User.Username,
+			Fullname: member.User.Fullname,
+			AvatarUrl: member.User.AvatarUrl,
+			Role: member.Role,
+		})
+	}
+	return participants
+}
+
+func NewListUsersFromChatResponse(chat *model.Chat, members []*model.ChatMember) *ListUsersFromChatResponse {
+	return &ListUsersFromChatResponse{
+		BaseChatData: NewBaseChatData(chat),
+		Participants: NewChatParticipantResponses(members),
+	}
+}
+
+type CreateGroupChatRequest struct {
+	Username string `json:"username" binding:"required"`
+	Name string `json:"name" binding:"required"`
+	Participants []string `json:"participants" binding:"required"`
+}
+
+type ChatParticipantRequest struct {
+	ChatId int32 `json:"chat_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Target string `json:"target" binding:"required"`
+}
+
+type SetChatRoleRequest struct {
+	ChatId int32 `json:"chat_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Target string `json:"target" binding:"required"`
+	Role string `json:"role" binding:"required"`
+}
+
+type RenameChatRequest struct {
+	ChatId int32 `json:"chat_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+type LeaveChatRequest struct {
+	ChatId int32 `json:"chat_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
 }
 
 type ListChatsFromUserRequest struct {
 	Username string `schema:"username,required"`
 }
 
+type ChatSummaryResponse struct {
+	*BaseChatData
+	UnreadCount int64 `json:"unread_count"`
+}
+
 type ListChatsFromUserResponse struct {
 	ChatIds []int32 `json:"chat_ids" binding:"required"`
+	Chats []*ChatSummaryResponse `json:"chats" binding:"required"`
 }
 
-func NewBaseChatData(chatId int32, createdAt time.Time) *BaseChatData {
+func NewListChatsFromUserResponse(chats []*model.Chat, unread map[int32]int64) *ListChatsFromUserResponse {
+	chatIds := make([]int32, 0, len(chats))
+	summaries := make([]*ChatSummaryResponse, 0, len(chats))
+
+	for _, chat := range chats {
+		chatIds = append(chatIds, chat.ChatId)
+		summaries = append(summaries, &ChatSummaryResponse{
+			BaseChatData: NewBaseChatData(chat),
+			UnreadCount: unread[chat.ChatId],
+		})
+	}
+
+	return &ListChatsFromUserResponse{
+		ChatIds: chatIds,
+		Chats: summaries,
+	}
+}
+
+type GetInboxRequest struct {
+	Username string `schema:"username,required"`
+	Limit int32 `schema:"limit,default=20"`
+	Offset int32 `schema:"offset,default=0"`
+}
+
+type LastMessageResponse struct {
+	MessageId int32 `json:"message_id" binding:"required"`
+	Author string `json:"author"`
+	Preview string `json:"preview" binding:"required"`
+	Deleted bool `json:"deleted"`
+	SentAt time.Time `json:"sent_at" binding:"required"`
+}
+
+type InboxEntryResponse struct {
+	*BaseChatData
+	Participants []*ChatParticipantResponse `json:"participants" binding:"required"`
+	LastMessage *LastMessageResponse `json:"last_message"`
+	LastActivity time.Time `json:"last_activity" binding:"required"`
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type GetInboxResponse struct {
+	Chats []*InboxEntryResponse `json:"chats" binding:"required"`
+}
+
+func NewGetInboxResponse(entries []*model.ChatInboxEntry) *GetInboxResponse {
+	chats := make([]*InboxEntryResponse, 0, len(entries))
+
+	for _, entry := range entries {
+		chat := &InboxEntryResponse{
+			BaseChatData: NewBaseChatData(entry.Chat),
+			Participants: NewChatParticipantResponses(entry.Participants),
+			LastActivity: entry.LastActivity,
+			UnreadCount: entry.UnreadCount,
+		}
+		if entry.LastMessage != nil {
+			chat.LastMessage = &LastMessageResponse{
+				MessageId: entry.LastMessage.MessageId,
+				Author: entry.LastMessageAuthor,
+				Preview: model.MessagePreview(entry.LastMessage.Message),
+				Deleted: entry.LastMessage.IsDeleted(),
+				SentAt: entry.LastMessage.SentAt,
+			}
+		}
+		chats = append(chats, chat)
+	}
+
+	return &GetInboxResponse{
+		Chats: chats,
+	}
+}
+
+type MarkChatReadRequest struct {
+	ChatId int32 `json:"chat_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	MessageId int32 `json:"message_id"`
+}
+
+type MarkChatReadResponse struct {
+	ChatId int32 `json:"chat_id" binding:"required"`
+	LastReadMessageId int32 `json:"last_read_message_id"`
+}
+
+type ReadStateResponse struct {
+	Username string `json:"username" binding:"required"`
+	LastReadMessageId int32 `json:"last_read_message_id"`
+	LastReadAt *time.Time `json:"last_read_at,omitempty"`
+}
+
+func NewBaseChatData(chat *model.Chat) *BaseChatData {
 	return &BaseChatData{
-		ChatId: chatId,
-		CreatedAt: createdAt,
+		ChatId: chat.ChatId,
+		Name: chat.Name,
+		IsGroup: chat.IsGroup,
+		CreatedAt: chat.CreatedAt,
 	}
 }
 
@@ -47,6 +202,7 @@ type AddMessageToChatRequest struct {
 	ChatId  int32  `json:"chat_id" binding:"required"`
 	AuthorId int32  `json:"author_id" binding:"required"`
 	Message string `json:"message" binding:"required"`
+	Attachment *AttachmentRequest `json:"attachment,omitempty"`
 }
 
 type AddMessageToChatResponse struct {
@@ -54,6 +210,7 @@ type AddMessageToChatResponse struct {
 	AuthorId  int32     `json:"author_id" binding:"required"`
 	ChatId    int32     `json:"chat_id" binding:"required"`
 	SentAt    time.Time `json:"sent_at" binding:"required"`
+	Attachment *AttachmentResponse `json:"attachment,omitempty"`
 }
 
 func NewAddMessageToChatResponse(messageId, authorId, chatId int32, sentAt time.Time) *AddMessageToChatResponse {
@@ -67,31 +224,232 @@ func NewAddMessageToChatResponse(messageId, authorId, chatId int32, sentAt time.
 	
 type ListMessagesFromChatRequest struct {
 	ChatId int32 `schema:"chat_id,required"`
+	Username string `schema:"username,required"`
 	Limit int32 `schema:"limit,default=10"`
+	Before int32 `schema:"before,default=0"`
+	After int32 `schema:"after,default=0"`
+	Order string `schema:"order,default=newest"`
 }
 
 type MessagesFromChat struct {
+	MessageId int32 `json:"message_id" binding:"required"`
 	AuthorId int32     `json:"author_id" binding:"required"`
 	SentAt	time.Time `json:"sent_at" binding:"required"`
 	Message string    `json:"message" binding:"required"`
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+	Deleted bool `json:"deleted"`
+	Attachment *AttachmentResponse `json:"attachment,omitempty"`
+	ReadBy []string `json:"read_by" binding:"required"`
+	Reactions []*ChatReactionResponse `json:"reactions" binding:"required"`
 }
 
 type ListMessagesFromChatResponse struct {
 	ChatId  int32              `json:"chat_id" binding:"required"`
 	Messages []MessagesFromChat `json:"messages" binding:"required"`
+	ReadStates []*ReadStateResponse `json:"read_states" binding:"required"`
+	HasMore bool `json:"has_more"`
+	OldestMessageId int32 `json:"oldest_message_id,omitempty"`
+	NewestMessageId int32 `json:"newest_message_id,omitempty"`
 }
 
-func MapsToMessagesFromChat(message []*model.ChatMessage) *ListMessagesFromChatResponse {
-	messages := make([]MessagesFromChat, len(message))
-	for i, msg := range message {
+// MapsToMessagesFromChat builds a page of the messages of a chat together with
+// who has read and reacted to each of them and the cursors of the pages
+// around it.
+func MapsToMessagesFromChat(chatId int32, page *model.ChatHistoryPage, states []*model.ChatReadState, reactions map[int32][]*model.ChatReaction) *ListMessagesFromChatResponse {
+	messages := make([]MessagesFromChat, len(page.Messages))
+	for i, msg := range page.Messages {
 		messages[i] = MessagesFromChat{
+			MessageId: msg.MessageId,
 			AuthorId: msg.AuthorId,
 			SentAt:   msg.SentAt,
 			Message:  msg.Message,
+			Deleted: msg.IsDeleted(),
+			Attachment: NewAttachmentResponse(msg.Attachment),
+			ReadBy: model.ReadBy(msg, states),
+			Reactions: NewChatReactionResponses(reactions[msg.MessageId]),
+		}
+		if msg.IsEdited() {
+			editedAt := msg.EditedAt
+			messages[i].EditedAt = &editedAt
+		}
+	}
+
+	readStates := make([]*ReadStateResponse, 0, len(states))
+	for _, state := range states {
+		readState := &ReadStateResponse{
+			Username: state.Username,
+			LastReadMessageId: state.LastReadMessageId,
+		}
+		if !state.LastReadAt.IsZero() {
+			lastReadAt := state.LastReadAt
+			readState.LastReadAt = &lastReadAt
 		}
+		readStates = append(readStates, readState)
 	}
+
 	return &ListMessagesFromChatResponse{
-		ChatId:   message[0].ChatId,
+		ChatId:   chatId,
 		Messages: messages,
+		ReadStates: readStates,
+		HasMore: page.HasMore,
+		OldestMessageId: page.OldestMessageId(),
+		NewestMessageId: page.NewestMessageId(),
+	}
+}
+
+type ChatReactionResponse struct {
+	Emoji string `json:"emoji" binding:"required"`
+	Count int64 `json:"count" binding:"required"`
+	Usernames []string `json:"usernames" binding:"required"`
+}
+
+func NewChatReactionResponses(reactions []*model.ChatReaction) []*ChatReactionResponse {
+	responses := make([]*ChatReactionResponse, 0, len(reactions))
+	for _, reaction := range reactions {
+		responses = append(responses, &ChatReactionResponse{
+			Emoji: reaction.Emoji,
+			Count: reaction.Count,
+			Usernames: reaction.Usernames,
+		})
 	}
-}
\ No newline at end of file
+	return responses
+}
+
+type EditChatMessageRequest struct {
+	MessageId int32 `json:"message_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Message string `json:"message" binding:"required"`
+}
+
+type EditChatMessageResponse struct {
+	MessageId int32 `json:"message_id" binding:"required"`
+	ChatId int32 `json:"chat_id" binding:"required"`
+	Message string `json:"message" binding:"required"`
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+}
+
+func NewEditChatMessageResponse(message *model.ChatMessage) *EditChatMessageResponse {
+	response := &EditChatMessageResponse{
+		MessageId: message.MessageId,
+		ChatId: message.ChatId,
+		Message: message.Message,
+	}
+	if message.IsEdited() {
+		editedAt := message.EditedAt
+		response.EditedAt = &editedAt
+	}
+	return response
+}
+
+type DeleteChatMessageRequest struct {
+	MessageId int32 `json:"message_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+}
+
+type ListMessageEditsRequest struct {
+	MessageId int32 `schema:"message_id,required"`
+	Username string `schema:"username,required"`
+}
+
+type ChatMessageEditResponse struct {
+	Message string `json:"message" binding:"required"`
+	EditedAt time.Time `json:"edited_at" binding:"required"`
+}
+
+type ListMessageEditsResponse struct {
+	MessageId int32 `json:"message_id" binding:"required"`
+	Edits []*ChatMessageEditResponse `json:"edits" binding:"required"`
+}
+
+func NewListMessageEditsResponse(messageId int32, edits []*model.ChatMessageEdit) *ListMessageEditsResponse {
+	responses := make([]*ChatMessageEditResponse, 0, len(edits))
+	for _, edit := range edits {
+		responses = append(responses, &ChatMessageEditResponse{
+			Message: edit.Message,
+			EditedAt: edit.EditedAt,
+		})
+	}
+
+	return &ListMessageEditsResponse{
+		MessageId: messageId,
+		Edits: responses,
+	}
+}
+
+type ChatReactionRequest struct {
+	MessageId int32 `json:"message_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Emoji string `json:"emoji" binding:"required"`
+}
+
+type ChatReactionsResponse struct {
+	MessageId int32 `json:"message_id" binding:"required"`
+	Reactions []*ChatReactionResponse `json:"reactions" binding:"required"`
+}
+
+type SearchChatMessagesRequest struct {
+	Username string `schema:"username,required"`
+	Query string `schema:"q,required"`
+	ChatId int32 `schema:"chat_id,default=0"`
+	Limit int32 `schema:"limit,default=20"`
+}
+
+type ChatMessageSearchResultResponse struct {
+	MessageId int32 `json:"message_id" binding:"required"`
+	ChatId int32 `json:"chat_id" binding:"required"`
+	ChatName string `json:"chat_name,omitempty"`
+	IsGroup bool `json:"is_group"`
+	Author string `json:"author"`
+	Message string `json:"message" binding:"required"`
+	Headline string `json:"headline" binding:"required"`
+	Attachment *AttachmentResponse `json:"attachment,omitempty"`
+	SentAt time.Time `json:"sent_at" binding:"required"`
+}
+
+type SearchChatMessagesResponse struct {
+	Results []*ChatMessageSearchResultResponse `json:"results" binding:"required"`
+}
+
+func NewSearchChatMessagesResponse(results []*model.ChatMessageSearchResult) *SearchChatMessagesResponse {
+	response := make([]*ChatMessageSearchResultResponse, 0, len(results))
+
+	for _, result := range results {
+		response = append(response, &ChatMessageSearchResultResponse{
+			MessageId: result.Message.MessageId,
+			ChatId: result.Message.ChatId,
+			ChatName: result.ChatName,
+			IsGroup: result.IsGroup,
+			Author: result.Author,
+			Message: result.Message.Message,
+			Headline: result.Headline,
+			Attachment: NewAttachmentResponse(result.Message.Attachment),
+			SentAt: result.Message.SentAt,
+		})
+	}
+
+	return &SearchChatMessagesResponse{
+		Results: response,
+	}
+}
+
+const (
+	CHAT_SOCKET_SUBSCRIBE   = "subscribe"
+	CHAT_SOCKET_UNSUBSCRIBE = "unsubscribe"
+	CHAT_SOCKET_PING        = "ping"
+	CHAT_SOCKET_PONG        = "pong"
+)
+
+// ChatSocketCommand is a frame sent by the client over the chat WebSocket.
+type ChatSocketCommand struct {
+	Action string `json:"action"`
+	ChatId int32  `json:"chat_id,omitempty"`
+}
+
+// ChatSocketReply is a control frame sent by the server over the chat
+// WebSocket. Chat events are sent as they come from the hub.
+type ChatSocketReply struct {
+	Type   string  `json:"type"`
+	ChatId int32   `json:"chat_id,omitempty"`
+	Chats  []int32 `json:"chats,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
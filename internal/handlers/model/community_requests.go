@@ -25,6 +25,7 @@ type AddUserToCommunityRequest struct {
 
 type ListUsersOfCommunityRequest struct {
 	CommunityName string `schema:"community_name,required"`
+	Viewer string `schema:"viewer"`
 }
 
 type ListUsersOfCommunityResponse struct {
@@ -51,8 +52,14 @@ func NewBaseCommunityData(communityName string, description string) *BaseCommuni
 }
 
 func NewCommunityDataResponse(community *model.Community) *CommunityDataResponse {
+	// A description hidden by moderation is not shown, but the community stays visible.
+	description := community.Description
+	if community.Hidden {
+		description = ""
+	}
+
 	return &CommunityDataResponse{
-		BaseCommunityData: NewBaseCommunityData(community.CommunityName, community.Description),
+		BaseCommunityData: NewBaseCommunityData(community.CommunityName, description),
 		CreatedAt: community.CreatedAt,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,27 @@
+package request_model
+
+import (
+	"symphony-api/internal/persistence/model"
+)
+
+type MediaResponse struct {
+	Key          string `json:"key" binding:"required"`
+	Url          string `json:"url" binding:"required"`
+	ThumbnailUrl string `json:"thumbnail_url" binding:"required"`
+	ContentType  string `json:"content_type" binding:"required"`
+	Size         int64  `json:"size" binding:"required"`
+	Width        int    `json:"width" binding:"required"`
+	Height       int    `json:"height" binding:"required"`
+}
+
+func NewMediaResponse(media *model.Media, url string, thumbnailUrl string) *MediaResponse {
+	return &MediaResponse{
+		Key:          media.Key,
+		Url:          url,
+		ThumbnailUrl: thumbnailUrl,
+		ContentType:  media.ContentType,
+		Size:         media.Size,
+		Width:        media.Width,
+		Height:       media.Height,
+	}
+}
@@ -0,0 +1,69 @@
+package request_model
+
+import (
+	"symphony-api/internal/persistence/model"
+	"time"
+)
+
+type CreateReportRequest struct {
+	Username    string `json:"username" binding:"required"`
+	ContentType string `json:"content_type" binding:"required"`
+	ContentId   int32  `json:"content_id" binding:"required"`
+	Reason      string `json:"reason" binding:"required"`
+}
+
+type CreateReportResponse struct {
+	ReportId int32  `json:"report_id" binding:"required"`
+	Status   string `json:"status" binding:"required"`
+}
+
+func NewCreateReportResponse(report *model.Report) *CreateReportResponse {
+	return &CreateReportResponse{
+		ReportId: report.ReportId,
+		Status:   report.Status,
+	}
+}
+
+type ListModerationQueueRequest struct {
+	Moderator string `schema:"moderator,required"`
+	Status    string `schema:"status,default=open"`
+	Limit     int32  `schema:"limit,default=20"`
+	Offset    int32  `schema:"offset,default=0"`
+}
+
+type ModerationItemResponse struct {
+	ContentType   string    `json:"content_type" binding:"required"`
+	ContentId     int32     `json:"content_id" binding:"required"`
+	ReporterCount int64     `json:"reporter_count" binding:"required"`
+	Reasons       []string  `json:"reasons" binding:"required"`
+	FirstReported time.Time `json:"first_reported" binding:"required"`
+	LastReported  time.Time `json:"last_reported" binding:"required"`
+	Hidden        bool      `json:"hidden" binding:"required"`
+}
+
+type ListModerationQueueResponse struct {
+	Items []*ModerationItemResponse `json:"items" binding:"required"`
+}
+
+func NewListModerationQueueResponse(items []*model.ModerationItem) *ListModerationQueueResponse {
+	responses := make([]*ModerationItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = &ModerationItemResponse{
+			ContentType:   item.ContentType,
+			ContentId:     item.ContentId,
+			ReporterCount: item.ReporterCount,
+			Reasons:       item.Reasons,
+			FirstReported: item.FirstReported,
+			LastReported:  item.LastReported,
+			Hidden:        item.Hidden,
+		}
+	}
+	return &ListModerationQueueResponse{Items: responses}
+}
+
+type ReviewReportedContentRequest struct {
+	Moderator   string `json:"moderator" binding:"required"`
+	ContentType string `json:"content_type" binding:"required"`
+	ContentId   int32  `json:"content_id" binding:"required"`
+	Action      string `json:"action" binding:"required"`
+}
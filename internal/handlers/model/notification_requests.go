@@ -0,0 +1,92 @@
+package request_model
+
+import (
+	"symphony-api/internal/persistence/model"
+	"time"
+)
+
+type ListNotificationsRequest struct {
+	Username   string `schema:"username,required"`
+	Limit      int32  `schema:"limit,default=20"`
+	Offset     int32  `schema:"offset,default=0"`
+	UnreadOnly bool   `schema:"unread_only,default=false"`
+}
+
+type NotificationResponse struct {
+	NotificationId int32     `json:"notification_id" binding:"required"`
+	Type           string    `json:"type" binding:"required"`
+	Summary        string    `json:"summary" binding:"required"`
+	Actors         []string  `json:"actors" binding:"required"`
+	ActorCount     int64     `json:"actor_count" binding:"required"`
+	TargetId       int32     `json:"target_id,omitempty"`
+	TargetName     string    `json:"target_name,omitempty"`
+	Read           bool      `json:"read" binding:"required"`
+	CreatedAt      time.Time `json:"created_at" binding:"required"`
+	UpdatedAt      time.Time `json:"updated_at" binding:"required"`
+}
+
+type ListNotificationsResponse struct {
+	Notifications []*NotificationResponse `json:"notifications" binding:"required"`
+	UnreadCount   int64                   `json:"unread_count" binding:"required"`
+}
+
+func NewListNotificationsResponse(notifications []*model.Notification, unreadCount int64) *ListNotificationsResponse {
+	responses := make([]*NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		responses[i] = &NotificationResponse{
+			NotificationId: notification.NotificationId,
+			Type:           notification.Type,
+			Summary:        notification.Summary(),
+			Actors:         notification.Actors,
+			ActorCount:     notification.ActorCount,
+			TargetId:       notification.TargetId,
+			TargetName:     notification.TargetName,
+			Read:           notification.IsRead(),
+			CreatedAt:      notification.CreatedAt,
+			UpdatedAt:      notification.UpdatedAt,
+		}
+	}
+	return &ListNotificationsResponse{
+		Notifications: responses,
+		UnreadCount:   unreadCount,
+	}
+}
+
+type NotificationActionRequest struct {
+	Username       string `json:"username" binding:"required"`
+	NotificationId int32  `json:"notification_id" binding:"required"`
+}
+
+type MarkAllNotificationsReadRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+type ListNotificationPreferencesRequest struct {
+	Username string `schema:"username,required"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type  string `json:"type" binding:"required"`
+	Muted bool   `json:"muted" binding:"required"`
+}
+
+type ListNotificationPreferencesResponse struct {
+	Preferences []*NotificationPreferenceResponse `json:"preferences" binding:"required"`
+}
+
+func NewListNotificationPreferencesResponse(preferences []*model.NotificationPreference) *ListNotificationPreferencesResponse {
+	responses := make([]*NotificationPreferenceResponse, len(preferences))
+	for i, preference := range preferences {
+		responses[i] = &NotificationPreferenceResponse{
+			Type:  preference.Type,
+			Muted: preference.Muted,
+		}
+	}
+	return &ListNotificationPreferencesResponse{Preferences: responses}
+}
+
+type SetNotificationPreferenceRequest struct {
+	Username string `json:"username" binding:"required"`
+	Type     string `json:"type" binding:"required"`
+	Muted    bool   `json:"muted"`
+}
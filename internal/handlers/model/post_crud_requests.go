@@ -5,7 +5,8 @@ import (
 )
 
 type CreatePostRequest struct {
-	Username string `json:"username" binding:"required"`
+	Username   string             `json:"username" binding:"required"`
+	Attachment *AttachmentRequest `json:"attachment,omitempty"`
 	*BasePostModel
 }
 
@@ -25,6 +26,9 @@ func NewBasePostModel(post *model.Post) *BasePostModel {
 
 type CreatePostResponse struct {
 	*BasePostModel
+	Attachment *AttachmentResponse `json:"attachment,omitempty"`
+	Hashtags   []string            `json:"hashtags"`
+	Mentions   []string            `json:"mentions"`
 }
 
 func (request *CreatePostResponse) ToPost() *model.Post {
@@ -35,42 +39,58 @@ func (request *CreatePostResponse) ToPost() *model.Post {
 	}
 }
 
-func NewCreatePostResponse(post *model.Post) *CreatePostResponse {
+func NewCreatePostResponse(post *model.Post, hashtags []string, mentions []*model.User) *CreatePostResponse {
+	usernames := make([]string, len(mentions))
+	for i, user := range mentions {
+		usernames[i] = user.Username
+	}
+
 	return &CreatePostResponse{
 		BasePostModel: NewBasePostModel(post),
+		Attachment:    NewAttachmentResponse(post.Attachment),
+		Hashtags:      hashtags,
+		Mentions:      usernames,
 	}
 }
 
 type PostResponse struct {
 	*BasePostModel
-	Id int32 `json:"id" binding:"required"`
+	Id         int32               `json:"id" binding:"required"`
+	UserId     int32               `json:"user_id" binding:"required"`
+	Attachment *AttachmentResponse `json:"attachment,omitempty"`
 }
 
 func NewPostResponse(post *model.Post) *PostResponse {
 	return &PostResponse{
 		Id:            post.PostId,
+		UserId:        post.UserId,
 		BasePostModel: NewBasePostModel(post),
+		Attachment:    NewAttachmentResponse(post.Attachment),
 	}
 }
 
 type GetPostByIdRequest struct {
-	PostId int32 `schema:"post_id,required"`
+	PostId int32  `schema:"post_id,required"`
+	Viewer string `schema:"viewer"`
 }
 
 type GetPostByIdResponse struct {
-	Id     int32 `json:"id" binding:"required"`
+	Id         int32 `json:"id" binding:"required"`
 	*BasePostModel
+	Attachment *AttachmentResponse `json:"attachment,omitempty"`
 }
 
 func NewGetPostByIdResponse(post *model.Post) *GetPostByIdResponse {
 	return &GetPostByIdResponse{
 		Id:            post.PostId,
 		BasePostModel: NewBasePostModel(post),
+		Attachment:    NewAttachmentResponse(post.Attachment),
 	}
 }
 
 type GetPostsByUsernameRequest struct {
 	Username string `schema:"username,required"`
+	Viewer   string `schema:"viewer"`
 }
 
 type GetPostsByUsernameResponse struct {
@@ -84,3 +104,70 @@ func NewGetPostsByUsernameResponse(posts []*model.Post) *GetPostsByUsernameRespo
 	}
 	return &GetPostsByUsernameResponse{Posts: postResponses}
 }
+
+type ListPostsResponse struct {
+	Posts []*PostResponse `json:"posts" binding:"required"`
+}
+
+func NewListPostsResponse(posts []*model.Post) *ListPostsResponse {
+	postResponses := make([]*PostResponse, len(posts))
+	for i, post := range posts {
+		postResponses[i] = NewPostResponse(post)
+	}
+	return &ListPostsResponse{Posts: postResponses}
+}
+
+type GetPostsByTagRequest struct {
+	Tag    string `schema:"tag,required"`
+	Viewer string `schema:"viewer"`
+}
+
+type GetPostsMentioningUserRequest struct {
+	Username string `schema:"username,required"`
+	Viewer   string `schema:"viewer"`
+}
+
+type GetFeedRequest struct {
+	Username string `schema:"username,required"`
+	Limit    int32  `schema:"limit,default=20"`
+}
+
+type GetTrendingTagsRequest struct {
+	Hours int32 `schema:"hours,default=24"`
+	Limit int32 `schema:"limit,default=10"`
+}
+
+type TrendingTagResponse struct {
+	Tag       string `json:"tag" binding:"required"`
+	PostCount int64  `json:"post_count" binding:"required"`
+}
+
+type GetTrendingTagsResponse struct {
+	Tags []*TrendingTagResponse `json:"tags" binding:"required"`
+}
+
+func NewGetTrendingTagsResponse(hashtags []*model.TrendingHashtag) *GetTrendingTagsResponse {
+	tags := make([]*TrendingTagResponse, len(hashtags))
+	for i, hashtag := range hashtags {
+		tags[i] = &TrendingTagResponse{
+			Tag:       hashtag.Tag,
+			PostCount: hashtag.PostCount,
+		}
+	}
+	return &GetTrendingTagsResponse{Tags: tags}
+}
+
+type LikePostRequest struct {
+	Username string `json:"username" binding:"required"`
+	PostId   int32  `json:"post_id" binding:"required"`
+}
+
+type CommentPostRequest struct {
+	Username string `json:"username" binding:"required"`
+	PostId   int32  `json:"post_id" binding:"required"`
+	Text     string `json:"text" binding:"required"`
+}
+
+type CommentPostResponse struct {
+	CommentId int32 `json:"comment_id" binding:"required"`
+}
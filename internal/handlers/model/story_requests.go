@@ -0,0 +1,109 @@
+package request_model
+
+import (
+	"symphony-api/internal/persistence/model"
+	"time"
+)
+
+type CreateStoryRequest struct {
+	Username string `json:"username" binding:"required"`
+	SongId   string `json:"song_id" binding:"required"`
+	Caption  string `json:"caption"`
+}
+
+type StoryResponse struct {
+	Id        string              `json:"id" binding:"required"`
+	Username  string              `json:"username" binding:"required"`
+	Song      *AttachmentResponse `json:"song" binding:"required"`
+	Caption   string              `json:"caption"`
+	CreatedAt time.Time           `json:"created_at" binding:"required"`
+	ExpiresAt time.Time           `json:"expires_at" binding:"required"`
+	ViewCount int                 `json:"view_count"`
+}
+
+func NewStoryResponse(story *model.Story, song *model.Attachment) *StoryResponse {
+	return &StoryResponse{
+		Id:        story.ID.Hex(),
+		Username:  story.Username,
+		Song:      NewAttachmentResponse(song),
+		Caption:   story.Caption,
+		CreatedAt: story.CreatedAt,
+		ExpiresAt: story.ExpiresAt,
+		ViewCount: len(story.Views),
+	}
+}
+
+type GetStoryTrayRequest struct {
+	Username string `schema:"username,required"`
+}
+
+type StoryTrayItemResponse struct {
+	Username   string    `json:"username" binding:"required"`
+	StoryCount int       `json:"story_count" binding:"required"`
+	LatestAt   time.Time `json:"latest_at" binding:"required"`
+	HasUnseen  bool      `json:"has_unseen" binding:"required"`
+}
+
+type GetStoryTrayResponse struct {
+	Users []*StoryTrayItemResponse `json:"users" binding:"required"`
+}
+
+func NewGetStoryTrayResponse(items []*model.StoryTrayItem) *GetStoryTrayResponse {
+	users := make([]*StoryTrayItemResponse, len(items))
+	for i, item := range items {
+		users[i] = &StoryTrayItemResponse{
+			Username:   item.Username,
+			StoryCount: item.StoryCount,
+			LatestAt:   item.LatestAt,
+			HasUnseen:  item.HasUnseen,
+		}
+	}
+	return &GetStoryTrayResponse{Users: users}
+}
+
+type ListStoriesRequest struct {
+	Username string `schema:"username,required"`
+	Viewer   string `schema:"viewer,required"`
+}
+
+type ListStoriesResponse struct {
+	Stories []*StoryResponse `json:"stories" binding:"required"`
+}
+
+func NewListStoriesResponse(stories []model.Story, songs []*model.Attachment) *ListStoriesResponse {
+	responses := make([]*StoryResponse, len(stories))
+	for i := range stories {
+		responses[i] = NewStoryResponse(&stories[i], songs[i])
+	}
+	return &ListStoriesResponse{Stories: responses}
+}
+
+type ViewStoryRequest struct {
+	StoryId string `json:"story_id" binding:"required"`
+	Viewer  string `json:"viewer" binding:"required"`
+}
+
+type ListStoryViewersRequest struct {
+	StoryId  string `schema:"story_id,required"`
+	Username string `schema:"username,required"`
+}
+
+type StoryViewResponse struct {
+	Username string    `json:"username" binding:"required"`
+	ViewedAt time.Time `json:"viewed_at" binding:"required"`
+}
+
+type ListStoryViewersResponse struct {
+	Viewers []*StoryViewResponse `json:"viewers" binding:"required"`
+}
+
+func NewListStoryViewersResponse(views []model.StoryView) *ListStoryViewersResponse {
+	viewers := make([]*StoryViewResponse, len(views))
+	for i, view := range views {
+		viewers[i] = &StoryViewResponse{
+			Username: view.Username,
+			ViewedAt: view.ViewedAt,
+		}
+	}
+	return &ListStoryViewersResponse{Viewers: viewers}
+}
@@ -7,10 +7,12 @@ import (
 
 type GetUserByUsernameRequest struct {
 	Username string `schema:"username,required"`
+	Viewer string `schema:"viewer"`
 }
 
 type GetUserFriendsRequest struct {
 	Username string `schema:"username,required"`
+	Viewer string `schema:"viewer"`
 }
 
 type GetUserFriendsResponse struct {
@@ -25,6 +27,130 @@ type GetFriendRecommendationByGenreResponse struct {
 	Friends []*UserResponse `json:"friends" binding:"required"`
 }
 
+type GetFriendRecommendationsRequest struct {
+	Username string `schema:"username,required"`
+	Limit int32 `schema:"limit,default=20"`
+	Offset int32 `schema:"offset,default=0"`
+}
+
+type RecommendationResponse struct {
+	User *UserResponse `json:"user" binding:"required"`
+	Score float64 `json:"score"`
+	Explanation string `json:"explanation"`
+	MutualFriends int64 `json:"mutual_friends"`
+	SharedGenres []string `json:"shared_genres"`
+	GenreSimilarity float64 `json:"genre_similarity"`
+	SharedCommunities int64 `json:"shared_communities"`
+	SharedSongs int64 `json:"shared_songs"`
+}
+
+type GetFriendRecommendationsResponse struct {
+	Recommendations []*RecommendationResponse `json:"recommendations" binding:"required"`
+}
+
+func NewGetFriendRecommendationsResponse(recommendations []*model.Recommendation, views model.UserViews) *GetFriendRecommendationsResponse {
+	responses := make([]*RecommendationResponse, 0, len(recommendations))
+
+	for _, recommendation := range recommendations {
+		responses = append(responses, &RecommendationResponse{
+			User: NewUserResponse(recommendation.User, views.Of(recommendation.User)),
+			Score: recommendation.Score,
+			Explanation: recommendation.Explanation,
+			MutualFriends: recommendation.MutualFriends,
+			SharedGenres: recommendation.SharedGenres,
+			GenreSimilarity: recommendation.GenreSimilarity(),
+			SharedCommunities: recommendation.SharedCommunities,
+			SharedSongs: recommendation.SharedSongs,
+		})
+	}
+
+	return &GetFriendRecommendationsResponse{
+		Recommendations: responses,
+	}
+}
+
+type GetSocialConnectionRequest struct {
+	Username string `schema:"username,required"`
+	Target string `schema:"target,required"`
+	MaxDepth int `schema:"max_depth,default=4"`
+}
+
+type SocialConnectionResponse struct {
+	Degrees int `json:"degrees"`
+	Summary string `json:"summary"`
+	Path []*UserResponse `json:"path" binding:"required"`
+	MutualFriends []*UserResponse `json:"mutual_friends" binding:"required"`
+	SharedCommunities []*CommunityDataResponse `json:"shared_communities" binding:"required"`
+	SharedGenres []string `json:"shared_genres" binding:"required"`
+}
+
+func NewSocialConnectionResponse(connection *model.SocialConnection, views model.UserViews) *SocialConnectionResponse {
+	communities := make([]*CommunityDataResponse, 0, len(connection.SharedCommunities))
+	for _, community := range connection.SharedCommunities {
+		communities = append(communities, NewCommunityDataResponse(community))
+	}
+
+	return &SocialConnectionResponse{
+		Degrees: connection.Degrees(),
+		Summary: connection.Summary(),
+		Path: newPathResponses(connection.Path, views),
+		MutualFriends: NewUserResponses(connection.MutualFriends, views),
+		SharedCommunities: communities,
+		SharedGenres: connection.SharedGenres,
+	}
+}
+
+// newPathResponses projects the users of a path, leaving the redacted ones as
+// null.
+func newPathResponses(path []*model.User, views model.UserViews) []*UserResponse {
+	responses := make([]*UserResponse, len(path))
+	for i, user := range path {
+		if user != nil {
+			responses[i] = NewUserResponse(user, views.Of(user))
+		}
+	}
+
+	return responses
+}
+
+type SearchUsersRequest struct {
+	Query string `schema:"q,required"`
+	Viewer string `schema:"viewer"`
+	Limit int32 `schema:"limit,default=20"`
+}
+
+type UserSearchResultResponse struct {
+	User *UserResponse `json:"user" binding:"required"`
+	Score float64 `json:"score"`
+	IsFriend bool `json:"is_friend"`
+	MutualFriends int64 `json:"mutual_friends"`
+	Following bool `json:"following"`
+}
+
+type SearchUsersResponse struct {
+	Count int `json:"count"`
+	Results []*UserSearchResultResponse `json:"results" binding:"required"`
+}
+
+func NewSearchUsersResponse(results []*model.UserSearchResult, views model.UserViews) *SearchUsersResponse {
+	responses := make([]*UserSearchResultResponse, 0, len(results))
+
+	for _, result := range results {
+		responses = append(responses, &UserSearchResultResponse{
+			User: NewUserResponse(result.User, views.Of(result.User)),
+			Score: result.Score,
+			IsFriend: result.IsFriend,
+			MutualFriends: result.MutualFriends,
+			Following: result.Following,
+		})
+	}
+
+	return &SearchUsersResponse{
+		Count: len(responses),
+		Results: responses,
+	}
+}
+
 type GetLikedGenresRequest struct {
 	Username string `schema:"username,required"`
 }
@@ -58,18 +184,162 @@ type CreateUserRequest struct {
 	*BaseUserModel
 }
 
-type CreateFriendshipRequest struct {
-	Username1 string `json:"username1" binding:"required"`
-	Username2 string `json:"username2" binding:"required"`
+type SendFriendRequestRequest struct {
+	Username string `json:"username" binding:"required"`
+	Target string `json:"target" binding:"required"`
+}
+
+type SendFriendRequestResponse struct {
+	Message string `json:"message" binding:"required"`
+	Accepted bool `json:"accepted"`
 }
 
-func NewBaseUserModel(user *model.User) *BaseUserModel {
-	return &BaseUserModel {
-		Username: user.Username,
-		Fullname: user.Fullname,
-		Email: user.Email,
-		Birth_date: user.Birth_date,
-		Telephone: user.Telephone,
+type AnswerFriendRequestRequest struct {
+	Username string `json:"username" binding:"required"`
+	Requester string `json:"requester" binding:"required"`
+}
+
+type CancelFriendRequestRequest struct {
+	Username string `json:"username" binding:"required"`
+	Target string `json:"target" binding:"required"`
+}
+
+type UnfriendRequest struct {
+	Username string `json:"username" binding:"required"`
+	Friend string `json:"friend" binding:"required"`
+}
+
+type ListFriendRequestsRequest struct {
+	Username string `schema:"username,required"`
+}
+
+type FriendRequestResponse struct {
+	From string `json:"from" binding:"required"`
+	To string `json:"to" binding:"required"`
+	SentAt time.Time `json:"sent_at" binding:"required"`
+}
+
+type ListFriendRequestsResponse struct {
+	Requests []*FriendRequestResponse `json:"requests" binding:"required"`
+}
+
+func NewListFriendRequestsResponse(requests []*model.FriendRequest) *ListFriendRequestsResponse {
+	responses := make([]*FriendRequestResponse, 0, len(requests))
+
+	for _, request := range requests {
+		responses = append(responses, &FriendRequestResponse{
+			From: request.From,
+			To: request.To,
+			SentAt: request.SentAt,
+		})
+	}
+
+	return &ListFriendRequestsResponse{
+		Requests: responses,
+	}
+}
+
+type FollowUserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Target string `json:"target" binding:"required"`
+}
+
+type FollowArtistRequest struct {
+	Username string `json:"username" binding:"required"`
+	ArtistId string `json:"artist_id" binding:"required"`
+}
+
+type GetFollowCountsRequest struct {
+	Username string `schema:"username,required"`
+}
+
+type FollowCountsResponse struct {
+	Followers int64 `json:"followers"`
+	FollowingUsers int64 `json:"following_users"`
+	FollowingArtists int64 `json:"following_artists"`
+}
+
+func NewFollowCountsResponse(counts *model.FollowCounts) *FollowCountsResponse {
+	return &FollowCountsResponse{
+		Followers: counts.Followers,
+		FollowingUsers: counts.FollowingUsers,
+		FollowingArtists: counts.FollowingArtists,
+	}
+}
+
+type ListFollowsRequest struct {
+	Username string `schema:"username,required"`
+	Viewer string `schema:"viewer"`
+}
+
+type ListUsersResponse struct {
+	Count int `json:"count"`
+	Users []*UserResponse `json:"users" binding:"required"`
+}
+
+func NewListUsersResponse(users []*model.User, views model.UserViews) *ListUsersResponse {
+	responses := NewUserResponses(users, views)
+
+	return &ListUsersResponse{
+		Count: len(responses),
+		Users: responses,
+	}
+}
+
+type ListFollowedArtistsResponse struct {
+	Artists []model.Artist `json:"artists" binding:"required"`
+}
+
+type ListArtistFollowersRequest struct {
+	ArtistId string `schema:"artist_id,required"`
+	Viewer string `schema:"viewer"`
+}
+
+type BlockUserRequest struct {
+	Username string `json:"username" binding:"required"`
+	Target string `json:"target" binding:"required"`
+}
+
+type ListBlockedUsersRequest struct {
+	Username string `schema:"username,required"`
+}
+
+type UpdatePrivacySettingsRequest struct {
+	Username string `json:"username" binding:"required"`
+	ProfileVisibility string `json:"profile_visibility" binding:"required"`
+	MessagePermission string `json:"message_permission" binding:"required"`
+}
+
+type PrivacySettingsResponse struct {
+	ProfileVisibility string `json:"profile_visibility" binding:"required"`
+	MessagePermission string `json:"message_permission" binding:"required"`
+}
+
+func NewPrivacySettingsResponse(user *model.User) *PrivacySettingsResponse {
+	return &PrivacySettingsResponse{
+		ProfileVisibility: user.ProfileVisibility,
+		MessagePermission: user.MessagePermission,
+	}
+}
+
+// UpdateProfileRequest changes the profile of a user. Fields left out of the
+// request are not changed.
+type UpdateProfileRequest struct {
+	Username string `json:"username" binding:"required"`
+	NewUsername *string `json:"new_username,omitempty"`
+	Fullname *string `json:"fullname,omitempty"`
+	Bio *string `json:"bio,omitempty"`
+	AvatarUrl *string `json:"avatar_url,omitempty"`
+	Telephone *string `json:"telephone,omitempty"`
+}
+
+func (request *UpdateProfileRequest) ToProfileUpdate() *model.ProfileUpdate {
+	return &model.ProfileUpdate{
+		Username: request.NewUsername,
+		Fullname: request.Fullname,
+		Bio: request.Bio,
+		AvatarUrl: request.AvatarUrl,
+		Telephone: request.Telephone,
 	}
 }
 
@@ -83,16 +353,49 @@ func (request *CreateUserRequest) ToUser() *model.User {
 	}
 }
 
+// UserResponse is the projection of a user that a viewer is allowed to see.
+// Fields hidden by the projection are left out of the JSON.
 type UserResponse struct {
-	*BaseUserModel
 	Id int32 `json:"id"`
+	Username string `json:"username" binding:"required"`
+	Fullname string `json:"fullname" binding:"required"`
+	Bio string `json:"bio,omitempty"`
+	AvatarUrl string `json:"avatar_url,omitempty"`
 	Register_date time.Time `json:"register_date"`
+	Email string `json:"email,omitempty"`
+	Birth_date *time.Time `json:"birth_date,omitempty"`
+	Telephone string `json:"telephone,omitempty"`
 }
 
-func NewUserResponse(user *model.User) *UserResponse {
-	return &UserResponse {
+func NewUserResponse(user *model.User, view string) *UserResponse {
+	response := &UserResponse {
 		Id: user.UserId,
+		Username: user.Username,
+		Fullname: user.Fullname,
+		Bio: user.Bio,
+		AvatarUrl: user.AvatarUrl,
 		Register_date: user.Register_date,
-		BaseUserModel: NewBaseUserModel(user),
 	}
+
+	if view == model.USER_VIEW_FRIEND || view == model.USER_VIEW_SELF {
+		birthDate := user.Birth_date
+		response.Email = user.Email
+		response.Birth_date = &birthDate
+	}
+
+	if view == model.USER_VIEW_SELF {
+		response.Telephone = user.Telephone
+	}
+
+	return response
+}
+
+func NewUserResponses(users []*model.User, views model.UserViews) []*UserResponse {
+	responses := make([]*UserResponse, 0, len(users))
+
+	for _, user := range users {
+		responses = append(responses, NewUserResponse(user, views.Of(user)))
+	}
+
+	return responses
 }
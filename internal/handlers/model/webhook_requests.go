@@ -0,0 +1,162 @@
+package request_model
+
+import (
+	"encoding/json"
+	"symphony-api/internal/persistence/model"
+	"time"
+)
+
+type CreateWebhookSubscriptionRequest struct {
+	Username   string   `json:"username" binding:"required"`
+	Url        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+}
+
+type ListWebhookSubscriptionsRequest struct {
+	Username string `schema:"username,required"`
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	Username       string   `json:"username" binding:"required"`
+	SubscriptionId int32    `json:"subscription_id" binding:"required"`
+	Url            string   `json:"url" binding:"required"`
+	EventTypes     []string `json:"event_types" binding:"required"`
+	Active         bool     `json:"active"`
+}
+
+type DeleteWebhookSubscriptionRequest struct {
+	Username       string `json:"username" binding:"required"`
+	SubscriptionId int32  `json:"subscription_id" binding:"required"`
+}
+
+// The secret is only sent when the subscription is created.
+type WebhookSubscriptionResponse struct {
+	SubscriptionId int32     `json:"subscription_id" binding:"required"`
+	Url            string    `json:"url" binding:"required"`
+	EventTypes     []string  `json:"event_types" binding:"required"`
+	Active         bool      `json:"active" binding:"required"`
+	CreatedAt      time.Time `json:"created_at" binding:"required"`
+	Secret         string    `json:"secret,omitempty"`
+}
+
+func NewWebhookSubscriptionResponse(subscription *model.WebhookSubscription) *WebhookSubscriptionResponse {
+	return &WebhookSubscriptionResponse{
+		SubscriptionId: subscription.SubscriptionId,
+		Url:            subscription.Url,
+		EventTypes:     subscription.EventTypes,
+		Active:         subscription.Active,
+		CreatedAt:      subscription.CreatedAt,
+	}
+}
+
+func NewCreateWebhookSubscriptionResponse(subscription *model.WebhookSubscription) *WebhookSubscriptionResponse {
+	response := NewWebhookSubscriptionResponse(subscription)
+	response.Secret = subscription.Secret
+	return response
+}
+
+type ListWebhookSubscriptionsResponse struct {
+	Subscriptions []*WebhookSubscriptionResponse `json:"subscriptions" binding:"required"`
+}
+
+func NewListWebhookSubscriptionsResponse(subscriptions []*model.WebhookSubscription) *ListWebhookSubscriptionsResponse {
+	responses := make([]*WebhookSubscriptionResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		responses[i] = NewWebhookSubscriptionResponse(subscription)
+	}
+	return &ListWebhookSubscriptionsResponse{Subscriptions: responses}
+}
+
+type ListWebhookDeliveriesRequest struct {
+	Username       string `schema:"username,required"`
+	SubscriptionId int32  `schema:"subscription_id,required"`
+	Status         string `schema:"status"`
+	Limit          int32  `schema:"limit,default=20"`
+	Offset         int32  `schema:"offset,default=0"`
+}
+
+type WebhookDeliveryResponse struct {
+	DeliveryId     int64           `json:"delivery_id" binding:"required"`
+	SubscriptionId int32           `json:"subscription_id" binding:"required"`
+	EventType      string          `json:"event_type" binding:"required"`
+	Status         string          `json:"status" binding:"required"`
+	Attempts       int32           `json:"attempts" binding:"required"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at,omitzero"`
+	ReplayOf       int64           `json:"replay_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at" binding:"required"`
+	DeliveredAt    time.Time       `json:"delivered_at,omitzero"`
+	Payload        json.RawMessage `json:"payload" binding:"required"`
+}
+
+func NewWebhookDeliveryResponse(delivery *model.WebhookDelivery) *WebhookDeliveryResponse {
+	response := &WebhookDeliveryResponse{
+		DeliveryId:     delivery.DeliveryId,
+		SubscriptionId: delivery.SubscriptionId,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ReplayOf:       delivery.ReplayOf,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		Payload:        delivery.Payload,
+	}
+	// Only pending deliveries have a next attempt.
+	if delivery.Status == model.WEBHOOK_DELIVERY_PENDING {
+		response.NextAttemptAt = delivery.NextAttemptAt
+	}
+	return response
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []*WebhookDeliveryResponse `json:"deliveries" binding:"required"`
+}
+
+func NewListWebhookDeliveriesResponse(deliveries []*model.WebhookDelivery) *ListWebhookDeliveriesResponse {
+	responses := make([]*WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = NewWebhookDeliveryResponse(delivery)
+	}
+	return &ListWebhookDeliveriesResponse{Deliveries: responses}
+}
+
+type GetWebhookDeliveryLogRequest struct {
+	Username   string `schema:"username,required"`
+	DeliveryId int64  `schema:"delivery_id,required"`
+}
+
+type WebhookAttemptResponse struct {
+	Attempt      int32     `json:"attempt" binding:"required"`
+	StatusCode   int32     `json:"status_code,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"`
+	DurationMs   int32     `json:"duration_ms" binding:"required"`
+	AttemptedAt  time.Time `json:"attempted_at" binding:"required"`
+}
+
+type GetWebhookDeliveryLogResponse struct {
+	Delivery *WebhookDeliveryResponse  `json:"delivery" binding:"required"`
+	Attempts []*WebhookAttemptResponse `json:"attempts" binding:"required"`
+}
+
+func NewGetWebhookDeliveryLogResponse(delivery *model.WebhookDelivery, attempts []*model.WebhookAttempt) *GetWebhookDeliveryLogResponse {
+	responses := make([]*WebhookAttemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		responses[i] = &WebhookAttemptResponse{
+			Attempt:      attempt.Attempt,
+			StatusCode:   attempt.StatusCode,
+			Error:        attempt.Error,
+			ResponseBody: attempt.ResponseBody,
+			DurationMs:   attempt.DurationMs,
+			AttemptedAt:  attempt.AttemptedAt,
+		}
+	}
+	return &GetWebhookDeliveryLogResponse{
+		Delivery: NewWebhookDeliveryResponse(delivery),
+		Attempts: responses,
+	}
+}
+
+type ReplayWebhookDeliveryRequest struct {
+	Username   string `json:"username" binding:"required"`
+	DeliveryId int64  `json:"delivery_id" binding:"required"`
+}
@@ -0,0 +1,110 @@
+package moderation_handlers
+
+import (
+	"log"
+	base_handlers "symphony-api/internal/handlers/base"
+	request_model "symphony-api/internal/handlers/model"
+	"symphony-api/internal/persistence/connectors/neo4j"
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/persistence/service"
+	"symphony-api/internal/server"
+	"symphony-api/pkg/config"
+)
+
+type ModerationHandler struct {
+	moderationService *service.ModerationService
+}
+
+func NewModerationHandler(connection postgres.PostgreConnection, neo4jConnection neo4j.Neo4jConnection) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: service.NewModerationService(
+			repository.NewReportRepository(connection),
+			repository.NewUserRepository(connection, neo4jConnection),
+			repository.NewChatRepository(connection),
+			config.GetEnvInt("REPORT_HIDE_THRESHOLD", 3),
+		),
+	}
+}
+
+func (handler *ModerationHandler) AddRoutes(server server.Server) {
+	server.AddRoute("/api/report/create", base_handlers.CreatePostMethodHandler(handler.CreateReport))
+	server.AddRoute("/api/moderation/queue", base_handlers.CreateGetMethodHandler(handler.ListModerationQueue))
+	server.AddRoute("/api/moderation/review", base_handlers.CreatePostMethodHandler(handler.ReviewReportedContent))
+}
+
+// CreateReport reports abusive content.
+//	@Summary		Report content
+//	@Description	Reports a post, comment, chat_message or community. Chat messages can only be reported by participants of the chat. Once enough distinct users report the same content it is hidden until a moderator reviews it.
+//	@Tags			moderation
+//	@Accept			json
+//	@Produce		json
+//	@Param			report	body		request_model.CreateReportRequest	true	"Report data"
+//	@Success		200		{object}	request_model.CreateReportResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/report/create [post]
+func (handler *ModerationHandler) CreateReport(request request_model.CreateReportRequest) (*request_model.CreateReportResponse, error) {
+	report, err := handler.moderationService.Report(
+		request.Username,
+		request.ContentType,
+		request.ContentId,
+		request.Reason,
+	)
+	if err != nil {
+		log.Printf("Error creating report: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewCreateReportResponse(report), nil
+}
+
+// ListModerationQueue lists reported content for moderators.
+//	@Summary		List moderation queue
+//	@Description	Lists reported contents grouped by content, the most reported first. Only available to moderators.
+//	@Tags			moderation
+//	@Accept			json
+//	@Produce		json
+//	@Param			moderator	query		string	true	"Username of the moderator"
+//	@Param			status		query		string	false	"Status of the reports: open (default), dismissed, hidden or deleted"
+//	@Param			limit		query		int		false	"Number of items to retrieve (default is 20)"
+//	@Param			offset		query		int		false	"Number of items to skip"
+//	@Success		200		{object}	request_model.ListModerationQueueResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/moderation/queue [get]
+func (handler *ModerationHandler) ListModerationQueue(request request_model.ListModerationQueueRequest) (*request_model.ListModerationQueueResponse, error) {
+	items, err := handler.moderationService.ListQueue(request.Moderator, request.Status, request.Limit, request.Offset)
+	if err != nil {
+		log.Printf("Error listing moderation queue: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewListModerationQueueResponse(items), nil
+}
+
+// ReviewReportedContent applies a moderation decision.
+//	@Summary		Review reported content
+//	@Description	Dismisses the reports (showing the content again), hides or deletes a reported content. Only available to moderators.
+//	@Tags			moderation
+//	@Accept			json
+//	@Produce		json
+//	@Param			review	body		request_model.ReviewReportedContentRequest	true	"Action: dismiss, hide or delete"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/moderation/review [post]
+func (handler *ModerationHandler) ReviewReportedContent(request request_model.ReviewReportedContentRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.moderationService.Review(
+		request.Moderator,
+		request.ContentType,
+		request.ContentId,
+		request.Action,
+	)
+	if err != nil {
+		log.Printf("Error reviewing reported content: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully reviewed content"), nil
+}
@@ -0,0 +1,159 @@
+package notification_handlers
+
+import (
+	"log"
+	base_handlers "symphony-api/internal/handlers/base"
+	request_model "symphony-api/internal/handlers/model"
+	"symphony-api/internal/persistence/connectors/neo4j"
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/persistence/service"
+	"symphony-api/internal/server"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(connection postgres.PostgreConnection, neo4jConnection neo4j.Neo4jConnection) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: service.NewNotificationService(
+			repository.NewNotificationRepository(connection),
+			repository.NewUserRepository(connection, neo4jConnection),
+		),
+	}
+}
+
+func (handler *NotificationHandler) AddRoutes(server server.Server) {
+	server.AddRoute("/api/notification/list", base_handlers.CreateGetMethodHandler(handler.ListNotifications))
+	server.AddRoute("/api/notification/mark_read", base_handlers.CreatePostMethodHandler(handler.MarkRead))
+	server.AddRoute("/api/notification/mark_all_read", base_handlers.CreatePostMethodHandler(handler.MarkAllRead))
+	server.AddRoute("/api/notification/delete", base_handlers.CreatePostMethodHandler(handler.DeleteNotification))
+	server.AddRoute("/api/notification/preferences", base_handlers.CreateGetMethodHandler(handler.ListPreferences))
+	server.AddRoute("/api/notification/set_preference", base_handlers.CreatePostMethodHandler(handler.SetPreference))
+}
+
+// ListNotifications lists the notifications of a user.
+//	@Summary		List notifications
+//	@Description	Lists the notifications of the user, the most recently updated first. Unread notifications of the same type about the same target are grouped, listing the most recent actors.
+//	@Tags			notification
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username of the user"
+//	@Param			limit		query		int		false	"Number of items to retrieve (default is 20)"
+//	@Param			offset		query		int		false	"Number of items to skip"
+//	@Param			unread_only	query		bool	false	"Only list unread notifications"
+//	@Success		200			{object}	request_model.ListNotificationsResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/notification/list [get]
+func (handler *NotificationHandler) ListNotifications(request request_model.ListNotificationsRequest) (*request_model.ListNotificationsResponse, error) {
+	notifications, unread, err := handler.notificationService.List(request.Username, request.Limit, request.Offset, request.UnreadOnly)
+	if err != nil {
+		log.Printf("Error listing notifications: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewListNotificationsResponse(notifications, unread), nil
+}
+
+// MarkRead marks a notification as read.
+//	@Summary		Mark notification as read
+//	@Description	Marks a notification of the user as read. New actors start a new notification afterwards.
+//	@Tags			notification
+//	@Accept			json
+//	@Produce		json
+//	@Param			notification	body		request_model.NotificationActionRequest	true	"Notification to mark"
+//	@Success		200				{object}	request_model.SuccessCreationResponse
+//	@Failure		400				{object}	map[string]string	"Invalid Input"
+//	@Failure		500				{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/notification/mark_read [post]
+func (handler *NotificationHandler) MarkRead(request request_model.NotificationActionRequest) (*request_model.SuccessCreationResponse, error) {
+	if err := handler.notificationService.MarkRead(request.Username, request.NotificationId); err != nil {
+		log.Printf("Error marking notification as read: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully marked notification as read"), nil
+}
+
+// MarkAllRead marks every notification of a user as read.
+//	@Summary		Mark all notifications as read
+//	@Description	Marks every unread notification of the user as read.
+//	@Tags			notification
+//	@Accept			json
+//	@Produce		json
+//	@Param			user	body		request_model.MarkAllNotificationsReadRequest	true	"User data"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/notification/mark_all_read [post]
+func (handler *NotificationHandler) MarkAllRead(request request_model.MarkAllNotificationsReadRequest) (*request_model.SuccessCreationResponse, error) {
+	if err := handler.notificationService.MarkAllRead(request.Username); err != nil {
+		log.Printf("Error marking notifications as read: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully marked notifications as read"), nil
+}
+
+// DeleteNotification deletes a notification.
+//	@Summary		Delete notification
+//	@Description	Deletes a notification of the user.
+//	@Tags			notification
+//	@Accept			json
+//	@Produce		json
+//	@Param			notification	body		request_model.NotificationActionRequest	true	"Notification to delete"
+//	@Success		200				{object}	request_model.SuccessCreationResponse
+//	@Failure		400				{object}	map[string]string	"Invalid Input"
+//	@Failure		500				{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/notification/delete [post]
+func (handler *NotificationHandler) DeleteNotification(request request_model.NotificationActionRequest) (*request_model.SuccessCreationResponse, error) {
+	if err := handler.notificationService.Delete(request.Username, request.NotificationId); err != nil {
+		log.Printf("Error deleting notification: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully deleted notification"), nil
+}
+
+// ListPreferences lists which types of notification a user muted.
+//	@Summary		List notification preferences
+//	@Description	Lists every type of notification, telling whether the user muted it.
+//	@Tags			notification
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username of the user"
+//	@Success		200			{object}	request_model.ListNotificationPreferencesResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/notification/preferences [get]
+func (handler *NotificationHandler) ListPreferences(request request_model.ListNotificationPreferencesRequest) (*request_model.ListNotificationPreferencesResponse, error) {
+	preferences, err := handler.notificationService.ListPreferences(request.Username)
+	if err != nil {
+		log.Printf("Error listing notification preferences: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewListNotificationPreferencesResponse(preferences), nil
+}
+
+// SetPreference mutes or unmutes a type of notification.
+//	@Summary		Set notification preference
+//	@Description	Mutes or unmutes a type of notification: post_liked, post_commented, post_mention, friend_request, friend_accepted, community_joined or chat_message. Muted types are not notified.
+//	@Tags			notification
+//	@Accept			json
+//	@Produce		json
+//	@Param			preference	body		request_model.SetNotificationPreferenceRequest	true	"Preference data"
+//	@Success		200			{object}	request_model.SuccessCreationResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/notification/set_preference [post]
+func (handler *NotificationHandler) SetPreference(request request_model.SetNotificationPreferenceRequest) (*request_model.SuccessCreationResponse, error) {
+	if err := handler.notificationService.SetPreference(request.Username, request.Type, request.Muted); err != nil {
+		log.Printf("Error setting notification preference: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully set notification preference"), nil
+}
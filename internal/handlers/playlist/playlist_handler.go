@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	media_handlers "symphony-api/internal/handlers/media"
 	"symphony-api/internal/persistence/model"
 	mongo_repository "symphony-api/internal/persistence/repository/mongo"
+	"symphony-api/internal/persistence/service"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -13,11 +15,12 @@ import (
 )
 
 type PlaylistHandler struct {
-	repo *mongo_repository.PlaylistRepository
+	repo         *mongo_repository.PlaylistRepository
+	mediaService *service.MediaService
 }
 
-func NewPlaylistHandler(repo *mongo_repository.PlaylistRepository) *PlaylistHandler {
-	return &PlaylistHandler{repo: repo}
+func NewPlaylistHandler(repo *mongo_repository.PlaylistRepository, mediaService *service.MediaService) *PlaylistHandler {
+	return &PlaylistHandler{repo: repo, mediaService: mediaService}
 }
 
 func (h *PlaylistHandler) AddRoutes(server interface {
@@ -29,6 +32,7 @@ func (h *PlaylistHandler) AddRoutes(server interface {
 		r.Get("/user/{username}", h.GetPlaylistsByUsername)
 		r.Post("/create", h.CreatePlaylist)
 		r.Post("/{id}/songs", h.AddSongToPlaylist)
+		r.Post("/{id}/cover", h.UploadCover)
 	})
 }
 
@@ -263,3 +267,52 @@ func (h *PlaylistHandler) AddSongToPlaylist(w http.ResponseWriter, r *http.Reque
 		return
 	}
 }
+
+// UploadCover uploads an image and sets it as the cover of a playlist
+// @Summary Upload playlist cover
+// @Description Upload a jpeg, png or gif image as the "file" field of a multipart form and use it as the playlist image_url
+// @Tags playlists
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Playlist ID"
+// @Param file formData file true "Cover image"
+// @Success 200 {object} model.Playlist
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /playlists/{id}/cover [post]
+func (h *PlaylistHandler) UploadCover(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	playlistID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid playlist ID", http.StatusBadRequest)
+		return
+	}
+
+	playlist, err := h.repo.GetPlaylistByID(ctx, playlistID)
+	if err != nil {
+		http.Error(w, "Playlist not found", http.StatusNotFound)
+		return
+	}
+
+	media, ok := media_handlers.UploadFromRequest(w, r, h.mediaService)
+	if !ok {
+		return
+	}
+
+	playlist.ImageURL = h.mediaService.URL(media.Key)
+	playlist.UpdatedAt = time.Now()
+
+	if err := h.repo.UpdatePlaylist(ctx, playlistID, *playlist); err != nil {
+		http.Error(w, "Failed to update playlist", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(playlist); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -1,25 +1,52 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"log"
+	"time"
 	base_handlers "symphony-api/internal/handlers/base"
 	request_model "symphony-api/internal/handlers/model"
+	"symphony-api/internal/persistence/connectors/neo4j"
 	"symphony-api/internal/persistence/connectors/postgres"
 	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/persistence/service"
 	"symphony-api/internal/server"
 	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/realtime"
+	"symphony-api/internal/webhook"
 )
 
 type PostCrud struct {
 	repository repository.PostRepository
 	userRepository repository.UserRepository
+	attachmentService *service.AttachmentService
+	feedService *service.FeedService
+	privacyService *service.PrivacyService
+	eventService *service.EventService
+	notificationService *service.NotificationService
+	webhookService *service.WebhookService
 }
 
-func NewPostCrud(connection postgres.PostgreConnection) *PostCrud {
+func NewPostCrud(
+	connection postgres.PostgreConnection,
+	neo4jConnection neo4j.Neo4jConnection,
+	attachmentService *service.AttachmentService,
+	eventService *service.EventService,
+	webhookService *service.WebhookService,
+) *PostCrud {
+	userRepository := repository.NewUserRepository(connection, neo4jConnection)
+	postRepository := repository.NewPostRepository(connection)
+
 	return &PostCrud{
-		userRepository: *repository.NewUserRepository(connection, nil),
-		repository: *repository.NewPostRepository(connection),
+		userRepository: *userRepository,
+		repository: *postRepository,
+		attachmentService: attachmentService,
+		feedService: service.NewFeedService(postRepository, userRepository),
+		privacyService: service.NewPrivacyService(userRepository),
+		eventService: eventService,
+		notificationService: service.NewNotificationService(repository.NewNotificationRepository(connection), userRepository),
+		webhookService: webhookService,
 	}
 }
 
@@ -28,6 +55,14 @@ func (postCrud *PostCrud) AddRoutes(server server.Server) {
 		"/api/post/create",
 		base_handlers.CreatePostMethodHandler(postCrud.CreatePostHandler),
 	)
+	server.AddRoute(
+		"/api/post/like",
+		base_handlers.CreatePostMethodHandler(postCrud.LikePostHandler),
+	)
+	server.AddRoute(
+		"/api/post/comment",
+		base_handlers.CreatePostMethodHandler(postCrud.CommentPostHandler),
+	)
 	server.AddRoute(
 		"/api/post/get-post-by-id",
 		base_handlers.CreateGetMethodHandler(postCrud.GetPostByIdHandler),
@@ -36,11 +71,27 @@ func (postCrud *PostCrud) AddRoutes(server server.Server) {
 		"/api/post/get-by-username",
 		base_handlers.CreateGetMethodHandler(postCrud.GetPostsByUsernameHandler),
 	)
+	server.AddRoute(
+		"/api/post/by-tag",
+		base_handlers.CreateGetMethodHandler(postCrud.GetPostsByTagHandler),
+	)
+	server.AddRoute(
+		"/api/post/trending-tags",
+		base_handlers.CreateGetMethodHandler(postCrud.GetTrendingTagsHandler),
+	)
+	server.AddRoute(
+		"/api/post/mentions",
+		base_handlers.CreateGetMethodHandler(postCrud.GetPostsMentioningUserHandler),
+	)
+	server.AddRoute(
+		"/api/post/feed",
+		base_handlers.CreateGetMethodHandler(postCrud.GetFeedHandler),
+	)
 }
 
 // CreatePostHandler handles the creation of a new post.
 //	@Summary		Create a new post
-//	@Description	Creates a new post in the system.
+//	@Description	Creates a new post in the system. The post may reference a song, artist or playlist.
 //	@Tags			Post
 //	@Accept			json
 //	@Produce		json
@@ -58,12 +109,29 @@ func (postCrud *PostCrud) CreatePostHandler(request request_model.CreatePostRequ
 		return nil, errors.New("error creating post")
 	}
 
+	var attachment *model.Attachment
+	if request.Attachment != nil {
+		attachment, err = request.Attachment.ToAttachment()
+		if err != nil {
+			return nil, err
+		}
+
+		ctx := context.Background()
+		if err := postCrud.attachmentService.Validate(ctx, attachment); err != nil {
+			return nil, err
+		}
+		if err := postCrud.attachmentService.Hydrate(ctx, []*model.Attachment{attachment}); err != nil {
+			log.Printf("Error hydrating post attachment: %v", err)
+		}
+	}
+
 	createdPost, err := postCrud.repository.Put(
 		&model.Post{
-			UserId:    user.UserId,
-			Text:      request.Text,
-			UrlFoto:   request.UrlFoto,
-			LikeCount: request.LikeCount,
+			UserId:     user.UserId,
+			Text:       request.Text,
+			UrlFoto:    request.UrlFoto,
+			LikeCount:  request.LikeCount,
+			Attachment: attachment,
 		},
 	)
 
@@ -72,37 +140,211 @@ func (postCrud *PostCrud) CreatePostHandler(request request_model.CreatePostRequ
 		return nil, errors.New("error creating post")
 	}
 
-	return request_model.NewCreatePostResponse(createdPost), nil
+	hashtags, mentions := postCrud.indexPostText(createdPost, user)
+	postCrud.notifyMentions(createdPost, user, mentions)
+
+	// Partners only learn about posts anyone can see.
+	if model.CanSeeProfile(user.ProfileVisibility, false, false) {
+		postCrud.webhookService.Publish(webhook.EVENT_POST_CREATED, map[string]any{
+			"post_id": createdPost.PostId,
+			"username": user.Username,
+		})
+	}
+
+	return request_model.NewCreatePostResponse(createdPost, hashtags, mentions), nil
+}
+
+// indexPostText stores the hashtags and mentions found in the text of a post.
+// Mentions of usernames that do not exist or are in a block relation with the
+// author are left as plain text. The post is already created at this point,
+// so failures are only logged.
+func (postCrud *PostCrud) indexPostText(post *model.Post, author *model.User) ([]string, []*model.User) {
+	hashtags := model.ExtractHashtags(post.Text)
+	if err := postCrud.repository.AddHashtags(post.PostId, hashtags); err != nil {
+		log.Printf("Error storing hashtags of post %d: %v", post.PostId, err)
+	}
+
+	mentions, _, err := postCrud.userRepository.GetByUsernames(model.ExtractMentions(post.Text))
+	if err != nil {
+		log.Printf("Error resolving mentions of post %d: %v", post.PostId, err)
+		mentions = make([]*model.User, 0)
+	}
+	mentions, err = postCrud.privacyService.FilterBlockedUsers(author.Username, mentions)
+	if err != nil {
+		log.Printf("Error checking blocks of mentions of post %d: %v", post.PostId, err)
+		mentions = make([]*model.User, 0)
+	}
+	if err := postCrud.repository.AddMentions(post.PostId, mentions); err != nil {
+		log.Printf("Error storing mentions of post %d: %v", post.PostId, err)
+	}
+
+	return hashtags, mentions
+}
+
+// notifyMentions tells the users mentioned in a post about it, except its
+// author. The mentions were already cleared of users in a block relation with
+// the author by indexPostText.
+func (postCrud *PostCrud) notifyMentions(post *model.Post, author *model.User, mentions []*model.User) {
+	mentioned := make([]*model.User, 0, len(mentions))
+	for _, user := range mentions {
+		if user.UserId == author.UserId {
+			continue
+		}
+		mentioned = append(mentioned, user)
+		postCrud.eventService.Publish(user, realtime.EVENT_POST_MENTION, map[string]any{
+			"post_id": post.PostId,
+			"username": author.Username,
+		})
+	}
+	if len(mentioned) > 0 {
+		postCrud.notificationService.Notify(mentioned, model.NOTIFICATION_POST_MENTION, author, post.PostId)
+	}
+}
+
+// LikePostHandler likes a post.
+//	@Summary		Like a post
+//	@Description	Likes a post on behalf of the user and tells its author. Liking a post again has no effect.
+//	@Tags			Post
+//	@Accept			json
+//	@Produce		json
+//	@Param			like	body		request_model.LikePostRequest	true	"Like data"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/post/like [post]
+func (postCrud *PostCrud) LikePostHandler(request request_model.LikePostRequest) (*request_model.SuccessCreationResponse, error) {
+	user, post, author, err := postCrud.getVisiblePost(request.Username, request.PostId)
+	if err != nil {
+		return nil, err
+	}
+
+	liked, err := postCrud.repository.Like(user.UserId, post.PostId)
+	if err != nil {
+		log.Printf("Error liking post: %v", err)
+		return nil, errors.New("error liking post")
+	}
+
+	if liked && author.UserId != user.UserId {
+		postCrud.eventService.Publish(author, realtime.EVENT_POST_LIKED, map[string]any{
+			"post_id": post.PostId,
+			"username": user.Username,
+		})
+		postCrud.notificationService.Notify([]*model.User{author}, model.NOTIFICATION_POST_LIKED, user, post.PostId)
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully liked post"), nil
+}
+
+// CommentPostHandler comments on a post.
+//	@Summary		Comment on a post
+//	@Description	Adds a comment of the user to a post and tells its author.
+//	@Tags			Post
+//	@Accept			json
+//	@Produce		json
+//	@Param			comment	body		request_model.CommentPostRequest	true	"Comment data"
+//	@Success		200		{object}	request_model.CommentPostResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/post/comment [post]
+func (postCrud *PostCrud) CommentPostHandler(request request_model.CommentPostRequest) (*request_model.CommentPostResponse, error) {
+	if err := model.ValidateComment(request.Text); err != nil {
+		return nil, err
+	}
+
+	user, post, author, err := postCrud.getVisiblePost(request.Username, request.PostId)
+	if err != nil {
+		return nil, err
+	}
+
+	commentId, err := postCrud.repository.AddComment(user.UserId, post.PostId, request.Text)
+	if err != nil {
+		log.Printf("Error commenting on post: %v", err)
+		return nil, errors.New("error commenting on post")
+	}
+
+	if author.UserId != user.UserId {
+		postCrud.eventService.Publish(author, realtime.EVENT_POST_COMMENTED, map[string]any{
+			"post_id": post.PostId,
+			"comment_id": commentId,
+			"username": user.Username,
+		})
+		postCrud.notificationService.Notify([]*model.User{author}, model.NOTIFICATION_POST_COMMENTED, user, post.PostId)
+	}
+
+	return &request_model.CommentPostResponse{CommentId: commentId}, nil
+}
+
+// getVisiblePost returns the user along with a post they can see and its
+// author. Posts of profiles hidden from the user are reported as missing.
+func (postCrud *PostCrud) getVisiblePost(username string, postId int32) (*model.User, *model.Post, *model.User, error) {
+	user, err := postCrud.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, nil, nil, errors.New("user does not exist")
+	}
+
+	post, author, err := postCrud.getPostVisibleTo(user.Username, postId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return user, post, author, nil
+}
+
+// getPostVisibleTo returns a post the viewer can see and its author. An empty
+// viewer stands for an anonymous request. Posts of profiles hidden from the
+// viewer are reported as missing.
+func (postCrud *PostCrud) getPostVisibleTo(viewer string, postId int32) (*model.Post, *model.User, error) {
+	post, err := postCrud.repository.GetById(postId)
+	if err != nil {
+		log.Printf("Error getting post: %v", err)
+		return nil, nil, errors.New("error getting post")
+	}
+	if post == nil {
+		return nil, nil, errors.New("post not found")
+	}
+
+	author, err := postCrud.userRepository.GetById(int64(post.UserId))
+	if err != nil {
+		log.Printf("Error getting author of post %d: %v", post.PostId, err)
+		return nil, nil, errors.New("post not found")
+	}
+	if err := postCrud.privacyService.CheckCanViewProfile(viewer, author); err != nil {
+		return nil, nil, errors.New("post not found")
+	}
+
+	return post, author, nil
 }
 
 // GetPostByIdHandler retrieves a post by its ID.
 //	@Summary		Get post by ID
-//	@Description	Retrieves a post using its unique identifier.
+//	@Description	Retrieves a post using its unique identifier. Posts of profiles the viewer can not see are reported as not found.
 //	@Tags			Post
 //	@Accept			json
 //	@Produce		json
 //	@Param			post_id	query		int	true	"Post ID"
+//	@Param			viewer	query		string	false	"User reading the post"
 //	@Success		200		{object}	request_model.GetPostByIdResponse
 //	@Failure		400		{object}	map[string]string	"Invalid Input"
 //	@Failure		404		{object}	map[string]string	"Post Not Found"
 //	@Failure		500		{object}	map[string]string	"Internal Server Error"
 //	@Router			/api/post/get-post-by-id [get]
 func (postCrud *PostCrud) GetPostByIdHandler(request request_model.GetPostByIdRequest) (*request_model.GetPostByIdResponse, error) {
-	post, err := postCrud.repository.GetById(request.PostId)
+	post, _, err := postCrud.getPostVisibleTo(request.Viewer, request.PostId)
 	if err != nil {
-		log.Printf("Error getting post: %v", err)
-		return nil, errors.New("error getting post")
+		return nil, err
 	}
+	postCrud.hydrateAttachments([]*model.Post{post})
 	return request_model.NewGetPostByIdResponse(post), nil
 }
 
 // GetPostsByUserIdHandler retrieves all posts for a specific user.
 //	@Summary		Get posts by username
-//	@Description	Retrieves all posts created by a specific user.
+//	@Description	Retrieves all posts created by a specific user. The posts are only returned if the privacy settings of the user allow the viewer to see their profile.
 //	@Tags			Post
 //	@Accept			json
 //	@Produce		json
 //	@Param			username	query		int	true	"Username"
+//	@Param			viewer		query		string	false	"User reading the posts"
 //	@Success		200		{object}	request_model.GetPostsByUsernameResponse
 //	@Failure		400		{object}	map[string]string	"Invalid Input"
 //	@Failure		500		{object}	map[string]string	"Internal Server Error"
@@ -113,10 +355,145 @@ func (postCrud *PostCrud) GetPostsByUsernameHandler(request request_model.GetPos
 		log.Printf("Error getting posts: %v", err)
 		return nil, errors.New("error getting posts")
 	}
+	if err := postCrud.privacyService.CheckCanViewProfile(request.Viewer, user); err != nil {
+		return nil, err
+	}
 	posts, err := postCrud.repository.GetByUserId(user.UserId)
 	if err != nil {
 		log.Printf("Error getting posts: %v", err)
 		return nil, errors.New("error getting posts")
 	}
+	postCrud.hydrateAttachments(posts)
 	return request_model.NewGetPostsByUsernameResponse(posts), nil
 }
+
+// hydrateAttachments fills the summary of the attachments of all posts in a
+// single batch. A failure here only degrades the response, so it is logged
+// instead of failing the request.
+func (postCrud *PostCrud) hydrateAttachments(posts []*model.Post) {
+	attachments := make([]*model.Attachment, 0)
+	for _, post := range posts {
+		if post.Attachment != nil {
+			attachments = append(attachments, post.Attachment)
+		}
+	}
+
+	if len(attachments) == 0 {
+		return
+	}
+
+	err := postCrud.attachmentService.Hydrate(context.Background(), attachments)
+	if err != nil {
+		log.Printf("Error hydrating post attachments: %v", err)
+	}
+}
+
+// GetPostsByTagHandler retrieves all posts with a hashtag.
+//	@Summary		Get posts by hashtag
+//	@Description	Retrieves all posts whose text contains the hashtag, newest first. The leading '#' is optional and the match is case insensitive. Posts of profiles the viewer can not see are left out.
+//	@Tags			Post
+//	@Accept			json
+//	@Produce		json
+//	@Param			tag		query		string	true	"Hashtag"
+//	@Param			viewer	query		string	false	"User reading the posts"
+//	@Success		200		{object}	request_model.ListPostsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/post/by-tag [get]
+func (postCrud *PostCrud) GetPostsByTagHandler(request request_model.GetPostsByTagRequest) (*request_model.ListPostsResponse, error) {
+	posts, err := postCrud.repository.GetByHashtag(request.Tag)
+	if err != nil {
+		log.Printf("Error getting posts by tag: %v", err)
+		return nil, errors.New("error getting posts")
+	}
+	posts, err = postCrud.privacyService.FilterVisiblePosts(request.Viewer, posts)
+	if err != nil {
+		log.Printf("Error filtering posts by tag: %v", err)
+		return nil, errors.New("error getting posts")
+	}
+	postCrud.hydrateAttachments(posts)
+	return request_model.NewListPostsResponse(posts), nil
+}
+
+// GetTrendingTagsHandler lists the most used hashtags in a time window.
+//	@Summary		Get trending hashtags
+//	@Description	Lists the hashtags used by most posts in the last hours.
+//	@Tags			Post
+//	@Accept			json
+//	@Produce		json
+//	@Param			hours	query		int	false	"Size of the time window in hours (default is 24)"
+//	@Param			limit	query		int	false	"Number of hashtags to retrieve (default is 10)"
+//	@Success		200		{object}	request_model.GetTrendingTagsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/post/trending-tags [get]
+func (postCrud *PostCrud) GetTrendingTagsHandler(request request_model.GetTrendingTagsRequest) (*request_model.GetTrendingTagsResponse, error) {
+	if request.Hours <= 0 || request.Limit <= 0 {
+		return nil, errors.New("hours and limit must be greater than zero")
+	}
+
+	since := time.Now().Add(-time.Duration(request.Hours) * time.Hour)
+	hashtags, err := postCrud.repository.ListTrendingHashtags(since, request.Limit)
+	if err != nil {
+		log.Printf("Error getting trending tags: %v", err)
+		return nil, errors.New("error getting trending tags")
+	}
+	return request_model.NewGetTrendingTagsResponse(hashtags), nil
+}
+
+// GetPostsMentioningUserHandler retrieves all posts that mention a user.
+//	@Summary		Get posts mentioning a user
+//	@Description	Retrieves all posts whose text mentions the user with @username, newest first. The user must be visible to the viewer, and posts of profiles the viewer can not see are left out.
+//	@Tags			Post
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Param			viewer		query		string	false	"User reading the posts"
+//	@Success		200		{object}	request_model.ListPostsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/post/mentions [get]
+func (postCrud *PostCrud) GetPostsMentioningUserHandler(request request_model.GetPostsMentioningUserRequest) (*request_model.ListPostsResponse, error) {
+	user, err := postCrud.userRepository.GetByUsername(request.Username)
+	if err != nil {
+		log.Printf("Error getting posts: %v", err)
+		return nil, errors.New("error getting posts")
+	}
+	if err := postCrud.privacyService.CheckCanViewProfile(request.Viewer, user); err != nil {
+		return nil, err
+	}
+	posts, err := postCrud.repository.GetByMentionedUserId(user.UserId)
+	if err != nil {
+		log.Printf("Error getting posts: %v", err)
+		return nil, errors.New("error getting posts")
+	}
+	posts, err = postCrud.privacyService.FilterVisiblePosts(request.Viewer, posts)
+	if err != nil {
+		log.Printf("Error filtering posts: %v", err)
+		return nil, errors.New("error getting posts")
+	}
+	postCrud.hydrateAttachments(posts)
+	return request_model.NewListPostsResponse(posts), nil
+}
+
+// GetFeedHandler retrieves the feed of a user.
+//	@Summary		Get the feed of a user
+//	@Description	Retrieves the newest posts of the user, their friends and the users they follow, together with the posts attached to the artists they follow. Posts of profiles the user can not see are left out.
+//	@Tags			Post
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Param			limit		query		int		false	"Number of posts to retrieve (default is 20)"
+//	@Success		200		{object}	request_model.ListPostsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/post/feed [get]
+func (postCrud *PostCrud) GetFeedHandler(request request_model.GetFeedRequest) (*request_model.ListPostsResponse, error) {
+	posts, err := postCrud.feedService.GetFeed(request.Username, request.Limit)
+	if err != nil {
+		log.Printf("Error getting feed: %v", err)
+		return nil, err
+	}
+	postCrud.hydrateAttachments(posts)
+	return request_model.NewListPostsResponse(posts), nil
+}
@@ -0,0 +1,148 @@
+package story_handlers
+
+import (
+	"context"
+	"log"
+	base_handlers "symphony-api/internal/handlers/base"
+	request_model "symphony-api/internal/handlers/model"
+	"symphony-api/internal/persistence/connectors/neo4j"
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/repository"
+	mongo_repository "symphony-api/internal/persistence/repository/mongo"
+	"symphony-api/internal/persistence/service"
+	"symphony-api/internal/server"
+)
+
+type StoryHandler struct {
+	storyService *service.StoryService
+}
+
+func NewStoryHandler(
+	connection postgres.PostgreConnection,
+	neo4jConnection neo4j.Neo4jConnection,
+	storyRepository *mongo_repository.StoryRepository,
+	attachmentService *service.AttachmentService,
+) *StoryHandler {
+	return &StoryHandler{
+		storyService: service.NewStoryService(
+			storyRepository,
+			repository.NewUserRepository(connection, neo4jConnection),
+			attachmentService,
+		),
+	}
+}
+
+func (handler *StoryHandler) AddRoutes(server server.Server) {
+	server.AddRoute("/api/story/create", base_handlers.CreatePostMethodHandler(handler.CreateStory))
+	server.AddRoute("/api/story/tray", base_handlers.CreateGetMethodHandler(handler.GetStoryTray))
+	server.AddRoute("/api/story/list", base_handlers.CreateGetMethodHandler(handler.ListStories))
+	server.AddRoute("/api/story/view", base_handlers.CreatePostMethodHandler(handler.ViewStory))
+	server.AddRoute("/api/story/viewers", base_handlers.CreateGetMethodHandler(handler.ListStoryViewers))
+}
+
+// CreateStory shares what a user is listening to.
+//	@Summary		Create a story
+//	@Description	Shares a song with a caption with the friends of the user. The story expires after 24 hours.
+//	@Tags			story
+//	@Accept			json
+//	@Produce		json
+//	@Param			story	body		request_model.CreateStoryRequest	true	"Story data"
+//	@Success		200		{object}	request_model.StoryResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/story/create [post]
+func (handler *StoryHandler) CreateStory(request request_model.CreateStoryRequest) (*request_model.StoryResponse, error) {
+	story, song, err := handler.storyService.CreateStory(context.Background(), request.Username, request.SongId, request.Caption)
+	if err != nil {
+		log.Printf("Error creating story: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewStoryResponse(story, song), nil
+}
+
+// GetStoryTray lists the friends with active stories.
+//	@Summary		Get stories tray
+//	@Description	Lists the friends of the user that have active stories. Friends with stories the user has not seen come first.
+//	@Tags			story
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Success		200		{object}	request_model.GetStoryTrayResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/story/tray [get]
+func (handler *StoryHandler) GetStoryTray(request request_model.GetStoryTrayRequest) (*request_model.GetStoryTrayResponse, error) {
+	items, err := handler.storyService.GetStoryTray(context.Background(), request.Username)
+	if err != nil {
+		log.Printf("Error getting story tray: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewGetStoryTrayResponse(items), nil
+}
+
+// ListStories lists the active stories of a user.
+//	@Summary		List stories of a user
+//	@Description	Lists the active stories of a user. Only the user and their friends can see them.
+//	@Tags			story
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Author of the stories"
+//	@Param			viewer		query		string	true	"User that is looking at the stories"
+//	@Success		200		{object}	request_model.ListStoriesResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/story/list [get]
+func (handler *StoryHandler) ListStories(request request_model.ListStoriesRequest) (*request_model.ListStoriesResponse, error) {
+	stories, songs, err := handler.storyService.ListStories(context.Background(), request.Username, request.Viewer)
+	if err != nil {
+		log.Printf("Error listing stories: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewListStoriesResponse(stories, songs), nil
+}
+
+// ViewStory marks a story as seen.
+//	@Summary		View a story
+//	@Description	Records that a friend of the author has seen the story.
+//	@Tags			story
+//	@Accept			json
+//	@Produce		json
+//	@Param			view	body		request_model.ViewStoryRequest	true	"Story and viewer"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/story/view [post]
+func (handler *StoryHandler) ViewStory(request request_model.ViewStoryRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.storyService.ViewStory(context.Background(), request.StoryId, request.Viewer)
+	if err != nil {
+		log.Printf("Error viewing story: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully viewed story"), nil
+}
+
+// ListStoryViewers lists who has seen a story.
+//	@Summary		List viewers of a story
+//	@Description	Lists the friends that have seen a story. Only available to the author.
+//	@Tags			story
+//	@Accept			json
+//	@Produce		json
+//	@Param			story_id	query		string	true	"Story ID"
+//	@Param			username	query		string	true	"Author of the story"
+//	@Success		200		{object}	request_model.ListStoryViewersResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/story/viewers [get]
+func (handler *StoryHandler) ListStoryViewers(request request_model.ListStoryViewersRequest) (*request_model.ListStoryViewersResponse, error) {
+	views, err := handler.storyService.ListViewers(context.Background(), request.StoryId, request.Username)
+	if err != nil {
+		log.Printf("Error listing story viewers: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewListStoryViewersResponse(views), nil
+}
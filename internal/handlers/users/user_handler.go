@@ -1,29 +1,62 @@
 package user_handlers
 
 import (
+	"context"
 	"errors"
 	base_handlers "symphony-api/internal/handlers/base"
 	request_model "symphony-api/internal/handlers/model"
 	"symphony-api/internal/persistence/connectors/neo4j"
 	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/model"
 	"symphony-api/internal/persistence/repository"
+	mongo_repository "symphony-api/internal/persistence/repository/mongo"
 	"symphony-api/internal/persistence/service"
 	"symphony-api/internal/server"
+	"symphony-api/internal/webhook"
 )
 
 type UserHandler struct {
 	repository *repository.UserRepository
 	communityService *service.CommunityService
+	friendshipService *service.FriendshipService
+	followService *service.FollowService
+	privacyService *service.PrivacyService
+	profileService *service.ProfileService
+	recommendationService *service.RecommendationService
+	socialPathService *service.SocialPathService
+	searchService *service.UserSearchService
+	webhookService *service.WebhookService
 }
 
-func NewUserHandler(connection postgres.PostgreConnection, neo4jConnection neo4j.Neo4jConnection) *UserHandler {
+func NewUserHandler(
+	connection postgres.PostgreConnection,
+	neo4jConnection neo4j.Neo4jConnection,
+	artistRepository *mongo_repository.ArtistRepository,
+	playlistRepository *mongo_repository.PlaylistRepository,
+	storyRepository *mongo_repository.StoryRepository,
+	eventService *service.EventService,
+	webhookService *service.WebhookService,
+) *UserHandler {
 	userRepository := repository.NewUserRepository(connection, neo4jConnection)
+	communityRepository := repository.NewCommunityRepository(connection)
+	privacyService := service.NewPrivacyService(userRepository)
+	notificationService := service.NewNotificationService(repository.NewNotificationRepository(connection), userRepository)
 	return &UserHandler{
 		repository: userRepository,
 		communityService: service.NewCommunityService(
-			repository.NewCommunityRepository(connection),
+			communityRepository,
 			userRepository,
+			notificationService,
+			webhookService,
 		),
+		friendshipService: service.NewFriendshipService(userRepository, eventService, notificationService),
+		followService: service.NewFollowService(userRepository, artistRepository),
+		privacyService: privacyService,
+		profileService: service.NewProfileService(userRepository, playlistRepository, storyRepository),
+		recommendationService: service.NewRecommendationService(userRepository, communityRepository, playlistRepository),
+		socialPathService: service.NewSocialPathService(userRepository, communityRepository, privacyService),
+		searchService: service.NewUserSearchService(userRepository),
+		webhookService: webhookService,
 	}
 }
 
@@ -36,14 +69,52 @@ func (handler *UserHandler) AddRoutes(server server.Server) {
 		"/api/user/get_by_username", 
 		base_handlers.CreateGetMethodHandler(handler.GetUserByUsername),
 	)
+	server.AddRoute(
+		"/api/user/search",
+		base_handlers.CreateGetMethodHandler(handler.SearchUsers),
+	)
+	server.AddRoute(
+		"/api/user/update_profile",
+		base_handlers.CreatePostMethodHandler(handler.UpdateProfile),
+	)
 	server.AddRoute(
 		"/api/user/list_communities", 
 		base_handlers.CreateGetMethodHandler(handler.ListUserCommunities),
 	)
 
 	server.AddRoute(
-		"/api/user/create_friendship", 
-		base_handlers.CreatePostMethodHandler(handler.CreateFriendship),
+		"/api/user/send_friend_request",
+		base_handlers.CreatePostMethodHandler(handler.SendFriendRequest),
+	)
+
+	server.AddRoute(
+		"/api/user/accept_friend_request",
+		base_handlers.CreatePostMethodHandler(handler.AcceptFriendRequest),
+	)
+
+	server.AddRoute(
+		"/api/user/decline_friend_request",
+		base_handlers.CreatePostMethodHandler(handler.DeclineFriendRequest),
+	)
+
+	server.AddRoute(
+		"/api/user/cancel_friend_request",
+		base_handlers.CreatePostMethodHandler(handler.CancelFriendRequest),
+	)
+
+	server.AddRoute(
+		"/api/user/list_incoming_friend_requests",
+		base_handlers.CreateGetMethodHandler(handler.ListIncomingFriendRequests),
+	)
+
+	server.AddRoute(
+		"/api/user/list_outgoing_friend_requests",
+		base_handlers.CreateGetMethodHandler(handler.ListOutgoingFriendRequests),
+	)
+
+	server.AddRoute(
+		"/api/user/unfriend",
+		base_handlers.CreatePostMethodHandler(handler.Unfriend),
 	)
 
 	server.AddRoute(
@@ -51,6 +122,71 @@ func (handler *UserHandler) AddRoutes(server server.Server) {
 		base_handlers.CreateGetMethodHandler(handler.GetUserFriends),
 	)
 
+	server.AddRoute(
+		"/api/user/follow",
+		base_handlers.CreatePostMethodHandler(handler.FollowUser),
+	)
+
+	server.AddRoute(
+		"/api/user/unfollow",
+		base_handlers.CreatePostMethodHandler(handler.UnfollowUser),
+	)
+
+	server.AddRoute(
+		"/api/user/follow_artist",
+		base_handlers.CreatePostMethodHandler(handler.FollowArtist),
+	)
+
+	server.AddRoute(
+		"/api/user/unfollow_artist",
+		base_handlers.CreatePostMethodHandler(handler.UnfollowArtist),
+	)
+
+	server.AddRoute(
+		"/api/user/follow_counts",
+		base_handlers.CreateGetMethodHandler(handler.GetFollowCounts),
+	)
+
+	server.AddRoute(
+		"/api/user/list_followers",
+		base_handlers.CreateGetMethodHandler(handler.ListFollowers),
+	)
+
+	server.AddRoute(
+		"/api/user/list_following",
+		base_handlers.CreateGetMethodHandler(handler.ListFollowing),
+	)
+
+	server.AddRoute(
+		"/api/user/list_followed_artists",
+		base_handlers.CreateGetMethodHandler(handler.ListFollowedArtists),
+	)
+
+	server.AddRoute(
+		"/api/user/list_artist_followers",
+		base_handlers.CreateGetMethodHandler(handler.ListArtistFollowers),
+	)
+
+	server.AddRoute(
+		"/api/user/block",
+		base_handlers.CreatePostMethodHandler(handler.BlockUser),
+	)
+
+	server.AddRoute(
+		"/api/user/unblock",
+		base_handlers.CreatePostMethodHandler(handler.UnblockUser),
+	)
+
+	server.AddRoute(
+		"/api/user/list_blocked",
+		base_handlers.CreateGetMethodHandler(handler.ListBlockedUsers),
+	)
+
+	server.AddRoute(
+		"/api/user/update_privacy",
+		base_handlers.CreatePostMethodHandler(handler.UpdatePrivacySettings),
+	)
+
 	server.AddRoute(
 		"/api/user/like_genre", 
 		base_handlers.CreatePostMethodHandler(handler.LikeGenre),
@@ -65,6 +201,16 @@ func (handler *UserHandler) AddRoutes(server server.Server) {
 		"/api/user/get_friends_recommendations_on_genre", 
 		base_handlers.CreateGetMethodHandler(handler.GetFriendRecommendationByGenre),
 	)
+
+	server.AddRoute(
+		"/api/user/recommendations",
+		base_handlers.CreateGetMethodHandler(handler.GetFriendRecommendations),
+	)
+
+	server.AddRoute(
+		"/api/user/connection",
+		base_handlers.CreateGetMethodHandler(handler.GetSocialConnection),
+	)
 }
 
 // CreateUserHandler handles the creation of a new user.
@@ -88,12 +234,16 @@ func (handler *UserHandler) CreateUserHandler(request request_model.CreateUserRe
 		return nil, errors.New("error creating user")
 	}
 
+	handler.webhookService.Publish(webhook.EVENT_USER_CREATED, map[string]any{
+		"username": request.Username,
+	})
+
 	return request_model.NewSuccessCreationResponse("Successfully created user"), nil
 }
 
 // Return user data based on their username
 //	@Summary		Get a user by its name
-//	@Description	Return user data based on their username
+//	@Description	Return user data based on their username. The profile is only returned if the privacy settings of the user allow the viewer to see it.
 //	@Tags			User
 //	@Accept			json
 //	@Produce		json
@@ -109,7 +259,76 @@ func (handler *UserHandler) GetUserByUsername(request request_model.GetUserByUse
         return nil, errors.New("error fetching user")
 	}
 
-	return request_model.NewUserResponse(user), nil
+	err = handler.privacyService.CheckCanViewProfile(request.Viewer, user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := handler.privacyService.ResolveViews(request.Viewer, []*model.User{user})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewUserResponse(user, views.Of(user)), nil
+}
+
+// Searches users by username or full name
+//	@Summary		Search users
+//	@Description	Finds users whose username or full name start with or look like the query, ignoring case and accents. When viewer is given, users blocked by or blocking them are left out and friends, followed users and users with mutual friends rank higher.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			q		query		string	true	"Search query (2 to 100 characters)"
+//	@Param			viewer	query		string	false	"User searching"
+//	@Param			limit	query		int		false	"Maximum number of results (1 to 50)"	default(20)
+//	@Success		200		{object}	request_model.SearchUsersResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/search [get]
+func (handler *UserHandler) SearchUsers(request request_model.SearchUsersRequest) (*request_model.SearchUsersResponse, error) {
+	results, err := handler.searchService.Search(request.Viewer, request.Query, request.Limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*model.User, 0, len(results))
+	for _, result := range results {
+		users = append(users, result.User)
+	}
+
+	views, err := handler.privacyService.ResolveViews(request.Viewer, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSearchUsersResponse(results, views), nil
+}
+
+// Updates the profile of a user
+//	@Summary		Update the profile of a user
+//	@Description	Changes the fullname, bio, avatar, telephone and username of a user. Fields left out are not changed. A new username is applied to the social graph, playlists and stories too. The telephone is not returned, it is read from /api/user/me.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.UpdateProfileRequest	true	"Profile changes"
+//	@Success		200		{object}	request_model.UserResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/update_profile [post]
+func (handler *UserHandler) UpdateProfile(request request_model.UpdateProfileRequest) (*request_model.UserResponse, error) {
+	user, err := handler.profileService.UpdateProfile(context.Background(), request.Username, request.ToProfileUpdate())
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The user is named by the request, so it gets what a user naming
+	// themselves as viewer gets (see model.NewUserViews).
+	return request_model.NewUserResponse(user, model.USER_VIEW_FRIEND), nil
 }
 
 // Return all communities a user is part of
@@ -141,30 +360,165 @@ func (handler *UserHandler) ListUserCommunities(request request_model.ListUserCo
 	}, nil
 }
 
-// Creates a friendship between two users
-//	@Summary		Create a friendship 
-//	@Description	Creates a friendship between user with username1 and user with username2
+// Sends a friend request to another user
+//	@Summary		Send a friend request
+//	@Description	Sends a friend request from username to target. If target had already sent a request to username, that request is accepted instead.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.SendFriendRequestRequest	true	"Users"
+//	@Success		200		{object}	request_model.SendFriendRequestResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/send_friend_request [post]
+func (handler *UserHandler) SendFriendRequest(request request_model.SendFriendRequestRequest) (*request_model.SendFriendRequestResponse, error) {
+	accepted, err := handler.friendshipService.SendRequest(request.Username, request.Target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if accepted {
+		return &request_model.SendFriendRequestResponse{
+			Message: "Accepted pending friend request",
+			Accepted: true,
+		}, nil
+	}
+
+	return &request_model.SendFriendRequestResponse{
+		Message: "Successfully sent friend request",
+	}, nil
+}
+
+// Accepts a friend request
+//	@Summary		Accept a friend request
+//	@Description	Accepts the friend request requester sent to username, making them friends
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.AnswerFriendRequestRequest	true	"Users"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/accept_friend_request [post]
+func (handler *UserHandler) AcceptFriendRequest(request request_model.AnswerFriendRequestRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.friendshipService.AcceptRequest(request.Username, request.Requester)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully accepted friend request"), nil
+}
+
+// Declines a friend request
+//	@Summary		Decline a friend request
+//	@Description	Declines the friend request requester sent to username
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.AnswerFriendRequestRequest	true	"Users"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/decline_friend_request [post]
+func (handler *UserHandler) DeclineFriendRequest(request request_model.AnswerFriendRequestRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.friendshipService.DeclineRequest(request.Username, request.Requester)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully declined friend request"), nil
+}
+
+// Cancels a friend request
+//	@Summary		Cancel a friend request
+//	@Description	Cancels the friend request username sent to target
 //	@Tags			User
 //	@Accept			json
 //	@Produce		json
-//	@Param			post	body		request_model.CreateFriendshipRequest	true	"User data"
+//	@Param			post	body		request_model.CancelFriendRequestRequest	true	"Users"
 //	@Success		200		{object}	request_model.SuccessCreationResponse
 //	@Failure		400		{object}	map[string]string	"Invalid Input"
 //	@Failure		500		{object}	map[string]string	"Internal Server Error"
-//	@Router			/api/user/create_friendship [post]
-func (handler *UserHandler) CreateFriendship(request request_model.CreateFriendshipRequest) (*request_model.SuccessCreationResponse, error) {
-	err := handler.repository.AddFriendship(request.Username1, request.Username2)
+//	@Router			/api/user/cancel_friend_request [post]
+func (handler *UserHandler) CancelFriendRequest(request request_model.CancelFriendRequestRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.friendshipService.CancelRequest(request.Username, request.Target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully cancelled friend request"), nil
+}
+
+// List friend requests received by a user
+//	@Summary		List incoming friend requests
+//	@Description	List the pending friend requests received by a user, newest first
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Success		200		{object}	request_model.ListFriendRequestsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/list_incoming_friend_requests [get]
+func (handler *UserHandler) ListIncomingFriendRequests(request request_model.ListFriendRequestsRequest) (*request_model.ListFriendRequestsResponse, error) {
+	requests, err := handler.friendshipService.ListIncomingRequests(request.Username)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return request_model.NewSuccessCreationResponse("Successfully created friendship"), nil
+	return request_model.NewListFriendRequestsResponse(requests), nil
+}
+
+// List friend requests sent by a user
+//	@Summary		List outgoing friend requests
+//	@Description	List the pending friend requests sent by a user, newest first
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Success		200		{object}	request_model.ListFriendRequestsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/list_outgoing_friend_requests [get]
+func (handler *UserHandler) ListOutgoingFriendRequests(request request_model.ListFriendRequestsRequest) (*request_model.ListFriendRequestsResponse, error) {
+	requests, err := handler.friendshipService.ListOutgoingRequests(request.Username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewListFriendRequestsResponse(requests), nil
+}
+
+// Removes a friendship between two users
+//	@Summary		Unfriend a user
+//	@Description	Removes the friendship between username and friend
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.UnfriendRequest	true	"Users"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/unfriend [post]
+func (handler *UserHandler) Unfriend(request request_model.UnfriendRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.friendshipService.Unfriend(request.Username, request.Friend)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully removed friendship"), nil
 }
 
 // List all friendship a user has
 //	@Summary		List friends of a user
-//	@Description	List all friends of a user
+//	@Description	List all friends of a user. The list is only returned if the privacy settings of the user allow the viewer to see their profile, and users blocked by or blocking the viewer are left out.
 //	@Tags			User
 //	@Accept			json
 //	@Produce		json
@@ -174,23 +528,373 @@ func (handler *UserHandler) CreateFriendship(request request_model.CreateFriends
 //	@Failure		500		{object}	map[string]string	"Internal Server Error"
 //	@Router			/api/user/list_friends [get]
 func (handler *UserHandler) GetUserFriends(request request_model.GetUserFriendsRequest) (*request_model.GetUserFriendsResponse, error) {
+	if err := handler.checkCanViewList(request.Viewer, request.Username); err != nil {
+		return nil, err
+	}
+
 	friends, err := handler.repository.ListFriendshipsByUsername(request.Username)
 
 	if err != nil {
 		return nil, err
 	}
 
-	friendsModel := make([]*request_model.UserResponse, 0)
+	friends, err = handler.privacyService.FilterBlockedUsers(request.Viewer, friends)
 
-	for _, friend := range friends {
-		friendsModel = append(friendsModel, request_model.NewUserResponse(friend))
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := handler.privacyService.ResolveViews(request.Viewer, friends)
+
+	if err != nil {
+		return nil, err
 	}
 
 	return &request_model.GetUserFriendsResponse{
-		Friends: friendsModel,
+		Friends: request_model.NewUserResponses(friends, views),
+	}, nil
+}
+
+// checkCanViewList fails when the viewer is not allowed to see the profile of
+// the user whose list is requested.
+func (handler *UserHandler) checkCanViewList(viewer string, username string) error {
+	user, err := handler.repository.GetByUsername(username)
+
+	if err != nil {
+		return errors.New("user does not exist")
+	}
+
+	return handler.privacyService.CheckCanViewProfile(viewer, user)
+}
+
+// Follows another user
+//	@Summary		Follow a user
+//	@Description	Makes username follow target. Unlike friendship, following does not need to be accepted.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.FollowUserRequest	true	"Users"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/follow [post]
+func (handler *UserHandler) FollowUser(request request_model.FollowUserRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.followService.FollowUser(request.Username, request.Target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully followed user"), nil
+}
+
+// Stops following another user
+//	@Summary		Unfollow a user
+//	@Description	Makes username stop following target
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.FollowUserRequest	true	"Users"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/unfollow [post]
+func (handler *UserHandler) UnfollowUser(request request_model.FollowUserRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.followService.UnfollowUser(request.Username, request.Target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully unfollowed user"), nil
+}
+
+// Follows an artist
+//	@Summary		Follow an artist
+//	@Description	Makes username follow an artist. Posts attached to the artist show up in the feed of the user.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.FollowArtistRequest	true	"User and artist"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/follow_artist [post]
+func (handler *UserHandler) FollowArtist(request request_model.FollowArtistRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.followService.FollowArtist(context.Background(), request.Username, request.ArtistId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully followed artist"), nil
+}
+
+// Stops following an artist
+//	@Summary		Unfollow an artist
+//	@Description	Makes username stop following an artist
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.FollowArtistRequest	true	"User and artist"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/unfollow_artist [post]
+func (handler *UserHandler) UnfollowArtist(request request_model.FollowArtistRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.followService.UnfollowArtist(request.Username, request.ArtistId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully unfollowed artist"), nil
+}
+
+// Returns how many followers a user has and how many users and artists they follow
+//	@Summary		Get follow counts of a user
+//	@Description	Returns how many followers a user has and how many users and artists they follow
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Success		200		{object}	request_model.FollowCountsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/follow_counts [get]
+func (handler *UserHandler) GetFollowCounts(request request_model.GetFollowCountsRequest) (*request_model.FollowCountsResponse, error) {
+	counts, err := handler.followService.GetCounts(request.Username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewFollowCountsResponse(counts), nil
+}
+
+// List the followers of a user
+//	@Summary		List followers of a user
+//	@Description	List all users following a user. The list is only returned if the privacy settings of the user allow the viewer to see their profile, and users blocked by or blocking the viewer are left out.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Param			viewer		query		string	false	"User looking at the list"
+//	@Success		200		{object}	request_model.ListUsersResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/list_followers [get]
+func (handler *UserHandler) ListFollowers(request request_model.ListFollowsRequest) (*request_model.ListUsersResponse, error) {
+	if err := handler.checkCanViewList(request.Viewer, request.Username); err != nil {
+		return nil, err
+	}
+
+	users, err := handler.followService.ListFollowers(request.Username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users, err = handler.privacyService.FilterBlockedUsers(request.Viewer, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := handler.privacyService.ResolveViews(request.Viewer, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewListUsersResponse(users, views), nil
+}
+
+// List the users followed by a user
+//	@Summary		List users followed by a user
+//	@Description	List all users a user is following. The list is only returned if the privacy settings of the user allow the viewer to see their profile, and users blocked by or blocking the viewer are left out.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Param			viewer		query		string	false	"User looking at the list"
+//	@Success		200		{object}	request_model.ListUsersResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/list_following [get]
+func (handler *UserHandler) ListFollowing(request request_model.ListFollowsRequest) (*request_model.ListUsersResponse, error) {
+	if err := handler.checkCanViewList(request.Viewer, request.Username); err != nil {
+		return nil, err
+	}
+
+	users, err := handler.followService.ListFollowing(request.Username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users, err = handler.privacyService.FilterBlockedUsers(request.Viewer, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := handler.privacyService.ResolveViews(request.Viewer, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewListUsersResponse(users, views), nil
+}
+
+// List the artists followed by a user
+//	@Summary		List artists followed by a user
+//	@Description	List all artists a user is following
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Success		200		{object}	request_model.ListFollowedArtistsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/list_followed_artists [get]
+func (handler *UserHandler) ListFollowedArtists(request request_model.ListFollowsRequest) (*request_model.ListFollowedArtistsResponse, error) {
+	artists, err := handler.followService.ListFollowedArtists(context.Background(), request.Username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &request_model.ListFollowedArtistsResponse{
+		Artists: artists,
 	}, nil
 }
 
+// List the followers of an artist
+//	@Summary		List followers of an artist
+//	@Description	List all users following an artist, leaving out users blocked by or blocking the viewer
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			artist_id	query		string	true	"Artist ObjectID"
+//	@Param			viewer		query		string	false	"User looking at the list"
+//	@Success		200		{object}	request_model.ListUsersResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/list_artist_followers [get]
+func (handler *UserHandler) ListArtistFollowers(request request_model.ListArtistFollowersRequest) (*request_model.ListUsersResponse, error) {
+	users, err := handler.followService.ListArtistFollowers(request.ArtistId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users, err = handler.privacyService.FilterBlockedUsers(request.Viewer, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := handler.privacyService.ResolveViews(request.Viewer, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewListUsersResponse(users, views), nil
+}
+
+// Blocks another user
+//	@Summary		Block a user
+//	@Description	Makes username block target. Friendships, friend requests and follows between them are removed, and they can no longer message, befriend or follow each other.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.BlockUserRequest	true	"Users"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/block [post]
+func (handler *UserHandler) BlockUser(request request_model.BlockUserRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.privacyService.Block(request.Username, request.Target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully blocked user"), nil
+}
+
+// Unblocks another user
+//	@Summary		Unblock a user
+//	@Description	Removes the block username placed on target
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.BlockUserRequest	true	"Users"
+//	@Success		200		{object}	request_model.SuccessCreationResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/unblock [post]
+func (handler *UserHandler) UnblockUser(request request_model.BlockUserRequest) (*request_model.SuccessCreationResponse, error) {
+	err := handler.privacyService.Unblock(request.Username, request.Target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully unblocked user"), nil
+}
+
+// List the users blocked by a user
+//	@Summary		List blocked users
+//	@Description	List all users blocked by a user
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username"
+//	@Success		200		{object}	request_model.ListUsersResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/list_blocked [get]
+func (handler *UserHandler) ListBlockedUsers(request request_model.ListBlockedUsersRequest) (*request_model.ListUsersResponse, error) {
+	users, err := handler.privacyService.ListBlocked(request.Username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := handler.privacyService.ResolveViews(request.Username, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewListUsersResponse(users, views), nil
+}
+
+// Updates the privacy settings of a user
+//	@Summary		Update privacy settings
+//	@Description	Sets who can see the profile of the user (public, friends or private) and who can message them (everyone, friends or nobody)
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			post	body		request_model.UpdatePrivacySettingsRequest	true	"Privacy settings"
+//	@Success		200		{object}	request_model.PrivacySettingsResponse
+//	@Failure		400		{object}	map[string]string	"Invalid Input"
+//	@Failure		500		{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/update_privacy [post]
+func (handler *UserHandler) UpdatePrivacySettings(request request_model.UpdatePrivacySettingsRequest) (*request_model.PrivacySettingsResponse, error) {
+	user, err := handler.privacyService.UpdateSettings(request.Username, request.ProfileVisibility, request.MessagePermission)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewPrivacySettingsResponse(user), nil
+}
+
 // Marks a genre as liked by a user. Genre can be any string.
 //	@Summary		Marks a genre as liked by a user.
 //	@Description	Marks a genre as liked by a user. Genre can be any string.
@@ -253,13 +957,88 @@ func (handler *UserHandler) GetFriendRecommendationByGenre(request request_model
 		return nil, err
 	}
 
-	friendsModel := make([]*request_model.UserResponse, 0)
+	views, err := handler.privacyService.ResolveViews(request.Username, friends)
 
-	for _, friend := range friends {
-		friendsModel = append(friendsModel, request_model.NewUserResponse(friend))
+	if err != nil {
+		return nil, err
 	}
 
 	return &request_model.GetFriendRecommendationByGenreResponse{
-		Friends: friendsModel,
+		Friends: request_model.NewUserResponses(friends, views),
 	}, nil
+}
+
+// GetFriendRecommendations returns a page of scored friend recommendations
+//	@Summary		Returns scored friend recommendations
+//	@Description	Suggests friends of friends, users with similar tastes, members of the same communities and users with playlists sharing songs, scored by mutual friends, shared genres, shared communities and songs in common, best first. Friends, blocked users and users with a pending friend request are left out.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"User to recommend friends to"
+//	@Param			limit		query		int		false	"Page size"	default(20)
+//	@Param			offset		query		int		false	"Page offset"	default(0)
+//	@Success		200			{object}	request_model.GetFriendRecommendationsResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/recommendations [get]
+func (handler *UserHandler) GetFriendRecommendations(request request_model.GetFriendRecommendationsRequest) (*request_model.GetFriendRecommendationsResponse, error) {
+	recommendations, err := handler.recommendationService.Recommend(
+		context.Background(),
+		request.Username,
+		request.Limit,
+		request.Offset,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*model.User, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		users = append(users, recommendation.User)
+	}
+
+	views, err := handler.privacyService.ResolveViews(request.Username, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewGetFriendRecommendationsResponse(recommendations, views), nil
+}
+
+// GetSocialConnection returns how two users are connected
+//	@Summary		Returns how two users are connected
+//	@Description	Returns the shortest chain of friendships between two users, up to max_depth friendships away, with the mutual friends, shared communities and shared genres of both users. Users of the chain the user can not see are null, and mutual friends they can not see are left out.
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"User asking"
+//	@Param			target		query		string	true	"User to connect to"
+//	@Param			max_depth	query		int		false	"Maximum number of friendships in the path (1 to 6)"	default(4)
+//	@Success		200			{object}	request_model.SocialConnectionResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/user/connection [get]
+func (handler *UserHandler) GetSocialConnection(request request_model.GetSocialConnectionRequest) (*request_model.SocialConnectionResponse, error) {
+	connection, err := handler.socialPathService.GetConnection(request.Username, request.Target, request.MaxDepth)
+
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*model.User, 0, len(connection.Path)+len(connection.MutualFriends))
+	for _, user := range connection.Path {
+		if user != nil {
+			users = append(users, user)
+		}
+	}
+	users = append(users, connection.MutualFriends...)
+	views, err := handler.privacyService.ResolveViews(request.Username, users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return request_model.NewSocialConnectionResponse(connection, views), nil
 }
\ No newline at end of file
@@ -0,0 +1,192 @@
+package webhook_handlers
+
+import (
+	"log"
+	base_handlers "symphony-api/internal/handlers/base"
+	request_model "symphony-api/internal/handlers/model"
+	"symphony-api/internal/persistence/service"
+	"symphony-api/internal/server"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (handler *WebhookHandler) AddRoutes(server server.Server) {
+	server.AddRoute("/api/webhook/create", base_handlers.CreatePostMethodHandler(handler.CreateSubscription))
+	server.AddRoute("/api/webhook/list", base_handlers.CreateGetMethodHandler(handler.ListSubscriptions))
+	server.AddRoute("/api/webhook/update", base_handlers.CreatePostMethodHandler(handler.UpdateSubscription))
+	server.AddRoute("/api/webhook/delete", base_handlers.CreatePostMethodHandler(handler.DeleteSubscription))
+	server.AddRoute("/api/webhook/deliveries", base_handlers.CreateGetMethodHandler(handler.ListDeliveries))
+	server.AddRoute("/api/webhook/delivery_log", base_handlers.CreateGetMethodHandler(handler.GetDeliveryLog))
+	server.AddRoute("/api/webhook/replay", base_handlers.CreatePostMethodHandler(handler.ReplayDelivery))
+}
+
+// CreateSubscription registers a webhook.
+//	@Summary		Create webhook subscription
+//	@Description	Registers a URL to receive platform events: user.created, post.created, community.created or community.member_joined. Deliveries are POSTs signed in the X-Symphony-Signature header as t=<unix time>,v1=<HMAC-SHA256 of "<unix time>.<body>"> with the secret, which is only returned here. Only available to admins.
+//	@Tags			webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			subscription	body		request_model.CreateWebhookSubscriptionRequest	true	"Subscription data"
+//	@Success		200				{object}	request_model.WebhookSubscriptionResponse
+//	@Failure		400				{object}	map[string]string	"Invalid Input"
+//	@Failure		500				{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/webhook/create [post]
+func (handler *WebhookHandler) CreateSubscription(request request_model.CreateWebhookSubscriptionRequest) (*request_model.WebhookSubscriptionResponse, error) {
+	subscription, err := handler.webhookService.CreateSubscription(request.Username, request.Url, request.EventTypes)
+	if err != nil {
+		log.Printf("Error creating webhook subscription: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewCreateWebhookSubscriptionResponse(subscription), nil
+}
+
+// ListSubscriptions lists the webhooks.
+//	@Summary		List webhook subscriptions
+//	@Description	Lists the webhook subscriptions, without their secrets. Only available to admins.
+//	@Tags			webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username of the admin"
+//	@Success		200			{object}	request_model.ListWebhookSubscriptionsResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/webhook/list [get]
+func (handler *WebhookHandler) ListSubscriptions(request request_model.ListWebhookSubscriptionsRequest) (*request_model.ListWebhookSubscriptionsResponse, error) {
+	subscriptions, err := handler.webhookService.ListSubscriptions(request.Username)
+	if err != nil {
+		log.Printf("Error listing webhook subscriptions: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewListWebhookSubscriptionsResponse(subscriptions), nil
+}
+
+// UpdateSubscription changes a webhook.
+//	@Summary		Update webhook subscription
+//	@Description	Replaces the URL and event types of a webhook subscription and pauses (active false) or resumes it. Deliveries of a paused subscription stay queued until it is resumed. Only available to admins.
+//	@Tags			webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			subscription	body		request_model.UpdateWebhookSubscriptionRequest	true	"Subscription data"
+//	@Success		200				{object}	request_model.WebhookSubscriptionResponse
+//	@Failure		400				{object}	map[string]string	"Invalid Input"
+//	@Failure		500				{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/webhook/update [post]
+func (handler *WebhookHandler) UpdateSubscription(request request_model.UpdateWebhookSubscriptionRequest) (*request_model.WebhookSubscriptionResponse, error) {
+	subscription, err := handler.webhookService.UpdateSubscription(
+		request.Username,
+		request.SubscriptionId,
+		request.Url,
+		request.EventTypes,
+		request.Active,
+	)
+	if err != nil {
+		log.Printf("Error updating webhook subscription: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewWebhookSubscriptionResponse(subscription), nil
+}
+
+// DeleteSubscription removes a webhook.
+//	@Summary		Delete webhook subscription
+//	@Description	Deletes a webhook subscription along with its deliveries and their logs. Only available to admins.
+//	@Tags			webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			subscription	body		request_model.DeleteWebhookSubscriptionRequest	true	"Subscription to delete"
+//	@Success		200				{object}	request_model.SuccessCreationResponse
+//	@Failure		400				{object}	map[string]string	"Invalid Input"
+//	@Failure		500				{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/webhook/delete [post]
+func (handler *WebhookHandler) DeleteSubscription(request request_model.DeleteWebhookSubscriptionRequest) (*request_model.SuccessCreationResponse, error) {
+	if err := handler.webhookService.DeleteSubscription(request.Username, request.SubscriptionId); err != nil {
+		log.Printf("Error deleting webhook subscription: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewSuccessCreationResponse("Successfully deleted webhook subscription"), nil
+}
+
+// ListDeliveries lists the deliveries of a webhook.
+//	@Summary		List webhook deliveries
+//	@Description	Lists the deliveries of a webhook subscription, newest first. Failed deliveries are retried with exponential backoff; those that run out of attempts are dead and can be replayed. Only available to admins.
+//	@Tags			webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			username		query		string	true	"Username of the admin"
+//	@Param			subscription_id	query		int		true	"Subscription ID"
+//	@Param			status			query		string	false	"Only list deliveries with this status: pending, delivered or dead"
+//	@Param			limit			query		int		false	"Number of items to retrieve (default is 20)"
+//	@Param			offset			query		int		false	"Number of items to skip"
+//	@Success		200				{object}	request_model.ListWebhookDeliveriesResponse
+//	@Failure		400				{object}	map[string]string	"Invalid Input"
+//	@Failure		500				{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/webhook/deliveries [get]
+func (handler *WebhookHandler) ListDeliveries(request request_model.ListWebhookDeliveriesRequest) (*request_model.ListWebhookDeliveriesResponse, error) {
+	deliveries, err := handler.webhookService.ListDeliveries(
+		request.Username,
+		request.SubscriptionId,
+		request.Status,
+		request.Limit,
+		request.Offset,
+	)
+	if err != nil {
+		log.Printf("Error listing webhook deliveries: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewListWebhookDeliveriesResponse(deliveries), nil
+}
+
+// GetDeliveryLog returns the attempts of a delivery.
+//	@Summary		Get webhook delivery log
+//	@Description	Returns a webhook delivery with the log of its attempts: status code, error, start of the response body and duration. Only available to admins.
+//	@Tags			webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	query		string	true	"Username of the admin"
+//	@Param			delivery_id	query		int		true	"Delivery ID"
+//	@Success		200			{object}	request_model.GetWebhookDeliveryLogResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/webhook/delivery_log [get]
+func (handler *WebhookHandler) GetDeliveryLog(request request_model.GetWebhookDeliveryLogRequest) (*request_model.GetWebhookDeliveryLogResponse, error) {
+	delivery, attempts, err := handler.webhookService.GetDeliveryLog(request.Username, request.DeliveryId)
+	if err != nil {
+		log.Printf("Error fetching webhook delivery log: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewGetWebhookDeliveryLogResponse(delivery, attempts), nil
+}
+
+// ReplayDelivery sends a delivery again.
+//	@Summary		Replay webhook delivery
+//	@Description	Queues a new delivery with the same payload as an existing one, to be sent right away. Used to send dead deliveries once the receiver is fixed. Only available to admins.
+//	@Tags			webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			delivery	body		request_model.ReplayWebhookDeliveryRequest	true	"Delivery to replay"
+//	@Success		200			{object}	request_model.WebhookDeliveryResponse
+//	@Failure		400			{object}	map[string]string	"Invalid Input"
+//	@Failure		500			{object}	map[string]string	"Internal Server Error"
+//	@Router			/api/webhook/replay [post]
+func (handler *WebhookHandler) ReplayDelivery(request request_model.ReplayWebhookDeliveryRequest) (*request_model.WebhookDeliveryResponse, error) {
+	delivery, err := handler.webhookService.Replay(request.Username, request.DeliveryId)
+	if err != nil {
+		log.Printf("Error replaying webhook delivery: %s", err)
+		return nil, err
+	}
+
+	return request_model.NewWebhookDeliveryResponse(delivery), nil
+}
@@ -14,7 +14,8 @@ type PostgreConnection interface {
 	Put(data map[string]any, tableName string) error
 	PutReturningId(data map[string]any, tableName string, idName string) (any, error)
 	Get(constraints map[string]any, tableName string) ([]map[string]any, error)
-	GetChatWithLimit(chat_id int32, limit int32, tableName string) ([]map[string]any, error)
+	Execute(query string, args ...any) error
+	ExecuteReturning(query string, args ...any) ([]map[string]any, error)
 }
 
 type PostgreConnectionImpl struct {
@@ -171,7 +172,7 @@ func getSelectWthConstraintsQuery(constraints map[string]any, tableName string)
 	return fmt.Sprintf(
 			"SELECT * FROM %s WHERE %s", 
 			tableName, 
-			joinComma(constraintList),
+			strings.Join(constraintList, " AND "),
 		), values
 }
 
@@ -179,21 +180,21 @@ func joinComma(values []string) string {
 	return strings.Join(values, ",")
 }
 
-func (conn *PostgreConnectionImpl) GetChatWithLimit(chat_id int32, limit int32, tableName string) ([]map[string]any, error) {
-    var time_column string
-    switch tableName {
-    case "CHAT_MESSAGE":
-        time_column = "sent_at"
-    case "CHAT":
-        time_column = "created_at"
-    default:
-        return nil, fmt.Errorf("unsupported table: %s", tableName)
-    }
+// Execute runs a raw statement that does not return rows. It is meant for
+// statements that can not be expressed with Put and Get, such as updates,
+// deletes and upserts.
+func (conn *PostgreConnectionImpl) Execute(query string, args ...any) error {
+	log.Printf("Executing statement at Postgres: %s", query)
+	_, err := conn.Exec(context.Background(), query, args...)
+	return err
+}
 
-    sql := fmt.Sprintf("SELECT * FROM %s WHERE chat_id = $1 ORDER BY %s DESC LIMIT $2", tableName, time_column)
-    rows, err := conn.Query(context.Background(), sql, chat_id, limit)
-    if err != nil {
-        return nil, err
-    }
-    return rowsToMaps(rows)
-}
\ No newline at end of file
+// ExecuteReturning runs a raw query and returns its rows as maps keyed by
+// column name, the same format returned by Get.
+func (conn *PostgreConnectionImpl) ExecuteReturning(query string, args ...any) ([]map[string]any, error) {
+	rows, err := conn.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToMaps(rows)
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSelectWthConstraintsQuery_SingleConstraint(t *testing.T) {
+	sql, args := getSelectWthConstraintsQuery(map[string]any{"id": int32(1)}, "post")
+
+	assert.Equal(t, "SELECT * FROM post WHERE id = $1", sql)
+	assert.Equal(t, []any{int32(1)}, args)
+}
+
+func TestGetSelectWthConstraintsQuery_JoinsConstraintsWithAnd(t *testing.T) {
+	constraints := map[string]any{
+		"id":      int32(1),
+		"hidden":  false,
+		"user_id": int32(2),
+	}
+
+	sql, args := getSelectWthConstraintsQuery(constraints, "post")
+
+	assert.True(t, strings.HasPrefix(sql, "SELECT * FROM post WHERE "))
+	conditions := strings.Split(strings.TrimPrefix(sql, "SELECT * FROM post WHERE "), " AND ")
+	assert.Len(t, conditions, len(constraints))
+	assert.NotContains(t, sql, ",")
+
+	// Each placeholder must be bound to the value of its own column.
+	for i, condition := range conditions {
+		column, placeholder, found := strings.Cut(condition, " = ")
+		assert.True(t, found, condition)
+		assert.Equal(t, fmt.Sprintf("$%d", i+1), placeholder)
+		assert.Equal(t, constraints[column], args[i])
+	}
+}
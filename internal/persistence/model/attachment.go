@@ -0,0 +1,62 @@
+package model
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ATTACHMENT_SONG     = "song"
+	ATTACHMENT_ARTIST   = "artist"
+	ATTACHMENT_PLAYLIST = "playlist"
+)
+
+// Attachment references a song, artist or playlist stored in Mongo.
+// Only Type and Id are persisted; Title, ArtistName and CoverUrl are
+// filled when the attachment is hydrated from the Mongo repositories.
+type Attachment struct {
+	Type       string `json:"type"`
+	Id         string `json:"id"`
+	Title      string `json:"title,omitempty"`
+	ArtistName string `json:"artist_name,omitempty"`
+	CoverUrl   string `json:"cover_url,omitempty"`
+}
+
+func NewAttachment(attachmentType string, id string) (*Attachment, error) {
+	switch attachmentType {
+	case ATTACHMENT_SONG, ATTACHMENT_ARTIST, ATTACHMENT_PLAYLIST:
+	default:
+		return nil, errors.New("invalid attachment type: " + attachmentType)
+	}
+
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, errors.New("invalid attachment id: " + id)
+	}
+
+	return &Attachment{
+		Type: attachmentType,
+		Id:   id,
+	}, nil
+}
+
+func (attachment *Attachment) ObjectId() primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(attachment.Id)
+	return id
+}
+
+// MapToAttachment reads the attachment columns of a row. It returns nil
+// when the row has no attachment.
+func MapToAttachment(data map[string]any) *Attachment {
+	attachmentType, _ := data["attachment_type"].(string)
+	attachmentId, _ := data["attachment_id"].(string)
+
+	if attachmentType == "" || attachmentId == "" {
+		return nil
+	}
+
+	return &Attachment{
+		Type: attachmentType,
+		Id:   attachmentId,
+	}
+}
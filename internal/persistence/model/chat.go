@@ -1,11 +1,27 @@
 package model
 
 import (
+	"errors"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+const (
+	CHAT_ROLE_ADMIN  = "admin"
+	CHAT_ROLE_MEMBER = "member"
+)
+
+const MAX_GROUP_CHAT_SIZE = 50
+const MAX_CHAT_NAME_LENGTH = 100
+
+// Chat is a conversation between users. Direct chats have exactly two
+// participants and no name; group chats have a name and any number of
+// participants up to MAX_GROUP_CHAT_SIZE.
 type Chat struct {
 	ChatId int32
+	Name string
+	IsGroup bool
 	CreatedAt time.Time
 }
 
@@ -16,13 +32,89 @@ func NewChat(chatId int32) *Chat {
 	}
 }
 
+func NewGroupChat(name string) *Chat {
+	return &Chat{
+		Name: name,
+		IsGroup: true,
+		CreatedAt: time.Now(),
+	}
+}
+
 func (chat *Chat) ToMap() map[string]any {
-    return map[string]any{}
+	if !chat.IsGroup {
+		return map[string]any{}
+	}
+
+	return map[string]any{
+		"name": chat.Name,
+		"is_group": true,
+	}
 }
 
 func MapToChat(data map[string]any) *Chat {
+	name, _ := data["name"].(string)
+	isGroup, _ := data["is_group"].(bool)
+
 	return &Chat{
 		ChatId: data["chat_id"].(int32),
+		Name: name,
+		IsGroup: isGroup,
 		CreatedAt: data["created_at"].(time.Time),
 	}
-}
\ No newline at end of file
+}
+
+// ValidateChatName trims the name of a group chat and checks its length.
+func ValidateChatName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return "", errors.New("group chats must have a name")
+	}
+	if utf8.RuneCountInString(name) > MAX_CHAT_NAME_LENGTH {
+		return "", errors.New("chat name is too long")
+	}
+
+	return name, nil
+}
+
+func ValidateChatRole(role string) error {
+	switch role {
+	case CHAT_ROLE_ADMIN, CHAT_ROLE_MEMBER:
+		return nil
+	default:
+		return errors.New("invalid chat role: " + role)
+	}
+}
+
+// ChatMember is a participant of a chat together with their role in it.
+type ChatMember struct {
+	User *User
+	Role string
+	JoinedAt time.Time
+}
+
+func (member *ChatMember) IsAdmin() bool {
+	return member.Role == CHAT_ROLE_ADMIN
+}
+
+// MapToChatMember reads a users row joined with its chat_participants row.
+func MapToChatMember(data map[string]any) *ChatMember {
+	role, _ := data["role"].(string)
+	joinedAt, _ := data["joined_at"].(time.Time)
+
+	return &ChatMember{
+		User: MapToUser(data),
+		Role: role,
+		JoinedAt: joinedAt,
+	}
+}
+
+// FindChatMember returns the member with the given username, or nil.
+func FindChatMember(members []*ChatMember, username string) *ChatMember {
+	for _, member := range members {
+		if member.User.Username == username {
+			return member
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+const (
+	MESSAGE_ORDER_NEWEST = "newest"
+	MESSAGE_ORDER_OLDEST = "oldest"
+
+	MAX_CHAT_HISTORY_LIMIT = 100
+)
+
+// ChatHistoryQuery selects a page of the messages of a chat. Before and After
+// are message ids used as exclusive cursors, 0 meaning no bound. Order only
+// sorts the page; which messages fall in it depends on the cursors.
+type ChatHistoryQuery struct {
+	Before int32
+	After  int32
+	Limit  int32
+	Order  string
+}
+
+func (query *ChatHistoryQuery) Validate() error {
+	if query.Limit <= 0 || query.Limit > MAX_CHAT_HISTORY_LIMIT {
+		return errors.New("limit must be between 1 and 100")
+	}
+	if query.Before < 0 || query.After < 0 {
+		return errors.New("message cursors can not be negative")
+	}
+	if query.Before > 0 && query.After > 0 && query.After >= query.Before {
+		return errors.New("after must be lower than before")
+	}
+
+	switch query.Order {
+	case MESSAGE_ORDER_NEWEST, MESSAGE_ORDER_OLDEST:
+		return nil
+	default:
+		return errors.New("invalid message order: " + query.Order)
+	}
+}
+
+// Forward reports whether the page is read forwards from the After cursor.
+// Otherwise it is read backwards, from Before or from the newest message.
+func (query *ChatHistoryQuery) Forward() bool {
+	return query.After > 0 && query.Before == 0
+}
+
+// ChatHistoryPage is a page of messages. HasMore tells whether there are more
+// messages past the page in the direction it was read.
+type ChatHistoryPage struct {
+	Messages []*ChatMessage
+	HasMore  bool
+}
+
+// NewChatHistoryPage builds a page from up to Limit+1 messages read in the
+// direction of the query, the extra one only telling that there are more.
+func NewChatHistoryPage(messages []*ChatMessage, query *ChatHistoryQuery) *ChatHistoryPage {
+	hasMore := len(messages) > int(query.Limit)
+	if hasMore {
+		messages = messages[:query.Limit]
+	}
+
+	page := make([]*ChatMessage, len(messages))
+	copy(page, messages)
+	sort.SliceStable(page, func(i, j int) bool {
+		if query.Order == MESSAGE_ORDER_OLDEST {
+			return page[i].MessageId < page[j].MessageId
+		}
+		return page[i].MessageId > page[j].MessageId
+	})
+
+	return &ChatHistoryPage{
+		Messages: page,
+		HasMore:  hasMore,
+	}
+}
+
+// OldestMessageId returns the id of the oldest message of the page, to be
+// used as the Before cursor of the previous page. It is 0 when it is empty.
+func (page *ChatHistoryPage) OldestMessageId() int32 {
+	var oldest int32
+	for _, message := range page.Messages {
+		if oldest == 0 || message.MessageId < oldest {
+			oldest = message.MessageId
+		}
+	}
+	return oldest
+}
+
+// NewestMessageId returns the id of the newest message of the page, to be
+// used as the After cursor of the next page. It is 0 when it is empty.
+func (page *ChatHistoryPage) NewestMessageId() int32 {
+	var newest int32
+	for _, message := range page.Messages {
+		if message.MessageId > newest {
+			newest = message.MessageId
+		}
+	}
+	return newest
+}
+
+// ChatMessageSearchResult is a message matching a full text search, along
+// with the chat it was sent to and a fragment of it highlighting the match.
+type ChatMessageSearchResult struct {
+	Message  *ChatMessage
+	Author   string
+	ChatName string
+	IsGroup  bool
+	Headline string
+	Rank     float64
+}
+
+func MapToChatMessageSearchResult(data map[string]any) *ChatMessageSearchResult {
+	author, _ := data["author"].(string)
+	chatName, _ := data["chat_name"].(string)
+	isGroup, _ := data["is_group"].(bool)
+	headline, _ := data["headline"].(string)
+	rank, _ := data["rank"].(float64)
+	authorId, _ := data["author_id"].(int32)
+	sentAt, _ := data["sent_at"].(time.Time)
+
+	return &ChatMessageSearchResult{
+		Message: &ChatMessage{
+			MessageId:  data["message_id"].(int32),
+			AuthorId:   authorId,
+			ChatId:     data["chat_id"].(int32),
+			Message:    data["message"].(string),
+			SentAt:     sentAt,
+			Attachment: MapToAttachment(data),
+		},
+		Author:   author,
+		ChatName: chatName,
+		IsGroup:  isGroup,
+		Headline: headline,
+		Rank:     rank,
+	}
+}
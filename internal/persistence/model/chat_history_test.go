@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatHistoryQuery_Validate(t *testing.T) {
+	assert.NoError(t, (&ChatHistoryQuery{Limit: 10, Order: MESSAGE_ORDER_NEWEST}).Validate())
+	assert.NoError(t, (&ChatHistoryQuery{Before: 20, After: 5, Limit: 10, Order: MESSAGE_ORDER_OLDEST}).Validate())
+	assert.Error(t, (&ChatHistoryQuery{Limit: 0, Order: MESSAGE_ORDER_NEWEST}).Validate())
+	assert.Error(t, (&ChatHistoryQuery{Limit: MAX_CHAT_HISTORY_LIMIT + 1, Order: MESSAGE_ORDER_NEWEST}).Validate())
+	assert.Error(t, (&ChatHistoryQuery{Before: 5, After: 5, Limit: 10, Order: MESSAGE_ORDER_NEWEST}).Validate())
+	assert.Error(t, (&ChatHistoryQuery{Limit: 10, Order: "random"}).Validate())
+}
+
+func TestChatHistoryQuery_Forward(t *testing.T) {
+	assert.False(t, (&ChatHistoryQuery{}).Forward())
+	assert.False(t, (&ChatHistoryQuery{Before: 9}).Forward())
+	assert.False(t, (&ChatHistoryQuery{Before: 9, After: 2}).Forward())
+	assert.True(t, (&ChatHistoryQuery{After: 2}).Forward())
+}
+
+func TestNewChatHistoryPage(t *testing.T) {
+	// Read backwards, newest first, with one message more than the limit.
+	messages := []*ChatMessage{{MessageId: 9}, {MessageId: 8}, {MessageId: 7}}
+
+	page := NewChatHistoryPage(messages, &ChatHistoryQuery{Limit: 2, Order: MESSAGE_ORDER_OLDEST})
+
+	assert.True(t, page.HasMore)
+	assert.Len(t, page.Messages, 2)
+	assert.Equal(t, int32(8), page.Messages[0].MessageId)
+	assert.Equal(t, int32(9), page.Messages[1].MessageId)
+	assert.Equal(t, int32(8), page.OldestMessageId())
+	assert.Equal(t, int32(9), page.NewestMessageId())
+}
+
+func TestNewChatHistoryPage_Empty(t *testing.T) {
+	page := NewChatHistoryPage(nil, &ChatHistoryQuery{Limit: 10, Order: MESSAGE_ORDER_NEWEST})
+
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.Messages)
+	assert.Equal(t, int32(0), page.OldestMessageId())
+	assert.Equal(t, int32(0), page.NewestMessageId())
+}
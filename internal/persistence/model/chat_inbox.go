@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+const MAX_MESSAGE_PREVIEW_LENGTH = 100
+
+// ChatInboxEntry is a chat as shown in the inbox of a user: its participants,
+// its newest message and how many messages the user has not read yet.
+// LastMessage is nil when the chat has no visible message.
+type ChatInboxEntry struct {
+	Chat              *Chat
+	Participants      []*ChatMember
+	LastMessage       *ChatMessage
+	LastMessageAuthor string
+	LastActivity      time.Time
+	UnreadCount       int64
+}
+
+// MapToChatInboxEntry reads a row of the inbox query. Participants come as a
+// JSON array of objects with username, fullname, avatar_url and role.
+func MapToChatInboxEntry(data map[string]any) *ChatInboxEntry {
+	entry := &ChatInboxEntry{
+		Chat:         MapToChat(data),
+		Participants: make([]*ChatMember, 0),
+	}
+
+	entry.LastActivity, _ = data["last_activity"].(time.Time)
+	entry.UnreadCount, _ = data["unread_count"].(int64)
+	entry.LastMessageAuthor, _ = data["last_message_author"].(string)
+
+	if messageId, ok := data["last_message_id"].(int32); ok {
+		message := &ChatMessage{
+			MessageId: messageId,
+			ChatId:    entry.Chat.ChatId,
+		}
+		message.AuthorId, _ = data["last_message_author_id"].(int32)
+		message.Message, _ = data["last_message"].(string)
+		message.SentAt, _ = data["last_message_at"].(time.Time)
+		message.DeletedAt, _ = data["last_message_deleted_at"].(time.Time)
+		entry.LastMessage = message
+	}
+
+	participants, _ := data["participants"].([]any)
+	for _, participant := range participants {
+		fields, ok := participant.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		member := &ChatMember{User: &User{}}
+		member.User.Username, _ = fields["username"].(string)
+		member.User.Fullname, _ = fields["fullname"].(string)
+		member.User.AvatarUrl, _ = fields["avatar_url"].(string)
+		member.Role, _ = fields["role"].(string)
+		entry.Participants = append(entry.Participants, member)
+	}
+
+	return entry
+}
+
+// MessagePreview shortens a message to MAX_MESSAGE_PREVIEW_LENGTH characters.
+func MessagePreview(message string) string {
+	if utf8.RuneCountInString(message) <= MAX_MESSAGE_PREVIEW_LENGTH {
+		return message
+	}
+
+	runes := []rune(message)
+	return string(runes[:MAX_MESSAGE_PREVIEW_LENGTH-1]) + "…"
+}
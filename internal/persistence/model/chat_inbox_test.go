@@ -0,0 +1,67 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapToChatInboxEntry(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	sentAt := time.Now()
+
+	entry := MapToChatInboxEntry(map[string]any{
+		"chat_id":                int32(3),
+		"name":                   "Jazz club",
+		"is_group":               true,
+		"created_at":             createdAt,
+		"last_message_id":        int32(9),
+		"last_message_author_id": int32(1),
+		"last_message_author":    "john",
+		"last_message":           "hello",
+		"last_message_at":        sentAt,
+		"last_activity":          sentAt,
+		"unread_count":           int64(2),
+		"participants": []any{
+			map[string]any{"username": "john", "fullname": "John", "avatar_url": nil, "role": "admin"},
+			map[string]any{"username": "mary", "fullname": "Mary", "avatar_url": "/media/mary.png", "role": "member"},
+		},
+	})
+
+	assert.Equal(t, "Jazz club", entry.Chat.Name)
+	assert.Equal(t, &ChatMessage{MessageId: 9, AuthorId: 1, ChatId: 3, Message: "hello", SentAt: sentAt}, entry.LastMessage)
+	assert.Equal(t, "john", entry.LastMessageAuthor)
+	assert.Equal(t, int64(2), entry.UnreadCount)
+	assert.Len(t, entry.Participants, 2)
+	assert.True(t, entry.Participants[0].IsAdmin())
+	assert.Equal(t, "/media/mary.png", entry.Participants[1].User.AvatarUrl)
+}
+
+func TestMapToChatInboxEntry_EmptyChat(t *testing.T) {
+	createdAt := time.Now()
+
+	entry := MapToChatInboxEntry(map[string]any{
+		"chat_id":         int32(3),
+		"is_group":        false,
+		"created_at":      createdAt,
+		"last_message_id": nil,
+		"last_activity":   createdAt,
+		"unread_count":    int64(0),
+		"participants":    nil,
+	})
+
+	assert.Nil(t, entry.LastMessage)
+	assert.Empty(t, entry.Participants)
+	assert.Equal(t, createdAt, entry.LastActivity)
+}
+
+func TestMessagePreview(t *testing.T) {
+	assert.Equal(t, "short", MessagePreview("short"))
+
+	preview := MessagePreview(strings.Repeat("á", 150))
+	assert.Equal(t, MAX_MESSAGE_PREVIEW_LENGTH, utf8.RuneCountInString(preview))
+	assert.True(t, strings.HasSuffix(preview, "…"))
+}
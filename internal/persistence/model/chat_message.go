@@ -4,18 +4,25 @@ import (
 	"time"
 )
 
+// ChatMessage is a message sent to a chat, optionally sharing a song, artist
+// or playlist. EditedAt is zero until the message is edited. Deleted messages
+// are kept as tombstones: their DeletedAt is set and their text and attachment
+// removed.
 type ChatMessage struct {
-	MessageId int32     `json:"message_id"`
-	AuthorId  int32     `json:"author_id"`
-	ChatId    int32     `json:"chat_id"`
-	Message   string    `json:"message"`
-	SentAt    time.Time `json:"sent_at"`
+	MessageId  int32       `json:"message_id"`
+	AuthorId   int32       `json:"author_id"`
+	ChatId     int32       `json:"chat_id"`
+	Message    string      `json:"message"`
+	SentAt     time.Time   `json:"sent_at"`
+	EditedAt   time.Time   `json:"edited_at,omitzero"`
+	DeletedAt  time.Time   `json:"deleted_at,omitzero"`
+	Attachment *Attachment `json:"attachment,omitempty"`
 }
 
 func NewChatMessage(
 	messageId,
-	authorId, 
-	chatId int32, 
+	authorId,
+	chatId int32,
 	message string) *ChatMessage {
 	return &ChatMessage{
 		MessageId: messageId,
@@ -27,21 +34,45 @@ func NewChatMessage(
 }
 
 func (message *ChatMessage) ToMap() map[string]any {
-	return map[string]any{
+	data := map[string]any{
 		"message_id": message.MessageId,
 		"author_id":  message.AuthorId,
 		"chat_id":    message.ChatId,
 		"message":    message.Message,
 		"sent_at":    message.SentAt,
 	}
+
+	if message.Attachment != nil {
+		data["attachment_type"] = message.Attachment.Type
+		data["attachment_id"] = message.Attachment.Id
+	}
+
+	return data
+}
+
+func (message *ChatMessage) IsEdited() bool {
+	return !message.EditedAt.IsZero()
 }
 
+func (message *ChatMessage) IsDeleted() bool {
+	return !message.DeletedAt.IsZero()
+}
+
+// MapToChatMessage reads a message row. The author of a message is null once
+// their account is deleted, which is read as AuthorId 0.
 func MapToChatMessage(data map[string]any) *ChatMessage {
+	authorId, _ := data["author_id"].(int32)
+	editedAt, _ := data["edited_at"].(time.Time)
+	deletedAt, _ := data["deleted_at"].(time.Time)
+
 	return &ChatMessage{
-		MessageId: data["message_id"].(int32),
-		AuthorId:  data["author_id"].(int32),
-		ChatId:    data["chat_id"].(int32),
-		Message:   data["message"].(string),
-		SentAt:    data["sent_at"].(time.Time),
+		MessageId:  data["message_id"].(int32),
+		AuthorId:   authorId,
+		ChatId:     data["chat_id"].(int32),
+		Message:    data["message"].(string),
+		SentAt:     data["sent_at"].(time.Time),
+		EditedAt:   editedAt,
+		DeletedAt:  deletedAt,
+		Attachment: MapToAttachment(data),
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapToChatMessageWithAttachment(t *testing.T) {
+	data := map[string]any{
+		"message_id":      int32(1),
+		"author_id":       int32(2),
+		"chat_id":         int32(3),
+		"message":         "Listen to this",
+		"sent_at":         time.Now(),
+		"attachment_type": ATTACHMENT_SONG,
+		"attachment_id":   "665f1c2e8b3e4a0012345678",
+	}
+
+	message := MapToChatMessage(data)
+
+	assert.NotNil(t, message.Attachment)
+	assert.Equal(t, ATTACHMENT_SONG, message.Attachment.Type)
+	assert.Equal(t, "665f1c2e8b3e4a0012345678", message.Attachment.Id)
+	assert.False(t, message.IsEdited())
+	assert.False(t, message.IsDeleted())
+}
+
+func TestChatMessageToMapWithAttachment(t *testing.T) {
+	message := NewChatMessage(1, 2, 3, "")
+	message.Attachment = &Attachment{Type: ATTACHMENT_PLAYLIST, Id: "665f1c2e8b3e4a0012345678"}
+
+	m := message.ToMap()
+
+	assert.Equal(t, ATTACHMENT_PLAYLIST, m["attachment_type"])
+	assert.Equal(t, "665f1c2e8b3e4a0012345678", m["attachment_id"])
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A single emoji can take several code points: skin tones, variation
+// selectors and the joiners of sequences such as families.
+const MAX_REACTION_LENGTH = 10
+
+// ChatReaction is how many participants reacted to a message with an emoji,
+// and who they are, in the order they reacted.
+type ChatReaction struct {
+	MessageId int32
+	Emoji     string
+	Count     int64
+	Usernames []string
+}
+
+func MapToChatReaction(data map[string]any) *ChatReaction {
+	count, _ := data["count"].(int64)
+	usernames := make([]string, 0)
+
+	values, _ := data["usernames"].([]any)
+	for _, value := range values {
+		if username, ok := value.(string); ok {
+			usernames = append(usernames, username)
+		}
+	}
+
+	return &ChatReaction{
+		MessageId: data["message_id"].(int32),
+		Emoji:     data["emoji"].(string),
+		Count:     count,
+		Usernames: usernames,
+	}
+}
+
+// GroupReactionsByMessage indexes the reactions by the message they belong
+// to, keeping their order.
+func GroupReactionsByMessage(reactions []*ChatReaction) map[int32][]*ChatReaction {
+	grouped := map[int32][]*ChatReaction{}
+	for _, reaction := range reactions {
+		grouped[reaction.MessageId] = append(grouped[reaction.MessageId], reaction)
+	}
+	return grouped
+}
+
+// ValidateReaction trims the reaction and checks it is a short run of emoji,
+// rejecting letters, digits, punctuation and whitespace.
+func ValidateReaction(emoji string) (string, error) {
+	emoji = strings.TrimSpace(emoji)
+
+	if emoji == "" {
+		return "", errors.New("reaction can not be empty")
+	}
+	if utf8.RuneCountInString(emoji) > MAX_REACTION_LENGTH {
+		return "", errors.New("reaction is too long")
+	}
+
+	for _, r := range emoji {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsPunct(r) || unicode.IsSpace(r) {
+			return "", errors.New("reaction must be an emoji")
+		}
+	}
+
+	return emoji, nil
+}
+
+// ChatMessageEdit is a previous version of an edited message. EditedAt is when
+// it was replaced.
+type ChatMessageEdit struct {
+	EditId    int32
+	MessageId int32
+	Message   string
+	EditedAt  time.Time
+}
+
+func MapToChatMessageEdit(data map[string]any) *ChatMessageEdit {
+	return &ChatMessageEdit{
+		EditId:    data["edit_id"].(int32),
+		MessageId: data["message_id"].(int32),
+		Message:   data["message"].(string),
+		EditedAt:  data["edited_at"].(time.Time),
+	}
+}
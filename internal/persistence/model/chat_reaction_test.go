@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReaction(t *testing.T) {
+	for _, emoji := range []string{"👍", " ❤️ ", "👍🏽", "👨‍👩‍👧"} {
+		_, err := ValidateReaction(emoji)
+		assert.NoError(t, err, emoji)
+	}
+
+	emoji, _ := ValidateReaction(" 🔥 ")
+	assert.Equal(t, "🔥", emoji)
+
+	for _, emoji := range []string{"", "  ", "ok", "1", "!", "👍 👍", "🔥🔥🔥🔥🔥🔥🔥🔥🔥🔥🔥"} {
+		_, err := ValidateReaction(emoji)
+		assert.Error(t, err, emoji)
+	}
+}
+
+func TestMapToChatReaction(t *testing.T) {
+	reaction := MapToChatReaction(map[string]any{
+		"message_id": int32(3),
+		"emoji":      "🔥",
+		"count":      int64(2),
+		"usernames":  []any{"john", "mary"},
+	})
+
+	assert.Equal(t, &ChatReaction{MessageId: 3, Emoji: "🔥", Count: 2, Usernames: []string{"john", "mary"}}, reaction)
+}
+
+func TestGroupReactionsByMessage(t *testing.T) {
+	reactions := []*ChatReaction{
+		{MessageId: 1, Emoji: "🔥"},
+		{MessageId: 2, Emoji: "👍"},
+		{MessageId: 1, Emoji: "👍"},
+	}
+
+	grouped := GroupReactionsByMessage(reactions)
+
+	assert.Len(t, grouped, 2)
+	assert.Equal(t, []*ChatReaction{reactions[0], reactions[2]}, grouped[1])
+	assert.Equal(t, []*ChatReaction{reactions[1]}, grouped[2])
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// ChatReadState tells up to which message a participant has read a chat.
+// LastReadMessageId is 0 when they have not read anything yet.
+type ChatReadState struct {
+	UserId            int32
+	Username          string
+	LastReadMessageId int32
+	LastReadAt        time.Time
+}
+
+func MapToChatReadState(data map[string]any) *ChatReadState {
+	username, _ := data["username"].(string)
+	lastReadMessageId, _ := data["last_read_message_id"].(int32)
+	lastReadAt, _ := data["last_read_at"].(time.Time)
+
+	return &ChatReadState{
+		UserId:            data["user_id"].(int32),
+		Username:          username,
+		LastReadMessageId: lastReadMessageId,
+		LastReadAt:        lastReadAt,
+	}
+}
+
+// ReadBy returns the usernames of the participants, other than its author,
+// that have read the message.
+func ReadBy(message *ChatMessage, states []*ChatReadState) []string {
+	readers := make([]string, 0)
+
+	for _, state := range states {
+		if state.UserId != message.AuthorId && state.LastReadMessageId >= message.MessageId {
+			readers = append(readers, state.Username)
+		}
+	}
+
+	return readers
+}
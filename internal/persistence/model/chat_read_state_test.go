@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapToChatReadState_NeverRead(t *testing.T) {
+	state := MapToChatReadState(map[string]any{
+		"user_id":              int32(2),
+		"username":             "mary",
+		"last_read_message_id": nil,
+		"last_read_at":         nil,
+	})
+
+	assert.Equal(t, &ChatReadState{UserId: 2, Username: "mary"}, state)
+}
+
+func TestReadBy(t *testing.T) {
+	states := []*ChatReadState{
+		{UserId: 1, Username: "john", LastReadMessageId: 10},
+		{UserId: 2, Username: "mary", LastReadMessageId: 7},
+		{UserId: 3, Username: "bob", LastReadMessageId: 5},
+	}
+
+	readers := ReadBy(&ChatMessage{MessageId: 7, AuthorId: 1}, states)
+
+	assert.Equal(t, []string{"mary"}, readers)
+	assert.Empty(t, ReadBy(&ChatMessage{MessageId: 11, AuthorId: 1}, states))
+}
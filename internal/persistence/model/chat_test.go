@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateChatName(t *testing.T) {
+	name, err := ValidateChatName("  Jazz club ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Jazz club", name)
+
+	_, err = ValidateChatName("   ")
+	assert.Error(t, err)
+}
+
+func TestValidateChatRole(t *testing.T) {
+	assert.NoError(t, ValidateChatRole(CHAT_ROLE_ADMIN))
+	assert.NoError(t, ValidateChatRole(CHAT_ROLE_MEMBER))
+	assert.Error(t, ValidateChatRole("owner"))
+}
+
+func TestMapToChat_DirectChat(t *testing.T) {
+	createdAt := time.Now()
+
+	chat := MapToChat(map[string]any{
+		"chat_id":    int32(4),
+		"name":       nil,
+		"is_group":   false,
+		"created_at": createdAt,
+	})
+
+	assert.Equal(t, &Chat{ChatId: 4, CreatedAt: createdAt}, chat)
+	assert.Empty(t, chat.ToMap())
+}
+
+func TestGroupChat_ToMap(t *testing.T) {
+	chat := NewGroupChat("Jazz club")
+
+	assert.Equal(t, map[string]any{"name": "Jazz club", "is_group": true}, chat.ToMap())
+}
+
+func TestFindChatMember(t *testing.T) {
+	members := []*ChatMember{
+		{User: &User{Username: "john"}, Role: CHAT_ROLE_ADMIN},
+		{User: &User{Username: "mary"}, Role: CHAT_ROLE_MEMBER},
+	}
+
+	assert.True(t, FindChatMember(members, "john").IsAdmin())
+	assert.False(t, FindChatMember(members, "mary").IsAdmin())
+	assert.Nil(t, FindChatMember(members, "bob"))
+}
@@ -7,6 +7,7 @@ type Community struct {
 	CommunityName string
 	Description string
 	CreatedAt time.Time
+	Hidden bool
 }
 
 func (community *Community) ToTableData() map[string]any {
@@ -17,11 +18,14 @@ func (community *Community) ToTableData() map[string]any {
 }
 
 func NewCommunityFromMap(data map[string]any) *Community {
+	hidden, _ := data["hidden"].(bool)
+
 	return &Community{
 		Id: data["id"].(int32),
 		CommunityName: data["community_name"].(string),
 		Description: data["description"].(string),
 		CreatedAt: data["created_at"].(time.Time),
+		Hidden: hidden,
 	}
 }
 
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const (
+	EXPORT_PENDING = "pending"
+	EXPORT_DONE    = "done"
+	EXPORT_FAILED  = "failed"
+)
+
+// DataExport is a job that bundles the data of a user into a ZIP file kept in
+// the export storage under StorageKey.
+type DataExport struct {
+	ExportId    int32
+	UserId      int32
+	Status      string
+	StorageKey  string
+	RequestedAt time.Time
+	CompletedAt *time.Time
+}
+
+func MapToDataExport(data map[string]any) *DataExport {
+	storageKey, _ := data["storage_key"].(string)
+
+	export := &DataExport{
+		ExportId:    data["id"].(int32),
+		UserId:      data["user_id"].(int32),
+		Status:      data["status"].(string),
+		StorageKey:  storageKey,
+		RequestedAt: data["requested_at"].(time.Time),
+	}
+
+	if completedAt, ok := data["completed_at"].(time.Time); ok {
+		export.CompletedAt = &completedAt
+	}
+
+	return export
+}
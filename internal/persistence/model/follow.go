@@ -0,0 +1,8 @@
+package model
+
+// FollowCounts summarizes the FOLLOWS edges of a user in Neo4j.
+type FollowCounts struct {
+	Followers        int64
+	FollowingUsers   int64
+	FollowingArtists int64
+}
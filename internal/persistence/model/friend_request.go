@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// FriendRequest is a pending FRIEND_REQUEST edge in Neo4j, sent by From to To.
+type FriendRequest struct {
+	From   string
+	To     string
+	SentAt time.Time
+}
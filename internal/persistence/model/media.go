@@ -0,0 +1,11 @@
+package model
+
+// Media describes an uploaded image and the thumbnail generated from it.
+type Media struct {
+	Key          string
+	ThumbnailKey string
+	ContentType  string
+	Size         int64
+	Width        int
+	Height       int
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	NOTIFICATION_POST_LIKED       = "post_liked"
+	NOTIFICATION_POST_COMMENTED   = "post_commented"
+	NOTIFICATION_POST_MENTION     = "post_mention"
+	NOTIFICATION_FRIEND_REQUEST   = "friend_request"
+	NOTIFICATION_FRIEND_ACCEPTED  = "friend_accepted"
+	NOTIFICATION_COMMUNITY_JOINED = "community_joined"
+	NOTIFICATION_CHAT_MESSAGE     = "chat_message"
+)
+
+var NOTIFICATION_TYPES = []string{
+	NOTIFICATION_POST_LIKED,
+	NOTIFICATION_POST_COMMENTED,
+	NOTIFICATION_POST_MENTION,
+	NOTIFICATION_FRIEND_REQUEST,
+	NOTIFICATION_FRIEND_ACCEPTED,
+	NOTIFICATION_COMMUNITY_JOINED,
+	NOTIFICATION_CHAT_MESSAGE,
+}
+
+// How many of the most recent actors of a notification are listed by name.
+const MAX_NOTIFICATION_ACTORS = 3
+
+// Notification tells a user that others did something related to them. While
+// it is unread, new actors doing the same on the same target are added to it
+// instead of creating another one, so it reads "Ana and 4 others liked your
+// post". TargetId is the post, chat or community it is about, or 0.
+type Notification struct {
+	NotificationId int32
+	UserId         int32
+	Type           string
+	TargetId       int32
+	TargetName     string
+	Actors         []string
+	ActorCount     int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ReadAt         time.Time
+}
+
+func ValidateNotificationType(notificationType string) error {
+	for _, known := range NOTIFICATION_TYPES {
+		if notificationType == known {
+			return nil
+		}
+	}
+	return errors.New("invalid notification type: " + notificationType)
+}
+
+// MapToNotification reads a row of the notification listing, where actors is
+// an array with the usernames of the most recent actors.
+func MapToNotification(data map[string]any) *Notification {
+	notification := &Notification{
+		NotificationId: data["id"].(int32),
+		UserId:         data["user_id"].(int32),
+		Type:           data["type"].(string),
+		Actors:         make([]string, 0),
+	}
+
+	notification.TargetId, _ = data["target_id"].(int32)
+	notification.TargetName, _ = data["target_name"].(string)
+	notification.ActorCount, _ = data["actor_count"].(int64)
+	notification.CreatedAt, _ = data["created_at"].(time.Time)
+	notification.UpdatedAt, _ = data["updated_at"].(time.Time)
+	notification.ReadAt, _ = data["read_at"].(time.Time)
+
+	actors, _ := data["actors"].([]any)
+	for _, actor := range actors {
+		if username, ok := actor.(string); ok {
+			notification.Actors = append(notification.Actors, username)
+		}
+	}
+
+	return notification
+}
+
+func (notification *Notification) IsRead() bool {
+	return !notification.ReadAt.IsZero()
+}
+
+// Summary describes the notification in a sentence.
+func (notification *Notification) Summary() string {
+	actors := describeActors(notification.Actors, notification.ActorCount)
+	several := notification.ActorCount > 1
+
+	switch notification.Type {
+	case NOTIFICATION_POST_LIKED:
+		return actors + " liked your post"
+	case NOTIFICATION_POST_COMMENTED:
+		return actors + " commented on your post"
+	case NOTIFICATION_POST_MENTION:
+		return actors + " mentioned you in a post"
+	case NOTIFICATION_FRIEND_REQUEST:
+		if several {
+			return actors + " sent you friend requests"
+		}
+		return actors + " sent you a friend request"
+	case NOTIFICATION_FRIEND_ACCEPTED:
+		return actors + " accepted your friend request"
+	case NOTIFICATION_COMMUNITY_JOINED:
+		if notification.TargetName != "" {
+			return actors + " joined " + notification.TargetName
+		}
+		return actors + " joined your community"
+	case NOTIFICATION_CHAT_MESSAGE:
+		if notification.TargetName != "" {
+			return actors + " sent messages in " + notification.TargetName
+		}
+		return actors + " sent you a message"
+	default:
+		return actors + " did something"
+	}
+}
+
+// describeActors names the most recent actor, or the two of them, and counts
+// the rest: "Ana", "Ana and Bob", "Ana and 4 others".
+func describeActors(actors []string, count int64) string {
+	if len(actors) == 0 {
+		if count <= 1 {
+			return "Someone"
+		}
+		return plural(count, "person", "people")
+	}
+	if count <= 1 {
+		return actors[0]
+	}
+	if count == 2 && len(actors) >= 2 {
+		return actors[0] + " and " + actors[1]
+	}
+	return actors[0] + " and " + plural(count-1, "other", "others")
+}
+
+// NotificationPreference tells whether the user muted a type of notification.
+type NotificationPreference struct {
+	Type  string
+	Muted bool
+}
+
+// NewNotificationPreferences lists every type of notification, marking the
+// muted ones.
+func NewNotificationPreferences(mutedTypes []string) []*NotificationPreference {
+	muted := make(map[string]bool, len(mutedTypes))
+	for _, notificationType := range mutedTypes {
+		muted[notificationType] = true
+	}
+
+	preferences := make([]*NotificationPreference, 0, len(NOTIFICATION_TYPES))
+	for _, notificationType := range NOTIFICATION_TYPES {
+		preferences = append(preferences, &NotificationPreference{
+			Type:  notificationType,
+			Muted: muted[notificationType],
+		})
+	}
+
+	return preferences
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_Summary(t *testing.T) {
+	tests := []struct {
+		notification *Notification
+		expected     string
+	}{
+		{&Notification{Type: NOTIFICATION_POST_LIKED, Actors: []string{"ana"}, ActorCount: 1}, "ana liked your post"},
+		{&Notification{Type: NOTIFICATION_POST_LIKED, Actors: []string{"ana", "bob"}, ActorCount: 2}, "ana and bob liked your post"},
+		{&Notification{Type: NOTIFICATION_POST_LIKED, Actors: []string{"ana", "bob", "carl"}, ActorCount: 5}, "ana and 4 others liked your post"},
+		{&Notification{Type: NOTIFICATION_FRIEND_REQUEST, Actors: []string{"ana", "bob", "carl"}, ActorCount: 3}, "ana and 2 others sent you friend requests"},
+		{&Notification{Type: NOTIFICATION_COMMUNITY_JOINED, TargetName: "Rock", Actors: []string{"ana"}, ActorCount: 1}, "ana joined Rock"},
+		{&Notification{Type: NOTIFICATION_CHAT_MESSAGE, Actors: []string{"ana"}, ActorCount: 1}, "ana sent you a message"},
+		{&Notification{Type: NOTIFICATION_POST_MENTION, ActorCount: 1}, "Someone mentioned you in a post"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.notification.Summary())
+	}
+}
+
+func TestMapToNotification(t *testing.T) {
+	notification := MapToNotification(map[string]any{
+		"id":          int32(1),
+		"user_id":     int32(2),
+		"type":        NOTIFICATION_POST_MENTION,
+		"target_id":   int32(9),
+		"target_name": nil,
+		"actor_count": int64(2),
+		"actors":      []any{"ana", "bob"},
+		"read_at":     nil,
+	})
+
+	assert.Equal(t, int32(9), notification.TargetId)
+	assert.Equal(t, []string{"ana", "bob"}, notification.Actors)
+	assert.False(t, notification.IsRead())
+}
+
+func TestValidateNotificationType(t *testing.T) {
+	assert.NoError(t, ValidateNotificationType(NOTIFICATION_CHAT_MESSAGE))
+	assert.Error(t, ValidateNotificationType("newsletter"))
+}
+
+func TestNewNotificationPreferences(t *testing.T) {
+	preferences := NewNotificationPreferences([]string{NOTIFICATION_CHAT_MESSAGE})
+
+	assert.Len(t, preferences, len(NOTIFICATION_TYPES))
+	for _, preference := range preferences {
+		assert.Equal(t, preference.Type == NOTIFICATION_CHAT_MESSAGE, preference.Muted)
+	}
+}
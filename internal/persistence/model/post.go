@@ -1,12 +1,20 @@
 package model
 
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const MAX_COMMENT_LENGTH = 2000
 
 type Post struct {
-	PostId    int32
-	UserId    int32  `json:"user_id"`
-	Text      string `json:"text"`
-	UrlFoto   string `json:"url_foto"`
-	LikeCount int    `json:"like_count"`
+	PostId     int32
+	UserId     int32  `json:"user_id"`
+	Text       string `json:"text"`
+	UrlFoto    string `json:"url_foto"`
+	LikeCount  int    `json:"like_count"`
+	Attachment *Attachment
 }
 
 func NewPost(
@@ -26,20 +34,39 @@ func NewPost(
 }
 
 func (post *Post) ToMap() map[string]any {
-	return map[string]any{
+	data := map[string]any{
 		"user_id":    post.UserId,
 		"text":       post.Text,
 		"url_foto":   post.UrlFoto,
 		"like_count": post.LikeCount,
 	}
+
+	if post.Attachment != nil {
+		data["attachment_type"] = post.Attachment.Type
+		data["attachment_id"] = post.Attachment.Id
+	}
+
+	return data
 }
 
 func MapToPost(data map[string]any) *Post {
 	return &Post{
-		PostId:    data["id"].(int32),
-		UserId:    data["user_id"].(int32),
-		Text:      data["text"].(string),
-		UrlFoto:   data["url_foto"].(string),
-		LikeCount: int(data["like_count"].(int32)),
+		PostId:     data["id"].(int32),
+		UserId:     data["user_id"].(int32),
+		Text:       data["text"].(string),
+		UrlFoto:    data["url_foto"].(string),
+		LikeCount:  int(data["like_count"].(int32)),
+		Attachment: MapToAttachment(data),
+	}
+}
+
+// ValidateComment checks the text of a comment on a post.
+func ValidateComment(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return errors.New("comment can not be empty")
+	}
+	if utf8.RuneCountInString(text) > MAX_COMMENT_LENGTH {
+		return errors.New("comment is too long")
 	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,3 +50,47 @@ func TestMapToPost(t *testing.T) {
 	assert.Equal(t, "test.jpg", p.UrlFoto)
 	assert.Equal(t, 5, p.LikeCount)
 }
+
+func TestPostToMapWithAttachment(t *testing.T) {
+	p := NewPost(1, 2, "Test post", "test.jpg", 0)
+	p.Attachment = &Attachment{Type: ATTACHMENT_SONG, Id: "665f1c2e8b3e4a0012345678"}
+
+	m := p.ToMap()
+
+	assert.Equal(t, ATTACHMENT_SONG, m["attachment_type"])
+	assert.Equal(t, "665f1c2e8b3e4a0012345678", m["attachment_id"])
+}
+
+func TestMapToPostWithAttachment(t *testing.T) {
+	data := map[string]any{
+		"id":              int32(1),
+		"user_id":         int32(2),
+		"text":            "Test post",
+		"url_foto":        "test.jpg",
+		"like_count":      int32(5),
+		"attachment_type": ATTACHMENT_PLAYLIST,
+		"attachment_id":   "665f1c2e8b3e4a0012345678",
+	}
+
+	p := MapToPost(data)
+
+	assert.NotNil(t, p.Attachment)
+	assert.Equal(t, ATTACHMENT_PLAYLIST, p.Attachment.Type)
+	assert.Equal(t, "665f1c2e8b3e4a0012345678", p.Attachment.Id)
+}
+
+func TestNewAttachment_Invalid(t *testing.T) {
+	_, err := NewAttachment("album", "665f1c2e8b3e4a0012345678")
+	assert.Error(t, err)
+
+	_, err = NewAttachment(ATTACHMENT_SONG, "not-an-object-id")
+	assert.Error(t, err)
+}
+
+func TestValidateComment(t *testing.T) {
+	assert.NoError(t, ValidateComment("Nice one"))
+	assert.NoError(t, ValidateComment(strings.Repeat("é", MAX_COMMENT_LENGTH)))
+	assert.Error(t, ValidateComment(""))
+	assert.Error(t, ValidateComment("  \n "))
+	assert.Error(t, ValidateComment(strings.Repeat("a", MAX_COMMENT_LENGTH+1)))
+}
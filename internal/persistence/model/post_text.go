@@ -0,0 +1,59 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Tags and mentions must start the text or follow a character that can not be
+// part of a word, so "a@b.com" and "C#" are not parsed.
+var hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&#@])#([\p{L}\p{N}_]{1,100})`)
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_#@.])@([A-Za-z0-9_.]{1,50})`)
+
+// NormalizeHashtag returns the form in which a hashtag is stored and searched:
+// lowercase and without the leading '#'.
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// ExtractHashtags returns the distinct normalized hashtags of a text, in the
+// order they first appear.
+func ExtractHashtags(text string) []string {
+	return extractUnique(hashtagPattern, text, NormalizeHashtag)
+}
+
+// ExtractMentions returns the distinct usernames mentioned in a text, in the
+// order they first appear. The usernames are not checked against the database.
+func ExtractMentions(text string) []string {
+	return extractUnique(mentionPattern, text, func(username string) string {
+		return strings.TrimRight(username, ".")
+	})
+}
+
+func extractUnique(pattern *regexp.Regexp, text string, normalize func(string) string) []string {
+	values := make([]string, 0)
+	seen := map[string]bool{}
+
+	for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+		value := normalize(match[1])
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+
+	return values
+}
+
+type TrendingHashtag struct {
+	Tag       string
+	PostCount int64
+}
+
+func MapToTrendingHashtag(data map[string]any) *TrendingHashtag {
+	return &TrendingHashtag{
+		Tag:       data["tag"].(string),
+		PostCount: data["post_count"].(int64),
+	}
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	tags := ExtractHashtags("#Jazz night with #bossa_nova and more #jazz! #MúsicaBrasileira")
+
+	assert.Equal(t, []string{"jazz", "bossa_nova", "músicabrasileira"}, tags)
+}
+
+func TestExtractHashtags_IgnoresWordsAndEntities(t *testing.T) {
+	tags := ExtractHashtags("I code in C#, see &#39; and issue#12")
+
+	assert.Empty(t, tags)
+}
+
+func TestExtractMentions(t *testing.T) {
+	mentions := ExtractMentions("@ana listening with @bob.silva. and @ana again")
+
+	assert.Equal(t, []string{"ana", "bob.silva"}, mentions)
+}
+
+func TestExtractMentions_IgnoresEmails(t *testing.T) {
+	mentions := ExtractMentions("write to john@example.com")
+
+	assert.Empty(t, mentions)
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	assert.Equal(t, "rock", NormalizeHashtag(" #Rock "))
+}
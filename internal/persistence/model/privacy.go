@@ -0,0 +1,64 @@
+package model
+
+import "errors"
+
+// Who can see the profile of a user.
+const (
+	PROFILE_PUBLIC  = "public"
+	PROFILE_FRIENDS = "friends"
+	PROFILE_PRIVATE = "private"
+)
+
+// Who can start chats with and send messages to a user.
+const (
+	MESSAGES_EVERYONE = "everyone"
+	MESSAGES_FRIENDS  = "friends"
+	MESSAGES_NOBODY   = "nobody"
+)
+
+func ValidatePrivacySettings(profileVisibility string, messagePermission string) error {
+	switch profileVisibility {
+	case PROFILE_PUBLIC, PROFILE_FRIENDS, PROFILE_PRIVATE:
+	default:
+		return errors.New("invalid profile visibility: " + profileVisibility)
+	}
+
+	switch messagePermission {
+	case MESSAGES_EVERYONE, MESSAGES_FRIENDS, MESSAGES_NOBODY:
+	default:
+		return errors.New("invalid message permission: " + messagePermission)
+	}
+
+	return nil
+}
+
+// CanSeeProfile tells whether a viewer can see the profile of a user with the
+// given visibility. Users can always see their own profile. An empty
+// visibility is treated as public.
+func CanSeeProfile(profileVisibility string, isSelf bool, isFriend bool) bool {
+	if isSelf {
+		return true
+	}
+
+	switch profileVisibility {
+	case PROFILE_PRIVATE:
+		return false
+	case PROFILE_FRIENDS:
+		return isFriend
+	default:
+		return true
+	}
+}
+
+// CanReceiveMessage tells whether a user with the given message permission
+// accepts messages from a sender. An empty permission is treated as everyone.
+func CanReceiveMessage(messagePermission string, isFriend bool) bool {
+	switch messagePermission {
+	case MESSAGES_NOBODY:
+		return false
+	case MESSAGES_FRIENDS:
+		return isFriend
+	default:
+		return true
+	}
+}
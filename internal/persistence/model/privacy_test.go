@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePrivacySettings(t *testing.T) {
+	assert.NoError(t, ValidatePrivacySettings(PROFILE_FRIENDS, MESSAGES_NOBODY))
+	assert.Error(t, ValidatePrivacySettings("hidden", MESSAGES_EVERYONE))
+	assert.Error(t, ValidatePrivacySettings(PROFILE_PUBLIC, "anyone"))
+}
+
+func TestCanSeeProfile(t *testing.T) {
+	assert.True(t, CanSeeProfile(PROFILE_PUBLIC, false, false))
+	assert.True(t, CanSeeProfile("", false, false))
+	assert.False(t, CanSeeProfile(PROFILE_FRIENDS, false, false))
+	assert.True(t, CanSeeProfile(PROFILE_FRIENDS, false, true))
+	assert.False(t, CanSeeProfile(PROFILE_PRIVATE, false, true))
+	assert.True(t, CanSeeProfile(PROFILE_PRIVATE, true, false))
+}
+
+func TestCanReceiveMessage(t *testing.T) {
+	assert.True(t, CanReceiveMessage(MESSAGES_EVERYONE, false))
+	assert.True(t, CanReceiveMessage("", false))
+	assert.False(t, CanReceiveMessage(MESSAGES_FRIENDS, false))
+	assert.True(t, CanReceiveMessage(MESSAGES_FRIENDS, true))
+	assert.False(t, CanReceiveMessage(MESSAGES_NOBODY, true))
+}
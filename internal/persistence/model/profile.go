@@ -0,0 +1,74 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const MAX_BIO_LENGTH = 500
+
+// Usernames use the same characters accepted by mentions, so every user can
+// be mentioned in a post.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.]{1,50}$`)
+
+func ValidateUsername(username string) error {
+	if !usernamePattern.MatchString(username) || strings.HasSuffix(username, ".") {
+		return errors.New("invalid username: " + username)
+	}
+
+	return nil
+}
+
+// ProfileUpdate holds the profile fields a user wants to change. Nil fields
+// are left as they are.
+type ProfileUpdate struct {
+	Username  *string
+	Fullname  *string
+	Bio       *string
+	AvatarUrl *string
+	Telephone *string
+}
+
+func (update *ProfileUpdate) Validate() error {
+	if update.Username != nil {
+		if err := ValidateUsername(*update.Username); err != nil {
+			return err
+		}
+	}
+	if update.Fullname != nil && (*update.Fullname == "" || utf8.RuneCountInString(*update.Fullname) > 100) {
+		return errors.New("fullname must have between 1 and 100 characters")
+	}
+	if update.Bio != nil && utf8.RuneCountInString(*update.Bio) > MAX_BIO_LENGTH {
+		return errors.New("bio is too long")
+	}
+	if update.Telephone != nil && utf8.RuneCountInString(*update.Telephone) > 20 {
+		return errors.New("telephone is too long")
+	}
+
+	return nil
+}
+
+// Apply returns a copy of the user with the changes applied.
+func (update *ProfileUpdate) Apply(user *User) *User {
+	updated := *user
+
+	if update.Username != nil {
+		updated.Username = *update.Username
+	}
+	if update.Fullname != nil {
+		updated.Fullname = *update.Fullname
+	}
+	if update.Bio != nil {
+		updated.Bio = *update.Bio
+	}
+	if update.AvatarUrl != nil {
+		updated.AvatarUrl = *update.AvatarUrl
+	}
+	if update.Telephone != nil {
+		updated.Telephone = *update.Telephone
+	}
+
+	return &updated
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUsername(t *testing.T) {
+	assert.NoError(t, ValidateUsername("john.doe_99"))
+	assert.Error(t, ValidateUsername(""))
+	assert.Error(t, ValidateUsername("john doe"))
+	assert.Error(t, ValidateUsername("john."))
+	assert.Error(t, ValidateUsername("joão"))
+}
+
+func TestProfileUpdate_Validate(t *testing.T) {
+	empty := ""
+	longBio := string(make([]byte, MAX_BIO_LENGTH+1))
+	username := "mary"
+
+	assert.NoError(t, (&ProfileUpdate{Username: &username}).Validate())
+	assert.Error(t, (&ProfileUpdate{Fullname: &empty}).Validate())
+	assert.Error(t, (&ProfileUpdate{Bio: &longBio}).Validate())
+}
+
+func TestProfileUpdate_Validate_CountsCharacters(t *testing.T) {
+	// Each of these takes two bytes, but the limits are in characters.
+	bio := strings.Repeat("ã", MAX_BIO_LENGTH)
+	fullname := strings.Repeat("é", 100)
+	longBio := strings.Repeat("ã", MAX_BIO_LENGTH+1)
+
+	assert.NoError(t, (&ProfileUpdate{Bio: &bio, Fullname: &fullname}).Validate())
+	assert.Error(t, (&ProfileUpdate{Bio: &longBio}).Validate())
+}
+
+func TestProfileUpdate_Apply(t *testing.T) {
+	user := &User{UserId: 1, Username: "john", Fullname: "John Doe", Telephone: "123"}
+	username := "johnny"
+	bio := "Jazz lover"
+
+	updated := (&ProfileUpdate{Username: &username, Bio: &bio}).Apply(user)
+
+	assert.Equal(t, "johnny", updated.Username)
+	assert.Equal(t, "Jazz lover", updated.Bio)
+	assert.Equal(t, "John Doe", updated.Fullname)
+	assert.Equal(t, "123", updated.Telephone)
+	assert.Equal(t, "john", user.Username)
+}
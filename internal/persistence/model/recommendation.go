@@ -0,0 +1,105 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Weights of each signal in the score of a recommendation.
+const (
+	MUTUAL_FRIEND_WEIGHT     = 3.0
+	GENRE_SIMILARITY_WEIGHT  = 10.0
+	SHARED_COMMUNITY_WEIGHT  = 2.0
+	SHARED_SONG_WEIGHT       = 0.5
+	MAX_SHARED_SONGS_COUNTED = 20
+)
+
+// Recommendation is a user suggested as a friend, with the signals that led to
+// the suggestion. User is only filled once the recommendation is returned.
+type Recommendation struct {
+	Username          string
+	User              *User
+	MutualFriends     int64
+	SharedGenres      []string
+	UserGenreCount    int64
+	CandidateGenres   int64
+	SharedCommunities int64
+	SharedSongs       int64
+	Score             float64
+	Explanation       string
+}
+
+// GenreSimilarity is the Jaccard index of the genres liked by both users.
+func (recommendation *Recommendation) GenreSimilarity() float64 {
+	shared := int64(len(recommendation.SharedGenres))
+	union := recommendation.UserGenreCount + recommendation.CandidateGenres - shared
+	if union <= 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// Rank fills the score and the explanation of the recommendation.
+func (recommendation *Recommendation) Rank() {
+	sharedSongs := recommendation.SharedSongs
+	if sharedSongs > MAX_SHARED_SONGS_COUNTED {
+		sharedSongs = MAX_SHARED_SONGS_COUNTED
+	}
+
+	recommendation.Score = MUTUAL_FRIEND_WEIGHT*float64(recommendation.MutualFriends) +
+		GENRE_SIMILARITY_WEIGHT*recommendation.GenreSimilarity() +
+		SHARED_COMMUNITY_WEIGHT*float64(recommendation.SharedCommunities) +
+		SHARED_SONG_WEIGHT*float64(sharedSongs)
+
+	recommendation.Explanation = recommendation.explain()
+}
+
+func (recommendation *Recommendation) explain() string {
+	reasons := make([]string, 0, 4)
+
+	if recommendation.MutualFriends > 0 {
+		reasons = append(reasons, plural(recommendation.MutualFriends, "mutual friend", "mutual friends"))
+	}
+	if len(recommendation.SharedGenres) > 0 {
+		reasons = append(reasons, "likes "+listGenres(recommendation.SharedGenres))
+	}
+	if recommendation.SharedCommunities > 0 {
+		reasons = append(reasons, plural(recommendation.SharedCommunities, "shared community", "shared communities"))
+	}
+	if recommendation.SharedSongs > 0 {
+		reasons = append(reasons, plural(recommendation.SharedSongs, "song in common", "songs in common"))
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+func plural(count int64, singular string, pluralForm string) string {
+	if count == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", count, pluralForm)
+}
+
+// listGenres names up to two genres and counts the rest.
+func listGenres(genres []string) string {
+	switch len(genres) {
+	case 1:
+		return genres[0]
+	case 2:
+		return genres[0] + " and " + genres[1]
+	default:
+		return fmt.Sprintf("%s, %s and %d more", genres[0], genres[1], len(genres)-2)
+	}
+}
+
+// SortRecommendations orders the recommendations by score, best first. Ties
+// are broken by username so pages are stable.
+func SortRecommendations(recommendations []*Recommendation) {
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Username < recommendations[j].Username
+	})
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecommendation_GenreSimilarity(t *testing.T) {
+	recommendation := &Recommendation{
+		SharedGenres:    []string{"jazz"},
+		UserGenreCount:  2,
+		CandidateGenres: 3,
+	}
+
+	assert.InDelta(t, 0.25, recommendation.GenreSimilarity(), 1e-9)
+	assert.Equal(t, 0.0, (&Recommendation{}).GenreSimilarity())
+}
+
+func TestRecommendation_Rank(t *testing.T) {
+	recommendation := &Recommendation{
+		Username:          "mary",
+		MutualFriends:     3,
+		SharedGenres:      []string{"Jazz"},
+		UserGenreCount:    1,
+		CandidateGenres:   1,
+		SharedCommunities: 1,
+		SharedSongs:       50,
+	}
+
+	recommendation.Rank()
+
+	assert.InDelta(t, 3*3.0+10.0+2.0+20*0.5, recommendation.Score, 1e-9)
+	assert.Equal(t, "3 mutual friends, likes Jazz, 1 shared community, 50 songs in common", recommendation.Explanation)
+}
+
+func TestRecommendation_ExplainManyGenres(t *testing.T) {
+	recommendation := &Recommendation{SharedGenres: []string{"Jazz", "Rock", "Blues", "Funk"}}
+
+	recommendation.Rank()
+
+	assert.Equal(t, "likes Jazz, Rock and 2 more", recommendation.Explanation)
+}
+
+func TestSortRecommendations(t *testing.T) {
+	recommendations := []*Recommendation{
+		{Username: "bob", Score: 1},
+		{Username: "mary", Score: 5},
+		{Username: "alice", Score: 1},
+	}
+
+	SortRecommendations(recommendations)
+
+	assert.Equal(t, "mary", recommendations[0].Username)
+	assert.Equal(t, "alice", recommendations[1].Username)
+	assert.Equal(t, "bob", recommendations[2].Username)
+}
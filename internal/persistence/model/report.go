@@ -0,0 +1,104 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	REPORT_STATUS_OPEN      = "open"
+	REPORT_STATUS_DISMISSED = "dismissed"
+	REPORT_STATUS_HIDDEN    = "hidden"
+	REPORT_STATUS_DELETED   = "deleted"
+)
+
+const (
+	REPORTED_POST         = "post"
+	REPORTED_COMMENT      = "comment"
+	REPORTED_CHAT_MESSAGE = "chat_message"
+	REPORTED_COMMUNITY    = "community"
+)
+
+// ReportableContent tells where each kind of reportable content is stored.
+type ReportableContent struct {
+	TableName string
+	IdColumn  string
+}
+
+var reportableContents = map[string]ReportableContent{
+	REPORTED_POST:         {TableName: "post", IdColumn: "id"},
+	REPORTED_COMMENT:      {TableName: "post_comment", IdColumn: "id_comment"},
+	REPORTED_CHAT_MESSAGE: {TableName: "chat_message", IdColumn: "message_id"},
+	REPORTED_COMMUNITY:    {TableName: "community", IdColumn: "id"},
+}
+
+func GetReportableContent(contentType string) (ReportableContent, error) {
+	content, ok := reportableContents[contentType]
+	if !ok {
+		return ReportableContent{}, errors.New("content can not be reported: " + contentType)
+	}
+	return content, nil
+}
+
+type Report struct {
+	ReportId    int32
+	ReporterId  int32
+	ContentType string
+	ContentId   int32
+	Reason      string
+	Status      string
+	CreatedAt   time.Time
+}
+
+func NewReport(reporterId int32, contentType string, contentId int32, reason string) *Report {
+	return &Report{
+		ReporterId:  reporterId,
+		ContentType: contentType,
+		ContentId:   contentId,
+		Reason:      reason,
+		Status:      REPORT_STATUS_OPEN,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func (report *Report) ToMap() map[string]any {
+	return map[string]any{
+		"reporter_id":  report.ReporterId,
+		"content_type": report.ContentType,
+		"content_id":   report.ContentId,
+		"reason":       report.Reason,
+		"status":       report.Status,
+	}
+}
+
+// ModerationItem groups all reports made against a single piece of content.
+type ModerationItem struct {
+	ContentType   string
+	ContentId     int32
+	ReporterCount int64
+	Reasons       []string
+	FirstReported time.Time
+	LastReported  time.Time
+	Hidden        bool
+}
+
+func MapToModerationItem(data map[string]any) *ModerationItem {
+	reasons := make([]string, 0)
+	if values, ok := data["reasons"].([]any); ok {
+		for _, reason := range values {
+			reasons = append(reasons, reason.(string))
+		}
+	}
+
+	hidden, _ := data["hidden"].(bool)
+
+	return &ModerationItem{
+		ContentType:   data["content_type"].(string),
+		ContentId:     data["content_id"].(int32),
+		ReporterCount: data["reporter_count"].(int64),
+		Reasons:       reasons,
+		FirstReported: data["first_reported"].(time.Time),
+		LastReported:  data["last_reported"].(time.Time),
+		Hidden:        hidden,
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapToModerationItem(t *testing.T) {
+	now := time.Now()
+	data := map[string]any{
+		"content_type":   REPORTED_POST,
+		"content_id":     int32(3),
+		"reporter_count": int64(2),
+		"reasons":        []any{"spam", "offensive"},
+		"first_reported": now.Add(-time.Hour),
+		"last_reported":  now,
+		"hidden":         true,
+	}
+
+	item := MapToModerationItem(data)
+
+	assert.Equal(t, REPORTED_POST, item.ContentType)
+	assert.Equal(t, int32(3), item.ContentId)
+	assert.Equal(t, int64(2), item.ReporterCount)
+	assert.Equal(t, []string{"spam", "offensive"}, item.Reasons)
+	assert.True(t, item.Hidden)
+}
+
+func TestGetReportableContent(t *testing.T) {
+	content, err := GetReportableContent(REPORTED_COMMENT)
+	assert.NoError(t, err)
+	assert.Equal(t, "post_comment", content.TableName)
+	assert.Equal(t, "id_comment", content.IdColumn)
+
+	_, err = GetReportableContent("playlist")
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MAX_SOCIAL_PATH_DEPTH bounds how many friendships a social path may span.
+const MAX_SOCIAL_PATH_DEPTH = 6
+
+// SocialConnection describes how two users are connected: the shortest chain
+// of friendships between them and what they have in common.
+type SocialConnection struct {
+	// Path goes from the first user to the second, both included. It is empty
+	// when the users are not connected within the requested depth. Users the
+	// first user is not allowed to see are nil.
+	Path              []*User
+	MutualFriends     []*User
+	SharedCommunities []*Community
+	SharedGenres      []string
+}
+
+// Degrees is the number of friendships between the two users, or -1 when
+// they are not connected.
+func (connection *SocialConnection) Degrees() int {
+	if len(connection.Path) == 0 {
+		return -1
+	}
+	return len(connection.Path) - 1
+}
+
+// Summary describes the connection in a sentence, for example
+// "2nd degree connection: 3 mutual friends, 1 shared community, both like Jazz".
+func (connection *SocialConnection) Summary() string {
+	var degree string
+	switch connection.Degrees() {
+	case -1:
+		degree = "Not connected"
+	case 0:
+		degree = "Same user"
+	default:
+		degree = ordinal(connection.Degrees()) + " degree connection"
+	}
+
+	details := make([]string, 0, 3)
+	if len(connection.MutualFriends) > 0 {
+		details = append(details, plural(int64(len(connection.MutualFriends)), "mutual friend", "mutual friends"))
+	}
+	if len(connection.SharedCommunities) > 0 {
+		details = append(details, plural(int64(len(connection.SharedCommunities)), "shared community", "shared communities"))
+	}
+	if len(connection.SharedGenres) > 0 {
+		details = append(details, "both like "+listGenres(connection.SharedGenres))
+	}
+
+	if len(details) == 0 {
+		return degree
+	}
+	return degree + ": " + strings.Join(details, ", ")
+}
+
+func ordinal(number int) string {
+	suffix := "th"
+	if number%100 < 11 || number%100 > 13 {
+		switch number % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", number, suffix)
+}
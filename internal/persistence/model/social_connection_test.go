@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSocialConnection_Degrees(t *testing.T) {
+	connection := &SocialConnection{
+		Path: []*User{{Username: "john"}, {Username: "bob"}, {Username: "mary"}},
+	}
+
+	assert.Equal(t, 2, connection.Degrees())
+	assert.Equal(t, -1, (&SocialConnection{}).Degrees())
+}
+
+func TestSocialConnection_Summary(t *testing.T) {
+	connection := &SocialConnection{
+		Path:              []*User{{Username: "john"}, {Username: "bob"}, {Username: "mary"}},
+		MutualFriends:     []*User{{Username: "bob"}},
+		SharedCommunities: []*Community{{CommunityName: "jazz"}, {CommunityName: "vinyl"}},
+		SharedGenres:      []string{"Jazz"},
+	}
+
+	assert.Equal(t, "2nd degree connection: 1 mutual friend, 2 shared communities, both like Jazz", connection.Summary())
+}
+
+func TestSocialConnection_SummaryNotConnected(t *testing.T) {
+	assert.Equal(t, "Not connected", (&SocialConnection{}).Summary())
+}
+
+func TestOrdinal(t *testing.T) {
+	assert.Equal(t, "1st", ordinal(1))
+	assert.Equal(t, "3rd", ordinal(3))
+	assert.Equal(t, "4th", ordinal(4))
+	assert.Equal(t, "11th", ordinal(11))
+	assert.Equal(t, "22nd", ordinal(22))
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const STORY_DURATION = 24 * time.Hour
+
+type StoryView struct {
+	Username string    `bson:"username"`
+	ViewedAt time.Time `bson:"viewed_at"`
+}
+
+// Story is a short lived "now listening" post. Mongo removes it through a TTL
+// index on ExpiresAt, but since the removal is not immediate readers must also
+// check IsActive.
+type Story struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Username  string             `bson:"username"`
+	SongID    primitive.ObjectID `bson:"song_id"`
+	Caption   string             `bson:"caption,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+	Views     []StoryView        `bson:"views"`
+}
+
+func NewStory(username string, songId primitive.ObjectID, caption string) *Story {
+	now := time.Now()
+	return &Story{
+		ID:        primitive.NewObjectID(),
+		Username:  username,
+		SongID:    songId,
+		Caption:   caption,
+		CreatedAt: now,
+		ExpiresAt: now.Add(STORY_DURATION),
+		Views:     []StoryView{},
+	}
+}
+
+func (story *Story) IsActive() bool {
+	return time.Now().Before(story.ExpiresAt)
+}
+
+func (story *Story) HasBeenViewedBy(username string) bool {
+	for _, view := range story.Views {
+		if view.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+// StoryTrayItem summarizes the active stories of one user for the tray.
+type StoryTrayItem struct {
+	Username   string
+	StoryCount int
+	LatestAt   time.Time
+	HasUnseen  bool
+}
+
+// BuildStoryTray groups stories by author. Authors with stories the viewer
+// has not seen come first, then the most recent ones.
+func BuildStoryTray(stories []Story, viewer string) []*StoryTrayItem {
+	itemsByUser := map[string]*StoryTrayItem{}
+	items := make([]*StoryTrayItem, 0)
+
+	for _, story := range stories {
+		item, ok := itemsByUser[story.Username]
+		if !ok {
+			item = &StoryTrayItem{Username: story.Username}
+			itemsByUser[story.Username] = item
+			items = append(items, item)
+		}
+
+		item.StoryCount++
+		if story.CreatedAt.After(item.LatestAt) {
+			item.LatestAt = story.CreatedAt
+		}
+		if !story.HasBeenViewedBy(viewer) {
+			item.HasUnseen = true
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].HasUnseen != items[j].HasUnseen {
+			return items[i].HasUnseen
+		}
+		return items[i].LatestAt.After(items[j].LatestAt)
+	})
+
+	return items
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNewStory(t *testing.T) {
+	story := NewStory("ana", primitive.NewObjectID(), "on repeat")
+
+	assert.True(t, story.IsActive())
+	assert.WithinDuration(t, story.CreatedAt.Add(STORY_DURATION), story.ExpiresAt, time.Second)
+	assert.False(t, story.HasBeenViewedBy("bob"))
+}
+
+func TestBuildStoryTray(t *testing.T) {
+	now := time.Now()
+	stories := []Story{
+		{Username: "ana", CreatedAt: now.Add(-3 * time.Hour), Views: []StoryView{{Username: "me"}}},
+		{Username: "bob", CreatedAt: now.Add(-2 * time.Hour)},
+		{Username: "ana", CreatedAt: now.Add(-time.Hour), Views: []StoryView{{Username: "me"}}},
+		{Username: "caio", CreatedAt: now.Add(-30 * time.Minute)},
+	}
+
+	tray := BuildStoryTray(stories, "me")
+
+	assert.Len(t, tray, 3)
+	assert.Equal(t, "caio", tray[0].Username)
+	assert.Equal(t, "bob", tray[1].Username)
+	assert.Equal(t, "ana", tray[2].Username)
+	assert.Equal(t, 2, tray[2].StoryCount)
+	assert.False(t, tray[2].HasUnseen)
+	assert.Equal(t, now.Add(-time.Hour), tray[2].LatestAt)
+}
@@ -12,6 +12,11 @@ type User struct {
 	Register_date time.Time
 	Birth_date time.Time
 	Telephone string
+	Bio string
+	AvatarUrl string
+	IsAdmin bool
+	ProfileVisibility string
+	MessagePermission string
 }
 
 func NewUser(
@@ -42,6 +47,12 @@ func (user *User) ToMap() map[string]any {
 }
 
 func MapToUser(data map[string]any) *User {
+	bio, _ := data["bio"].(string)
+	avatarUrl, _ := data["avatar_url"].(string)
+	isAdmin, _ := data["is_admin"].(bool)
+	profileVisibility, _ := data["profile_visibility"].(string)
+	messagePermission, _ := data["message_permission"].(string)
+
 	return &User{
 		UserId: data["id"].(int32),
 		Username: data["username"].(string),
@@ -50,6 +61,11 @@ func MapToUser(data map[string]any) *User {
 		Register_date: data["register_date"].(time.Time),
 		Birth_date: data["birth_date"].(time.Time),
 		Telephone: data["telephone"].(string),
+		Bio: bio,
+		AvatarUrl: avatarUrl,
+		IsAdmin: isAdmin,
+		ProfileVisibility: profileVisibility,
+		MessagePermission: messagePermission,
 	}
 }
 
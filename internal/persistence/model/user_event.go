@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// Most events replayed when a stream resumes. Clients that missed more
+	// reload their state instead.
+	USER_EVENT_MAX_REPLAY = 500
+
+	// Most events deleted by each statement when pruning.
+	USER_EVENT_PRUNE_BATCH = 5000
+)
+
+// UserEvent is an event addressed to a user, kept in the event log so their
+// stream can resume after the last one it received. Data is the JSON payload.
+type UserEvent struct {
+	EventId   int64
+	UserId    int32
+	Type      string
+	Data      json.RawMessage
+	CreatedAt time.Time
+}
+
+// MapToUserEvent reads an event row. The data column must be selected as text.
+func MapToUserEvent(data map[string]any) *UserEvent {
+	payload, _ := data["data"].(string)
+	createdAt, _ := data["created_at"].(time.Time)
+
+	return &UserEvent{
+		EventId:   data["event_id"].(int64),
+		UserId:    data["user_id"].(int32),
+		Type:      data["type"].(string),
+		Data:      json.RawMessage(payload),
+		CreatedAt: createdAt,
+	}
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MIN_SEARCH_QUERY_LENGTH = 2
+	MAX_SEARCH_QUERY_LENGTH = 100
+	MAX_SEARCH_CANDIDATES   = 100
+)
+
+// Boosts added to the text similarity of a search result.
+const (
+	SEARCH_PREFIX_BOOST        = 0.5
+	SEARCH_FRIEND_BOOST        = 0.4
+	SEARCH_MUTUAL_FRIEND_BOOST = 0.05
+	SEARCH_MAX_MUTUAL_BOOST    = 0.3
+	SEARCH_FOLLOWING_BOOST     = 0.2
+)
+
+// UserSearchResult is a user matching a search query. Similarity and
+// PrefixMatch come from Postgres; IsFriend, MutualFriends and Following
+// describe how close the user is to the one searching.
+type UserSearchResult struct {
+	User          *User
+	Similarity    float64
+	PrefixMatch   bool
+	IsFriend      bool
+	MutualFriends int64
+	Following     bool
+	Score         float64
+}
+
+// SocialProximity is how close a user is to another one in the graph.
+type SocialProximity struct {
+	IsFriend      bool
+	MutualFriends int64
+	Following     bool
+}
+
+// NormalizeSearchQuery trims the query and checks its length.
+func NormalizeSearchQuery(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	length := utf8.RuneCountInString(query)
+
+	if length < MIN_SEARCH_QUERY_LENGTH {
+		return "", errors.New("search query is too short")
+	}
+	if length > MAX_SEARCH_QUERY_LENGTH {
+		return "", errors.New("search query is too long")
+	}
+
+	return query, nil
+}
+
+// EscapeLikePattern escapes the characters LIKE gives a meaning to, so the
+// text is matched literally.
+func EscapeLikePattern(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
+
+// MapToUserSearchResult reads a users row extended with the similarity and
+// prefix_match columns of the search query.
+func MapToUserSearchResult(data map[string]any) *UserSearchResult {
+	similarity, _ := data["similarity"].(float64)
+	prefixMatch, _ := data["prefix_match"].(bool)
+
+	return &UserSearchResult{
+		User:        MapToUser(data),
+		Similarity:  similarity,
+		PrefixMatch: prefixMatch,
+	}
+}
+
+// ApplyProximity copies the social proximity of the user into the result.
+func (result *UserSearchResult) ApplyProximity(proximity *SocialProximity) {
+	if proximity == nil {
+		return
+	}
+
+	result.IsFriend = proximity.IsFriend
+	result.MutualFriends = proximity.MutualFriends
+	result.Following = proximity.Following
+}
+
+// Rank fills the score of the result: its text similarity boosted by prefix
+// matches and by social proximity.
+func (result *UserSearchResult) Rank() {
+	score := result.Similarity
+
+	if result.PrefixMatch {
+		score += SEARCH_PREFIX_BOOST
+	}
+	if result.IsFriend {
+		score += SEARCH_FRIEND_BOOST
+	}
+	if result.Following {
+		score += SEARCH_FOLLOWING_BOOST
+	}
+
+	mutualBoost := SEARCH_MUTUAL_FRIEND_BOOST * float64(result.MutualFriends)
+	if mutualBoost > SEARCH_MAX_MUTUAL_BOOST {
+		mutualBoost = SEARCH_MAX_MUTUAL_BOOST
+	}
+	score += mutualBoost
+
+	result.Score = score
+}
+
+// SortUserSearchResults orders the results by score, best first, breaking ties
+// by username.
+func SortUserSearchResults(results []*UserSearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].User.Username < results[j].User.Username
+	})
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	query, err := NormalizeSearchQuery("  joão ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "joão", query)
+
+	_, err = NormalizeSearchQuery(" j ")
+	assert.Error(t, err)
+}
+
+func TestEscapeLikePattern(t *testing.T) {
+	assert.Equal(t, `john\_doe\%\\`, EscapeLikePattern(`john_doe%\`))
+}
+
+func TestUserSearchResult_Rank(t *testing.T) {
+	result := &UserSearchResult{
+		User:        &User{Username: "maria"},
+		Similarity:  0.4,
+		PrefixMatch: true,
+	}
+	result.ApplyProximity(&SocialProximity{IsFriend: true, MutualFriends: 10})
+
+	result.Rank()
+
+	assert.InDelta(t, 0.4+0.5+0.4+0.3, result.Score, 1e-9)
+}
+
+func TestSortUserSearchResults(t *testing.T) {
+	results := []*UserSearchResult{
+		{User: &User{Username: "mario"}, Similarity: 0.5},
+		{User: &User{Username: "maria"}, Similarity: 0.5},
+		{User: &User{Username: "marina"}, Similarity: 0.3, IsFriend: true},
+	}
+	for _, result := range results {
+		result.Rank()
+	}
+
+	SortUserSearchResults(results)
+
+	assert.Equal(t, "marina", results[0].User.Username)
+	assert.Equal(t, "maria", results[1].User.Username)
+	assert.Equal(t, "mario", results[2].User.Username)
+}
@@ -0,0 +1,49 @@
+package model
+
+// Projections of user data, chosen by the relationship between the viewer and
+// the user being shown.
+const (
+	// USER_VIEW_PUBLIC only exposes the username, full name and register date.
+	USER_VIEW_PUBLIC = "public"
+	// USER_VIEW_FRIEND also exposes the email and birth date.
+	USER_VIEW_FRIEND = "friend"
+	// USER_VIEW_SELF exposes every field, telephone included.
+	USER_VIEW_SELF = "self"
+)
+
+// UserViews tells which projection a viewer gets of each user, keyed by
+// username. Users missing from it get the public view.
+type UserViews map[string]string
+
+// NewUserViews resolves the view of each user given the viewer and the
+// usernames of their friends. An empty viewer stands for an anonymous request.
+//
+// The viewer is declared by the caller, so it is never trusted with the self
+// view: users naming themselves get the friend view. The self view is only
+// given to requests authenticated as the user.
+func NewUserViews(viewer string, users []*User, friends []string) UserViews {
+	isFriend := make(map[string]bool, len(friends))
+	for _, friend := range friends {
+		isFriend[friend] = true
+	}
+
+	views := make(UserViews, len(users))
+	for _, user := range users {
+		switch {
+		case viewer != "" && user.Username == viewer, isFriend[user.Username]:
+			views[user.Username] = USER_VIEW_FRIEND
+		default:
+			views[user.Username] = USER_VIEW_PUBLIC
+		}
+	}
+
+	return views
+}
+
+func (views UserViews) Of(user *User) string {
+	if view, ok := views[user.Username]; ok {
+		return view
+	}
+
+	return USER_VIEW_PUBLIC
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserViews(t *testing.T) {
+	john := &User{Username: "john"}
+	mary := &User{Username: "mary"}
+	bob := &User{Username: "bob"}
+
+	views := NewUserViews("john", []*User{john, mary, bob}, []string{"mary"})
+
+	assert.Equal(t, USER_VIEW_FRIEND, views.Of(john))
+	assert.Equal(t, USER_VIEW_FRIEND, views.Of(mary))
+	assert.Equal(t, USER_VIEW_PUBLIC, views.Of(bob))
+	assert.Equal(t, USER_VIEW_PUBLIC, views.Of(&User{Username: "alice"}))
+}
+
+func TestNewUserViews_Anonymous(t *testing.T) {
+	john := &User{Username: "john"}
+
+	views := NewUserViews("", []*User{john}, nil)
+
+	assert.Equal(t, USER_VIEW_PUBLIC, views.Of(john))
+}
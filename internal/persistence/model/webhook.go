@@ -0,0 +1,128 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	WEBHOOK_DELIVERY_PENDING   = "pending"
+	WEBHOOK_DELIVERY_DELIVERED = "delivered"
+	WEBHOOK_DELIVERY_DEAD      = "dead"
+)
+
+// WebhookSubscription sends the platform events of the given types to Url,
+// signed with Secret.
+type WebhookSubscription struct {
+	SubscriptionId int32
+	Url            string
+	Secret         string
+	EventTypes     []string
+	Active         bool
+	CreatedBy      int32
+	CreatedAt      time.Time
+}
+
+func MapToWebhookSubscription(data map[string]any) *WebhookSubscription {
+	subscription := &WebhookSubscription{
+		SubscriptionId: data["subscription_id"].(int32),
+		Url:            data["url"].(string),
+		Secret:         data["secret"].(string),
+		EventTypes:     make([]string, 0),
+	}
+
+	subscription.Active, _ = data["active"].(bool)
+	subscription.CreatedBy, _ = data["created_by"].(int32)
+	subscription.CreatedAt, _ = data["created_at"].(time.Time)
+
+	eventTypes, _ := data["event_types"].([]any)
+	for _, eventType := range eventTypes {
+		if value, ok := eventType.(string); ok {
+			subscription.EventTypes = append(subscription.EventTypes, value)
+		}
+	}
+
+	return subscription
+}
+
+// WebhookDelivery is an event queued to be sent to a subscription. ReplayOf
+// is the delivery it copies when it was replayed, or 0. Url and Secret are
+// only filled when the delivery is claimed to be sent.
+type WebhookDelivery struct {
+	DeliveryId     int64
+	SubscriptionId int32
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	ReplayOf       int64
+	CreatedAt      time.Time
+	DeliveredAt    time.Time
+	Url            string
+	Secret         string
+}
+
+// MapToWebhookDelivery reads a delivery row. The payload column must be
+// selected as text.
+func MapToWebhookDelivery(data map[string]any) *WebhookDelivery {
+	delivery := &WebhookDelivery{
+		DeliveryId:     data["delivery_id"].(int64),
+		SubscriptionId: data["subscription_id"].(int32),
+		EventType:      data["event_type"].(string),
+		Status:         data["status"].(string),
+	}
+
+	payload, _ := data["payload"].(string)
+	delivery.Payload = json.RawMessage(payload)
+	delivery.Attempts, _ = data["attempts"].(int32)
+	delivery.NextAttemptAt, _ = data["next_attempt_at"].(time.Time)
+	delivery.ReplayOf, _ = data["replay_of"].(int64)
+	delivery.CreatedAt, _ = data["created_at"].(time.Time)
+	delivery.DeliveredAt, _ = data["delivered_at"].(time.Time)
+	delivery.Url, _ = data["url"].(string)
+	delivery.Secret, _ = data["secret"].(string)
+
+	return delivery
+}
+
+// ValidateWebhookDeliveryStatus accepts the delivery statuses, or an empty
+// one meaning any of them.
+func ValidateWebhookDeliveryStatus(status string) error {
+	switch status {
+	case "", WEBHOOK_DELIVERY_PENDING, WEBHOOK_DELIVERY_DELIVERED, WEBHOOK_DELIVERY_DEAD:
+		return nil
+	default:
+		return errors.New("invalid delivery status: " + status)
+	}
+}
+
+// WebhookAttempt is the log of one attempt to send a delivery. StatusCode is
+// 0 when the receiver did not answer, Error telling why.
+type WebhookAttempt struct {
+	AttemptId    int64
+	DeliveryId   int64
+	Attempt      int32
+	StatusCode   int32
+	Error        string
+	ResponseBody string
+	DurationMs   int32
+	AttemptedAt  time.Time
+}
+
+func MapToWebhookAttempt(data map[string]any) *WebhookAttempt {
+	attempt := &WebhookAttempt{
+		AttemptId:  data["attempt_id"].(int64),
+		DeliveryId: data["delivery_id"].(int64),
+		Attempt:    data["attempt"].(int32),
+	}
+
+	attempt.StatusCode, _ = data["status_code"].(int32)
+	attempt.Error, _ = data["error"].(string)
+	attempt.ResponseBody, _ = data["response_body"].(string)
+	attempt.DurationMs, _ = data["duration_ms"].(int32)
+	attempt.AttemptedAt, _ = data["attempted_at"].(time.Time)
+
+	return attempt
+}
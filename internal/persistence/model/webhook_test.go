@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapToWebhookSubscription(t *testing.T) {
+	subscription := MapToWebhookSubscription(map[string]any{
+		"subscription_id": int32(1),
+		"url":             "https://partner.example.com/hooks",
+		"secret":          "whsec_abc",
+		"event_types":     []any{"user.created", "post.created"},
+		"active":          true,
+		"created_by":      nil,
+	})
+
+	assert.Equal(t, []string{"user.created", "post.created"}, subscription.EventTypes)
+	assert.True(t, subscription.Active)
+	assert.Equal(t, int32(0), subscription.CreatedBy)
+}
+
+func TestMapToWebhookDelivery(t *testing.T) {
+	delivery := MapToWebhookDelivery(map[string]any{
+		"delivery_id":     int64(5),
+		"subscription_id": int32(1),
+		"event_type":      "user.created",
+		"payload":         `{"type":"user.created"}`,
+		"status":          WEBHOOK_DELIVERY_PENDING,
+		"attempts":        int32(2),
+		"replay_of":       nil,
+		"delivered_at":    nil,
+	})
+
+	assert.Equal(t, json.RawMessage(`{"type":"user.created"}`), delivery.Payload)
+	assert.Equal(t, int32(2), delivery.Attempts)
+	assert.Equal(t, int64(0), delivery.ReplayOf)
+	assert.True(t, delivery.DeliveredAt.IsZero())
+}
+
+func TestValidateWebhookDeliveryStatus(t *testing.T) {
+	assert.NoError(t, ValidateWebhookDeliveryStatus(""))
+	assert.NoError(t, ValidateWebhookDeliveryStatus(WEBHOOK_DELIVERY_DEAD))
+	assert.Error(t, ValidateWebhookDeliveryStatus("failed"))
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 	"symphony-api/internal/persistence/connectors/postgres"
 	"symphony-api/internal/persistence/model"
 )
@@ -55,6 +56,116 @@ func (repository *ChatRepository) AddUserToChat(user *model.User, chat *model.Ch
 	)
 }
 
+func (repository *ChatRepository) AddMember(chatId int32, userId int32, role string) error {
+	return repository.connection.Put(
+		map[string]any{
+			"chat_id": chatId,
+			"user_id": userId,
+			"role": role,
+		},
+		USER_TO_CHAT_TABLE,
+	)
+}
+
+// CreateGroup creates a group chat with the creator as its admin and the
+// other users as members. Everything is written in a single statement, so a
+// failure leaves no chat behind.
+func (repository *ChatRepository) CreateGroup(name string, creatorId int32, memberIds []int32) (*model.Chat, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		WITH created AS (
+			INSERT INTO chat (name, is_group)
+			VALUES ($1, TRUE)
+			RETURNING *
+		), members AS (
+			INSERT INTO chat_participants (chat_id, user_id, role)
+			SELECT chat_id, $2::int, $4::varchar FROM created
+			UNION ALL
+			SELECT chat_id, member_id, $5::varchar FROM created, unnest($3::int[]) AS member_id
+		)
+		SELECT * FROM created
+		`,
+		name,
+		creatorId,
+		memberIds,
+		model.CHAT_ROLE_ADMIN,
+		model.CHAT_ROLE_MEMBER,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("could not create chat")
+	}
+
+	return model.MapToChat(data[0]), nil
+}
+
+// RemoveMember takes the user out of the chat. It returns false when the user
+// was not part of it.
+func (repository *ChatRepository) RemoveMember(chatId int32, userId int32) (bool, error) {
+	removed, err := repository.connection.ExecuteReturning(
+		"DELETE FROM chat_participants WHERE chat_id = $1 AND user_id = $2 RETURNING user_id",
+		chatId,
+		userId,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return len(removed) > 0, nil
+}
+
+func (repository *ChatRepository) SetMemberRole(chatId int32, userId int32, role string) error {
+	return repository.connection.Execute(
+		"UPDATE chat_participants SET role = $1 WHERE chat_id = $2 AND user_id = $3",
+		role,
+		chatId,
+		userId,
+	)
+}
+
+// ListMembers returns the participants of a chat with their roles, in the
+// order they joined.
+func (repository *ChatRepository) ListMembers(chatId int32) ([]*model.ChatMember, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT u.*, cp.role, cp.joined_at
+		FROM chat_participants cp
+		JOIN users u ON u.id = cp.user_id
+		WHERE cp.chat_id = $1
+		ORDER BY cp.joined_at, u.username
+		`,
+		chatId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*model.ChatMember, 0, len(data))
+	for _, member := range data {
+		members = append(members, model.MapToChatMember(member))
+	}
+
+	return members, nil
+}
+
+func (repository *ChatRepository) Rename(chatId int32, name string) error {
+	return repository.connection.Execute(
+		"UPDATE chat SET name = $1 WHERE chat_id = $2 AND is_group",
+		name,
+		chatId,
+	)
+}
+
+// Delete removes the chat together with its participants and messages.
+func (repository *ChatRepository) Delete(chatId int32) error {
+	return repository.connection.Execute(
+		"DELETE FROM chat WHERE chat_id = $1",
+		chatId,
+	)
+}
+
 func (repository *ChatRepository) ListUsersFromChat(chat *model.Chat) ([]*model.User, error) {
 	constraint := map[string]any{
 		"cp.chat_id": chat.ChatId,
@@ -77,6 +188,173 @@ func (repository *ChatRepository) ListUsersFromChat(chat *model.Chat) ([]*model.
 	return userList, nil
 }
 
+func (repository *ChatRepository) IsParticipant(chatId int32, userId int32) (bool, error) {
+	constraint := map[string]any{
+		"chat_id": chatId,
+		"user_id": userId,
+	}
+
+	participants, err := repository.connection.Get(constraint, USER_TO_CHAT_TABLE)
+	if err != nil {
+		return false, err
+	}
+
+	return len(participants) > 0, nil
+}
+
+// MarkRead moves the read marker of the participant to the newest message of
+// the chat that is not newer than messageId, or to the newest message of the
+// chat when messageId is 0. The marker never moves back. It returns the
+// resulting marker, or 0 when nothing changed because the chat has no such
+// message.
+func (repository *ChatRepository) MarkRead(chatId int32, userId int32, messageId int32) (int32, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		UPDATE chat_participants cp
+		SET last_read_message_id = GREATEST(COALESCE(cp.last_read_message_id, 0), target.message_id),
+			last_read_at = now()
+		FROM (
+			SELECT MAX(message_id) AS message_id
+			FROM chat_message
+			WHERE chat_id = $1 AND ($3 = 0 OR message_id <= $3)
+		) target
+		WHERE cp.chat_id = $1 AND cp.user_id = $2 AND target.message_id IS NOT NULL
+		RETURNING cp.last_read_message_id
+		`,
+		chatId,
+		userId,
+		messageId,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	lastRead, _ := data[0]["last_read_message_id"].(int32)
+	return lastRead, nil
+}
+
+// ListReadStates returns the read marker of every participant of the chat.
+func (repository *ChatRepository) ListReadStates(chatId int32) ([]*model.ChatReadState, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT cp.user_id, u.username, cp.last_read_message_id, cp.last_read_at
+		FROM chat_participants cp
+		JOIN users u ON u.id = cp.user_id
+		WHERE cp.chat_id = $1
+		ORDER BY u.username
+		`,
+		chatId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*model.ChatReadState, 0, len(data))
+	for _, state := range data {
+		states = append(states, model.MapToChatReadState(state))
+	}
+
+	return states, nil
+}
+
+// CountUnreadByUser returns, for each chat of the user, how many visible and
+// not deleted messages written by others are newer than their read marker.
+func (repository *ChatRepository) CountUnreadByUser(userId int32) (map[int32]int64, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT cp.chat_id, COUNT(m.message_id) AS unread_count
+		FROM chat_participants cp
+		LEFT JOIN chat_message m ON m.chat_id = cp.chat_id
+			AND m.message_id > COALESCE(cp.last_read_message_id, 0)
+			AND m.author_id IS DISTINCT FROM cp.user_id
+			AND NOT m.hidden
+			AND m.deleted_at IS NULL
+		WHERE cp.user_id = $1
+		GROUP BY cp.chat_id
+		`,
+		userId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int32]int64, len(data))
+	for _, row := range data {
+		chatId, _ := row["chat_id"].(int32)
+		count, _ := row["unread_count"].(int64)
+		counts[chatId] = count
+	}
+
+	return counts, nil
+}
+
+// GetInbox returns a page of the chats of the user, most recently active
+// first, each with its participants, newest visible message and unread count,
+// all in a single query.
+func (repository *ChatRepository) GetInbox(userId int32, limit int32, offset int32) ([]*model.ChatInboxEntry, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT c.chat_id, c.name, c.is_group, c.created_at,
+			last.message_id AS last_message_id,
+			last.author_id AS last_message_author_id,
+			author.username AS last_message_author,
+			last.message AS last_message,
+			last.sent_at AS last_message_at,
+			last.deleted_at AS last_message_deleted_at,
+			COALESCE(last.sent_at, c.created_at) AS last_activity,
+			(
+				SELECT COUNT(*)
+				FROM chat_message m
+				WHERE m.chat_id = c.chat_id
+					AND m.message_id > COALESCE(me.last_read_message_id, 0)
+					AND m.author_id IS DISTINCT FROM me.user_id
+					AND NOT m.hidden
+					AND m.deleted_at IS NULL
+			) AS unread_count,
+			(
+				SELECT json_agg(json_build_object(
+					'username', u.username,
+					'fullname', u.fullname,
+					'avatar_url', u.avatar_url,
+					'role', p.role
+				) ORDER BY p.joined_at, u.username)
+				FROM chat_participants p
+				JOIN users u ON u.id = p.user_id
+				WHERE p.chat_id = c.chat_id
+			) AS participants
+		FROM chat_participants me
+		JOIN chat c ON c.chat_id = me.chat_id
+		LEFT JOIN LATERAL (
+			SELECT m.message_id, m.author_id, m.message, m.sent_at, m.deleted_at
+			FROM chat_message m
+			WHERE m.chat_id = c.chat_id AND NOT m.hidden
+			ORDER BY m.message_id DESC
+			LIMIT 1
+		) last ON TRUE
+		LEFT JOIN users author ON author.id = last.author_id
+		WHERE me.user_id = $1
+		ORDER BY last_activity DESC, c.chat_id DESC
+		LIMIT $2 OFFSET $3
+		`,
+		userId,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*model.ChatInboxEntry, 0, len(data))
+	for _, entry := range data {
+		entries = append(entries, model.MapToChatInboxEntry(entry))
+	}
+
+	return entries, nil
+}
+
 func (repository *ChatRepository) ListChatsByUser(user *model.User) ([]*model.Chat, error) {
     constraint := map[string]any{
         "cp.user_id": user.UserId,
@@ -99,39 +377,39 @@ func (repository *ChatRepository) ListChatsByUser(user *model.User) ([]*model.Ch
     return chatList, nil
 }
 
+// FindChatByUsers returns the direct chat between two users, or nil when they
+// have none. Group chats they share are not considered.
 func (repository *ChatRepository) FindChatByUsers(userId1, userId2 int32) (*model.Chat, error) {
-    user1 := &model.User{UserId: userId1}
-    chats, err := repository.ListChatsByUser(user1)
+    chats, err := repository.connection.ExecuteReturning(
+        `
+        SELECT c.* FROM chat c
+        JOIN chat_participants cp1 ON cp1.chat_id = c.chat_id AND cp1.user_id = $1
+        JOIN chat_participants cp2 ON cp2.chat_id = c.chat_id AND cp2.user_id = $2
+        WHERE NOT c.is_group
+        LIMIT 1
+        `,
+        userId1,
+        userId2,
+    )
     if err != nil {
         return nil, err
     }
-    for _, chat := range chats {
-        users, err := repository.ListUsersFromChat(chat)
-        if err != nil {
-            continue
-        }
-        found1, found2 := false, false
-        for _, u := range users {
-            if u.UserId == userId1 {
-                found1 = true
-            }
-            if u.UserId == userId2 {
-                found2 = true
-            }
-        }
-        if found1 && found2 && len(users) == 2 {
-            return chat, nil
-        }
-    }
-    return nil, nil
-}
-
-func (repository *ChatRepository) AddMessageToChatAndReturn(chatId int32, authorId int32, message string) (*model.ChatMessage, error) {
+    if len(chats) == 0 {
+        return nil, nil
+    }
+    return model.MapToChat(chats[0]), nil
+}
+
+func (repository *ChatRepository) AddMessageToChatAndReturn(chatId int32, authorId int32, message string, attachment *model.Attachment) (*model.ChatMessage, error) {
     data := map[string]any{
         "chat_id":  chatId,
         "author_id": authorId,
         "message":   message,
     }
+    if attachment != nil {
+        data["attachment_type"] = attachment.Type
+        data["attachment_id"] = attachment.Id
+    }
     id, err := repository.connection.PutReturningId(data, CHAT_MESSAGE_TABLE, "message_id")
     if err != nil {
         return nil, err
@@ -147,21 +425,261 @@ func (repository *ChatRepository) AddMessageToChatAndReturn(chatId int32, author
     return model.MapToChatMessage(msgs[0]), nil
 }
 
-func (repository *ChatRepository) ListMessagesFromChat(chatId int32, limit int32) ([]*model.ChatMessage, error) {
-    messagesData, err := repository.connection.GetChatWithLimit(
+// GetMessage returns a message by its id, or nil when it does not exist or
+// was hidden by a moderator.
+func (repository *ChatRepository) GetMessage(messageId int32) (*model.ChatMessage, error) {
+    constraint := map[string]any{
+        "message_id": messageId,
+        "hidden": false,
+    }
+    messages, err := repository.connection.Get(constraint, CHAT_MESSAGE_TABLE)
+    if err != nil {
+        return nil, err
+    }
+    if len(messages) == 0 {
+        return nil, nil
+    }
+    return model.MapToChatMessage(messages[0]), nil
+}
+
+// EditMessage replaces the text of a message, keeping the previous one in its
+// history. It returns nil when the message was deleted in the meantime.
+func (repository *ChatRepository) EditMessage(messageId int32, message string) (*model.ChatMessage, error) {
+    data, err := repository.connection.ExecuteReturning(
+        `
+        WITH previous AS (
+            SELECT message_id, message
+            FROM chat_message
+            WHERE message_id = $1 AND deleted_at IS NULL
+            FOR UPDATE
+        ), history AS (
+            INSERT INTO chat_message_edit (message_id, message)
+            SELECT message_id, message FROM previous
+        )
+        UPDATE chat_message m
+        SET message = $2, edited_at = now()
+        FROM previous
+        WHERE m.message_id = previous.message_id
+        RETURNING m.*
+        `,
+        messageId,
+        message,
+    )
+    if err != nil {
+        return nil, err
+    }
+    if len(data) == 0 {
+        return nil, nil
+    }
+    return model.MapToChatMessage(data[0]), nil
+}
+
+// DeleteMessage turns a message into a tombstone: its text, attachment, edit
+// history and reactions are removed but its place in the chat is kept. It returns nil when
+// the message was already deleted.
+func (repository *ChatRepository) DeleteMessage(messageId int32) (*model.ChatMessage, error) {
+    data, err := repository.connection.ExecuteReturning(
+        `
+        WITH history AS (
+            DELETE FROM chat_message_edit WHERE message_id = $1
+        ), reactions AS (
+            DELETE FROM chat_message_reaction WHERE message_id = $1
+        )
+        UPDATE chat_message
+        SET message = '', attachment_type = NULL, attachment_id = NULL, deleted_at = now()
+        WHERE message_id = $1 AND deleted_at IS NULL
+        RETURNING *
+        `,
+        messageId,
+    )
+    if err != nil {
+        return nil, err
+    }
+    if len(data) == 0 {
+        return nil, nil
+    }
+    return model.MapToChatMessage(data[0]), nil
+}
+
+// ListMessageEdits returns the previous versions of a message, oldest first.
+func (repository *ChatRepository) ListMessageEdits(messageId int32) ([]*model.ChatMessageEdit, error) {
+    data, err := repository.connection.ExecuteReturning(
+        "SELECT * FROM chat_message_edit WHERE message_id = $1 ORDER BY edit_id",
+        messageId,
+    )
+    if err != nil {
+        return nil, err
+    }
+
+    edits := make([]*model.ChatMessageEdit, 0, len(data))
+    for _, row := range data {
+        edits = append(edits, model.MapToChatMessageEdit(row))
+    }
+
+    return edits, nil
+}
+
+// AddReaction reacts to a message on behalf of a user. It returns false when
+// they had already reacted with that emoji.
+func (repository *ChatRepository) AddReaction(messageId int32, userId int32, emoji string) (bool, error) {
+    data, err := repository.connection.ExecuteReturning(
+        `
+        INSERT INTO chat_message_reaction (message_id, user_id, emoji)
+        VALUES ($1, $2, $3)
+        ON CONFLICT DO NOTHING
+        RETURNING message_id
+        `,
+        messageId,
+        userId,
+        emoji,
+    )
+    if err != nil {
+        return false, err
+    }
+    return len(data) > 0, nil
+}
+
+// RemoveReaction removes a reaction of a user. It returns false when there
+// was no such reaction.
+func (repository *ChatRepository) RemoveReaction(messageId int32, userId int32, emoji string) (bool, error) {
+    data, err := repository.connection.ExecuteReturning(
+        "DELETE FROM chat_message_reaction WHERE message_id = $1 AND user_id = $2 AND emoji = $3 RETURNING message_id",
+        messageId,
+        userId,
+        emoji,
+    )
+    if err != nil {
+        return false, err
+    }
+    return len(data) > 0, nil
+}
+
+// ListReactions returns the reactions to the given messages aggregated by
+// emoji, the first one used on each message first.
+func (repository *ChatRepository) ListReactions(messageIds []int32) ([]*model.ChatReaction, error) {
+    if len(messageIds) == 0 {
+        return []*model.ChatReaction{}, nil
+    }
+
+    data, err := repository.connection.ExecuteReturning(
+        `
+        SELECT r.message_id, r.emoji,
+            COUNT(*) AS count,
+            array_agg(u.username ORDER BY r.reacted_at, u.username) AS usernames
+        FROM chat_message_reaction r
+        JOIN users u ON u.id = r.user_id
+        WHERE r.message_id = ANY($1)
+        GROUP BY r.message_id, r.emoji
+        ORDER BY r.message_id, MIN(r.reacted_at), r.emoji
+        `,
+        messageIds,
+    )
+    if err != nil {
+        return nil, err
+    }
+
+    reactions := make([]*model.ChatReaction, 0, len(data))
+    for _, row := range data {
+        reactions = append(reactions, model.MapToChatReaction(row))
+    }
+
+    return reactions, nil
+}
+
+// ListMessagesFromChat returns a page of the visible messages of a chat
+// between the cursors of the query. One message more than the limit is read
+// so the page knows whether there are more.
+func (repository *ChatRepository) ListMessagesFromChat(chatId int32, query *model.ChatHistoryQuery) (*model.ChatHistoryPage, error) {
+    direction := "DESC"
+    if query.Forward() {
+        direction = "ASC"
+    }
+
+    messagesData, err := repository.connection.ExecuteReturning(
+        fmt.Sprintf(`
+        SELECT * FROM chat_message
+        WHERE chat_id = $1
+            AND NOT hidden
+            AND ($2::int = 0 OR message_id < $2)
+            AND ($3::int = 0 OR message_id > $3)
+        ORDER BY message_id %s
+        LIMIT $4
+        `, direction),
+        chatId,
+        query.Before,
+        query.After,
+        query.Limit+1,
+    )
+
+    if err != nil {
+        return nil, err
+    }
+
+    messages := make([]*model.ChatMessage, 0, len(messagesData))
+    for _, messageData := range messagesData {
+        messages = append(messages, model.MapToChatMessage(messageData))
+    }
+
+    return model.NewChatHistoryPage(messages, query), nil
+}
+
+// SearchMessages runs a full text search over the visible messages of the
+// chats the user participates in, or only over one of them when chatId is not
+// 0. The best matches come first.
+func (repository *ChatRepository) SearchMessages(userId int32, text string, chatId int32, limit int32) ([]*model.ChatMessageSearchResult, error) {
+    data, err := repository.connection.ExecuteReturning(
+        `
+        SELECT m.message_id, m.author_id, m.chat_id, m.message, m.sent_at,
+            m.attachment_type, m.attachment_id,
+            author.username AS author,
+            c.name AS chat_name,
+            c.is_group,
+            ts_headline('simple', m.message, q.query, 'MaxFragments=1, MinWords=5, MaxWords=20') AS headline,
+            ts_rank(to_tsvector('simple', immutable_unaccent(m.message)), q.query)::float8 AS rank
+        FROM chat_participants me
+        JOIN chat c ON c.chat_id = me.chat_id
+        JOIN chat_message m ON m.chat_id = me.chat_id
+        LEFT JOIN users author ON author.id = m.author_id
+        CROSS JOIN websearch_to_tsquery('simple', immutable_unaccent($2)) AS q(query)
+        WHERE me.user_id = $1
+            AND ($3::int = 0 OR me.chat_id = $3)
+            AND NOT m.hidden
+            AND to_tsvector('simple', immutable_unaccent(m.message)) @@ q.query
+        ORDER BY rank DESC, m.message_id DESC
+        LIMIT $4
+        `,
+        userId,
+        text,
         chatId,
         limit,
-        CHAT_MESSAGE_TABLE,
+    )
+    if err != nil {
+        return nil, err
+    }
+
+    results := make([]*model.ChatMessageSearchResult, 0, len(data))
+    for _, row := range data {
+        results = append(results, model.MapToChatMessageSearchResult(row))
+    }
+
+    return results, nil
+}
+
+// ListMessagesByAuthor returns every message written by a user, oldest first.
+func (repository *ChatRepository) ListMessagesByAuthor(authorId int32) ([]*model.ChatMessage, error) {
+    messagesData, err := repository.connection.ExecuteReturning(
+        "SELECT * FROM chat_message WHERE author_id = $1 ORDER BY sent_at, message_id",
+        authorId,
     )
 
     if err != nil {
         return nil, err
     }
 
-    var messages []*model.ChatMessage
+    messages := make([]*model.ChatMessage, 0, len(messagesData))
     for _, messageData := range messagesData {
         messages = append(messages, model.MapToChatMessage(messageData))
     }
 
     return messages, nil
-}
\ No newline at end of file
+}
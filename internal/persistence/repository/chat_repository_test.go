@@ -167,44 +167,75 @@ func TestAddMessageToChatAndReturn_GetFailure(t *testing.T) {
     mockConn.On("PutReturningId", data, CHAT_MESSAGE_TABLE, "message_id").Return(fakeMsgId, nil)
     mockConn.On("Get", constraint, CHAT_MESSAGE_TABLE).Return([]map[string]any{}, nil)
 
-    msg, err := repo.AddMessageToChatAndReturn(chatId, authorId, message)
+    msg, err := repo.AddMessageToChatAndReturn(chatId, authorId, message, nil)
     assert.Error(t, err)
     assert.Nil(t, msg)
     mockConn.AssertExpectations(t)
 }
 
+func TestAddMessageToChatAndReturn_WithAttachment(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    attachment := &model.Attachment{Type: model.ATTACHMENT_SONG, Id: "665f1c2e8b3e4a0012345678"}
+    data := map[string]any{
+        "chat_id":         int32(3),
+        "author_id":       int32(2),
+        "message":         "Listen to this",
+        "attachment_type": model.ATTACHMENT_SONG,
+        "attachment_id":   "665f1c2e8b3e4a0012345678",
+    }
+    stored := map[string]any{
+        "message_id":      int32(9),
+        "chat_id":         int32(3),
+        "author_id":       int32(2),
+        "message":         "Listen to this",
+        "sent_at":         time.Now(),
+        "attachment_type": model.ATTACHMENT_SONG,
+        "attachment_id":   "665f1c2e8b3e4a0012345678",
+    }
+
+    mockConn.On("PutReturningId", data, CHAT_MESSAGE_TABLE, "message_id").Return(int32(9), nil)
+    mockConn.On("Get", map[string]any{"message_id": int32(9)}, CHAT_MESSAGE_TABLE).Return([]map[string]any{stored}, nil)
+
+    msg, err := repo.AddMessageToChatAndReturn(3, 2, "Listen to this", attachment)
+    assert.NoError(t, err)
+    assert.Equal(t, attachment, msg.Attachment)
+    mockConn.AssertExpectations(t)
+}
+
 func TestListMessagesFromChat_Success(t *testing.T) {
     mockConn := new(MockPostgreConnection)
     repo := NewChatRepository(mockConn)
 
     chatId := int32(3)
-    limit := int32(2)
     now := time.Now()
 
     dbResult := []map[string]any{
         {
-            "message_id": int32(1),
+            "message_id": int32(2),
             "author_id":  int32(2),
             "chat_id":    chatId,
-            "message":    "Hello",
+            "message":    "World",
             "sent_at":    now,
         },
         {
-            "message_id": int32(2),
+            "message_id": int32(1),
             "author_id":  int32(2),
             "chat_id":    chatId,
-            "message":    "World",
+            "message":    "Hello",
             "sent_at":    now.Add(-time.Minute),
         },
     }
 
-    mockConn.On("GetChatWithLimit", chatId, limit, CHAT_MESSAGE_TABLE).Return(dbResult, nil)
+    mockConn.On("ExecuteReturning", mock.Anything, []any{chatId, int32(0), int32(0), int32(3)}).Return(dbResult, nil)
 
-    messages, err := repo.ListMessagesFromChat(chatId, limit)
+    page, err := repo.ListMessagesFromChat(chatId, &model.ChatHistoryQuery{Limit: 2, Order: model.MESSAGE_ORDER_OLDEST})
     assert.NoError(t, err)
-    assert.Len(t, messages, 2)
-    assert.Equal(t, "Hello", messages[0].Message)
-    assert.Equal(t, "World", messages[1].Message)
+    assert.False(t, page.HasMore)
+    assert.Len(t, page.Messages, 2)
+    assert.Equal(t, "Hello", page.Messages[0].Message)
+    assert.Equal(t, "World", page.Messages[1].Message)
     mockConn.AssertExpectations(t)
 }
 
@@ -213,11 +244,266 @@ func TestListMessagesFromChat_Failure(t *testing.T) {
     repo := NewChatRepository(mockConn)
 
     chatId := int32(3)
-    limit := int32(2)
 
-    mockConn.On("GetChatWithLimit", chatId, limit, CHAT_MESSAGE_TABLE).Return([]map[string]any{}, errors.New("db error"))
-    messages, err := repo.ListMessagesFromChat(chatId, limit)
+    mockConn.On("ExecuteReturning", mock.Anything, []any{chatId, int32(10), int32(0), int32(3)}).Return([]map[string]any{}, errors.New("db error"))
+    page, err := repo.ListMessagesFromChat(chatId, &model.ChatHistoryQuery{Before: 10, Limit: 2, Order: model.MESSAGE_ORDER_NEWEST})
+    assert.Error(t, err)
+    assert.Nil(t, page)
+    mockConn.AssertExpectations(t)
+}
+
+func TestSearchMessages_Success(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(2), "concert", int32(0), int32(20)}).Return(
+        []map[string]any{
+            {
+                "message_id": int32(7),
+                "author_id":  int32(4),
+                "chat_id":    int32(3),
+                "message":    "See you at the concert",
+                "sent_at":    time.Now(),
+                "author":     "mary",
+                "chat_name":  nil,
+                "is_group":   false,
+                "headline":   "See you at the <b>concert</b>",
+                "rank":       0.06,
+            },
+        },
+        nil,
+    )
+
+    results, err := repo.SearchMessages(2, "concert", 0, 20)
+    assert.NoError(t, err)
+    assert.Len(t, results, 1)
+    assert.Equal(t, int32(7), results[0].Message.MessageId)
+    assert.Equal(t, "mary", results[0].Author)
+    assert.Equal(t, "See you at the <b>concert</b>", results[0].Headline)
+    mockConn.AssertExpectations(t)
+}
+
+func TestAddMember_Success(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    expectedMap := map[string]any{
+        "chat_id": int32(1),
+        "user_id": int32(2),
+        "role": model.CHAT_ROLE_ADMIN,
+    }
+
+    mockConn.On("Put", expectedMap, USER_TO_CHAT_TABLE).Return(nil)
+
+    err := repo.AddMember(1, 2, model.CHAT_ROLE_ADMIN)
+    assert.NoError(t, err)
+    mockConn.AssertExpectations(t)
+}
+
+func TestCreateGroup_Success(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    createdAt := time.Now()
+    mockConn.On(
+        "ExecuteReturning",
+        mock.Anything,
+        []any{"Band", int32(1), []int32{2, 3}, model.CHAT_ROLE_ADMIN, model.CHAT_ROLE_MEMBER},
+    ).Return([]map[string]any{
+        {"chat_id": int32(7), "name": "Band", "is_group": true, "created_at": createdAt},
+    }, nil)
+
+    chat, err := repo.CreateGroup("Band", 1, []int32{2, 3})
+    assert.NoError(t, err)
+    assert.Equal(t, int32(7), chat.ChatId)
+    assert.Equal(t, "Band", chat.Name)
+    assert.True(t, chat.IsGroup)
+    mockConn.AssertExpectations(t)
+}
+
+func TestCreateGroup_Failure(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    mockConn.On("ExecuteReturning", mock.Anything, mock.Anything).Return([]map[string]any(nil), errors.New("insert failed"))
+
+    chat, err := repo.CreateGroup("Band", 1, []int32{2})
     assert.Error(t, err)
-    assert.Nil(t, messages)
+    assert.Nil(t, chat)
+    mockConn.AssertExpectations(t)
+}
+
+func TestRemoveMember_NotAMember(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), int32(2)}).Return([]map[string]any{}, nil)
+
+    removed, err := repo.RemoveMember(1, 2)
+    assert.NoError(t, err)
+    assert.False(t, removed)
+    mockConn.AssertExpectations(t)
+}
+
+func TestListMembers_Success(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    joinedAt := time.Now()
+    dbResult := []map[string]any{
+        {
+            "id":   int32(2),
+            "username":  "user1",
+            "fullname": "fulano da silva",
+            "email": "email",
+            "register_date": time.Now(),
+            "birth_date": time.Now(),
+            "telephone": "telephone",
+            "role": model.CHAT_ROLE_ADMIN,
+            "joined_at": joinedAt,
+        },
+    }
+
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1)}).Return(dbResult, nil)
+
+    members, err := repo.ListMembers(1)
+    assert.NoError(t, err)
+    assert.Len(t, members, 1)
+    assert.Equal(t, "user1", members[0].User.Username)
+    assert.True(t, members[0].IsAdmin())
+    assert.Equal(t, joinedAt, members[0].JoinedAt)
+    mockConn.AssertExpectations(t)
+}
+
+func TestFindChatByUsers_NoDirectChat(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), int32(2)}).Return([]map[string]any{}, nil)
+
+    chat, err := repo.FindChatByUsers(1, 2)
+    assert.NoError(t, err)
+    assert.Nil(t, chat)
+    mockConn.AssertExpectations(t)
+}
+
+func TestMarkRead_Success(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), int32(2), int32(0)}).Return(
+        []map[string]any{{"last_read_message_id": int32(42)}},
+        nil,
+    )
+
+    lastRead, err := repo.MarkRead(1, 2, 0)
+    assert.NoError(t, err)
+    assert.Equal(t, int32(42), lastRead)
+    mockConn.AssertExpectations(t)
+}
+
+func TestCountUnreadByUser_Success(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(2)}).Return(
+        []map[string]any{
+            {"chat_id": int32(1), "unread_count": int64(3)},
+            {"chat_id": int32(5), "unread_count": int64(0)},
+        },
+        nil,
+    )
+
+    counts, err := repo.CountUnreadByUser(2)
+    assert.NoError(t, err)
+    assert.Equal(t, map[int32]int64{1: 3, 5: 0}, counts)
+    mockConn.AssertExpectations(t)
+}
+
+func TestGetInbox_Success(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    createdAt := time.Now()
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(2), int32(20), int32(0)}).Return(
+        []map[string]any{
+            {
+                "chat_id": int32(1),
+                "is_group": false,
+                "created_at": createdAt,
+                "last_activity": createdAt,
+                "unread_count": int64(0),
+            },
+        },
+        nil,
+    )
+
+    entries, err := repo.GetInbox(2, 20, 0)
+    assert.NoError(t, err)
+    assert.Len(t, entries, 1)
+    assert.Equal(t, int32(1), entries[0].Chat.ChatId)
+    assert.Nil(t, entries[0].LastMessage)
+    mockConn.AssertExpectations(t)
+}
+
+func TestEditMessage_Success(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    editedAt := time.Now()
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(7), "Hello again"}).Return(
+        []map[string]any{
+            {
+                "message_id": int32(7),
+                "author_id":  int32(2),
+                "chat_id":    int32(3),
+                "message":    "Hello again",
+                "sent_at":    editedAt.Add(-time.Minute),
+                "edited_at":  editedAt,
+                "deleted_at": nil,
+            },
+        },
+        nil,
+    )
+
+    message, err := repo.EditMessage(7, "Hello again")
+    assert.NoError(t, err)
+    assert.Equal(t, "Hello again", message.Message)
+    assert.True(t, message.IsEdited())
+    assert.False(t, message.IsDeleted())
+    mockConn.AssertExpectations(t)
+}
+
+func TestDeleteMessage_AlreadyDeleted(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(7)}).Return([]map[string]any{}, nil)
+
+    message, err := repo.DeleteMessage(7)
+    assert.NoError(t, err)
+    assert.Nil(t, message)
     mockConn.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestListReactions_NoMessages(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    reactions, err := repo.ListReactions(nil)
+    assert.NoError(t, err)
+    assert.Empty(t, reactions)
+    mockConn.AssertNotCalled(t, "ExecuteReturning", mock.Anything, mock.Anything)
+}
+
+func TestAddReaction_AlreadyReacted(t *testing.T) {
+    mockConn := new(MockPostgreConnection)
+    repo := NewChatRepository(mockConn)
+
+    mockConn.On("ExecuteReturning", mock.Anything, []any{int32(7), int32(2), "🔥"}).Return([]map[string]any{}, nil)
+
+    added, err := repo.AddReaction(7, 2, "🔥")
+    assert.NoError(t, err)
+    assert.False(t, added)
+    mockConn.AssertExpectations(t)
+}
@@ -64,3 +64,105 @@ func (repository *CommunityRepository) ListUsersFromCommunity(community *model.C
 
 	return model.MapArrayToUsers(users), nil
 }
+
+// ListMembersAmong returns the members of the community whose username is one
+// of the given ones.
+func (repository *CommunityRepository) ListMembersAmong(community *model.Community, usernames []string) ([]*model.User, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT u.* FROM users u
+		JOIN user_community uc ON u.id = uc.user_id
+		WHERE uc.community_id = $1 AND u.username = ANY($2)
+		`,
+		community.Id,
+		usernames,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model.MapArrayToUsers(data), nil
+}
+
+// CountSharedCommunities returns, for each of the given usernames, how many
+// communities they share with the user. Users sharing none are not in the map.
+func (repository *CommunityRepository) CountSharedCommunities(userId int32, usernames []string) (map[string]int64, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT u.username, COUNT(*) AS shared_communities
+		FROM user_community mine
+		JOIN user_community theirs ON theirs.community_id = mine.community_id
+		JOIN users u ON u.id = theirs.user_id
+		WHERE mine.user_id = $1 AND u.username = ANY($2)
+		GROUP BY u.username
+		`,
+		userId,
+		usernames,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64)
+	for _, row := range data {
+		username, _ := row["username"].(string)
+		count, _ := row["shared_communities"].(int64)
+		counts[username] = count
+	}
+
+	return counts, nil
+}
+
+// ListCommunityPeers returns the usernames of the users sharing a community
+// with the user, the ones sharing more first. At most limit are returned.
+func (repository *CommunityRepository) ListCommunityPeers(userId int32, limit int32) ([]string, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT u.username, COUNT(*) AS shared_communities
+		FROM user_community mine
+		JOIN user_community theirs ON theirs.community_id = mine.community_id
+		JOIN users u ON u.id = theirs.user_id
+		WHERE mine.user_id = $1 AND theirs.user_id <> $1
+		GROUP BY u.username
+		ORDER BY shared_communities DESC, u.username
+		LIMIT $2
+		`,
+		userId,
+		limit,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	usernames := make([]string, 0, len(data))
+	for _, row := range data {
+		username, _ := row["username"].(string)
+		usernames = append(usernames, username)
+	}
+
+	return usernames, nil
+}
+
+// ListSharedCommunities returns the communities both users belong to.
+func (repository *CommunityRepository) ListSharedCommunities(userId1 int32, userId2 int32) ([]*model.Community, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT c.* FROM community c
+		JOIN user_community uc1 ON uc1.community_id = c.id
+		JOIN user_community uc2 ON uc2.community_id = c.id
+		WHERE uc1.user_id = $1 AND uc2.user_id = $2
+		ORDER BY c.community_name
+		`,
+		userId1,
+		userId2,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model.MapArrayToCommunity(data), nil
+}
@@ -88,3 +88,90 @@ func TestPut_Failure(t *testing.T) {
 	assert.Error(t, err)
 	mockConn.AssertExpectations(t)
 }
+
+func TestCountSharedCommunities(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewCommunityRepository(mockConn)
+
+	usernames := []string{"mary", "bob"}
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), usernames}).Return(
+		[]map[string]any{
+			{"username": "mary", "shared_communities": int64(2)},
+		},
+		nil,
+	)
+
+	counts, err := repo.CountSharedCommunities(1, usernames)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"mary": 2}, counts)
+	assert.Equal(t, int64(0), counts["bob"])
+	mockConn.AssertExpectations(t)
+}
+
+func TestListCommunityPeers(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewCommunityRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), int32(50)}).Return(
+		[]map[string]any{
+			{"username": "mary", "shared_communities": int64(2)},
+			{"username": "bob", "shared_communities": int64(1)},
+		},
+		nil,
+	)
+
+	usernames, err := repo.ListCommunityPeers(1, 50)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mary", "bob"}, usernames)
+	mockConn.AssertExpectations(t)
+}
+
+func TestListSharedCommunities(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewCommunityRepository(mockConn)
+
+	createdAt := time.Now()
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), int32(2)}).Return(
+		[]map[string]any{
+			{"id": int32(3), "community_name": "jazz", "description": "Jazz fans", "created_at": createdAt},
+		},
+		nil,
+	)
+
+	communities, err := repo.ListSharedCommunities(1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.Community{
+		{Id: 3, CommunityName: "jazz", Description: "Jazz fans", CreatedAt: createdAt},
+	}, communities)
+	mockConn.AssertExpectations(t)
+}
+
+func TestCommunityRepository_ListMembersAmong(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewCommunityRepository(mockConn)
+
+	community := &model.Community{Id: 3, CommunityName: "TestCommunity"}
+	usernames := []string{"ana", "bob"}
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(3), usernames}).Return([]map[string]any{
+		{
+			"id":            int32(1),
+			"username":      "ana",
+			"fullname":      "Ana",
+			"email":         "ana@example.com",
+			"register_date": time.Now(),
+			"birth_date":    time.Now(),
+			"telephone":     "",
+		},
+	}, nil)
+
+	members, err := repo.ListMembersAmong(community, usernames)
+
+	assert.NoError(t, err)
+	assert.Len(t, members, 1)
+	assert.Equal(t, "ana", members[0].Username)
+	mockConn.AssertExpectations(t)
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/model"
+)
+
+const DATA_EXPORT_TABLE = "data_export"
+
+type DataExportRepository struct {
+	connection postgres.PostgreConnection
+}
+
+func NewDataExportRepository(connection postgres.PostgreConnection) *DataExportRepository {
+	return &DataExportRepository{
+		connection: connection,
+	}
+}
+
+// Put creates a pending export for the user and returns its id.
+func (repository *DataExportRepository) Put(userId int32) (int32, error) {
+	id, err := repository.connection.PutReturningId(
+		map[string]any{
+			"user_id": userId,
+			"status":  model.EXPORT_PENDING,
+		},
+		DATA_EXPORT_TABLE,
+		"id",
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return id.(int32), nil
+}
+
+func (repository *DataExportRepository) GetById(exportId int32) (*model.DataExport, error) {
+	data, err := repository.connection.Get(
+		map[string]any{
+			"id": exportId,
+		},
+		DATA_EXPORT_TABLE,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("export not found")
+	}
+
+	return model.MapToDataExport(data[0]), nil
+}
+
+func (repository *DataExportRepository) ListByUserId(userId int32) ([]*model.DataExport, error) {
+	data, err := repository.connection.Get(
+		map[string]any{
+			"user_id": userId,
+		},
+		DATA_EXPORT_TABLE,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]*model.DataExport, 0, len(data))
+	for _, export := range data {
+		exports = append(exports, model.MapToDataExport(export))
+	}
+	return exports, nil
+}
+
+// Finish records the outcome of an export. The storage key is only set when
+// the export succeeded.
+func (repository *DataExportRepository) Finish(exportId int32, status string, storageKey string) (bool, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		UPDATE data_export
+		SET status = $1, storage_key = NULLIF($2, ''), completed_at = now()
+		WHERE id = $3
+		RETURNING id
+		`,
+		status,
+		storageKey,
+		exportId,
+	)
+	if err != nil {
+		return false, err
+	}
+	return len(data) > 0, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"symphony-api/internal/persistence/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDataExportRepository_Put(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewDataExportRepository(mockConn)
+
+	mockConn.On("PutReturningId", map[string]any{
+		"user_id": int32(1),
+		"status":  model.EXPORT_PENDING,
+	}, DATA_EXPORT_TABLE, "id").Return(int32(5), nil)
+
+	exportId, err := repo.Put(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), exportId)
+	mockConn.AssertExpectations(t)
+}
+
+func TestDataExportRepository_GetById(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewDataExportRepository(mockConn)
+
+	requestedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	completedAt := requestedAt.Add(time.Minute)
+
+	mockConn.On("Get", map[string]any{"id": int32(5)}, DATA_EXPORT_TABLE).Return([]map[string]any{
+		{
+			"id":           int32(5),
+			"user_id":      int32(1),
+			"status":       model.EXPORT_DONE,
+			"storage_key":  "export-5-abc.zip",
+			"requested_at": requestedAt,
+			"completed_at": completedAt,
+		},
+	}, nil)
+
+	export, err := repo.GetById(5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.DataExport{
+		ExportId:    5,
+		UserId:      1,
+		Status:      model.EXPORT_DONE,
+		StorageKey:  "export-5-abc.zip",
+		RequestedAt: requestedAt,
+		CompletedAt: &completedAt,
+	}, export)
+	mockConn.AssertExpectations(t)
+}
+
+func TestDataExportRepository_Finish(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewDataExportRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{model.EXPORT_FAILED, "", int32(5)}).Return([]map[string]any{{"id": int32(5)}}, nil)
+
+	found, err := repo.Finish(5, model.EXPORT_FAILED, "")
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	mockConn.AssertExpectations(t)
+}
+
+func TestDataExportRepository_Finish_Deleted(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewDataExportRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{model.EXPORT_DONE, "export-5.zip", int32(5)}).Return([]map[string]any{}, nil)
+
+	found, err := repo.Finish(5, model.EXPORT_DONE, "export-5.zip")
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	mockConn.AssertExpectations(t)
+}
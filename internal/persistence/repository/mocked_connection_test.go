@@ -24,9 +24,14 @@ func (m *MockPostgreConnection) Get(constraints map[string]any, table string) ([
     return args.Get(0).([]map[string]any), args.Error(1)
 }
 
-func (m *MockPostgreConnection) GetChatWithLimit(chat_id int32, limit int32, tableName string) ([]map[string]any, error) {
-    args := m.Called(chat_id, limit, tableName)
-    return args.Get(0).([]map[string]any), args.Error(1)
+func (m *MockPostgreConnection) Execute(query string, args ...any) error {
+    called := m.Called(query, args)
+    return called.Error(0)
+}
+
+func (m *MockPostgreConnection) ExecuteReturning(query string, args ...any) ([]map[string]any, error) {
+    called := m.Called(query, args)
+    return called.Get(0).([]map[string]any), called.Error(1)
 }
 
 type MockNeo4jConn struct {
@@ -43,3 +43,16 @@ func (r *ArtistRepository) GetArtistBySpotifyID(ctx context.Context, idSpotify s
 	}
 	return &artist, nil
 }
+
+// GetArtistsByIDs busca todos os artistas cujo ID está em ids em uma única consulta.
+func (r *ArtistRepository) GetArtistsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Artist, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	var artists []model.Artist
+	if err := cursor.All(ctx, &artists); err != nil {
+		return nil, err
+	}
+	return artists, nil
+}
@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type PlaylistRepository struct {
@@ -64,3 +65,73 @@ func (r *PlaylistRepository) UpdatePlaylist(ctx context.Context, id primitive.Ob
 	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": id}, playlist)
 	return err
 }
+
+// GetPlaylistsByIDs fetches all playlists whose ID is in ids with a single query.
+func (r *PlaylistRepository) GetPlaylistsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Playlist, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	var playlists []model.Playlist
+	if err := cursor.All(ctx, &playlists); err != nil {
+		return nil, err
+	}
+	return playlists, nil
+}
+
+// GetPlaylistsByUsernames fetches the playlists of all the given users with a
+// single query.
+func (r *PlaylistRepository) GetPlaylistsByUsernames(ctx context.Context, usernames []string) ([]model.Playlist, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"username": bson.M{"$in": usernames}})
+	if err != nil {
+		return nil, err
+	}
+	var playlists []model.Playlist
+	if err := cursor.All(ctx, &playlists); err != nil {
+		return nil, err
+	}
+	return playlists, nil
+}
+
+// ListUsernamesWithSongs returns the owners of the playlists holding any of
+// the songs, other than the given user. At most limit playlists are read.
+func (r *PlaylistRepository) ListUsernamesWithSongs(ctx context.Context, songIds []primitive.ObjectID, username string, limit int64) ([]string, error) {
+	cursor, err := r.collection.Find(
+		ctx,
+		bson.M{"songs.song_id": bson.M{"$in": songIds}, "username": bson.M{"$ne": username}},
+		options.Find().SetProjection(bson.M{"username": 1}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var playlists []model.Playlist
+	if err := cursor.All(ctx, &playlists); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	usernames := make([]string, 0)
+	for _, playlist := range playlists {
+		if !seen[playlist.Username] {
+			seen[playlist.Username] = true
+			usernames = append(usernames, playlist.Username)
+		}
+	}
+	return usernames, nil
+}
+
+// RenameUsername moves every playlist of a user to a new username.
+func (r *PlaylistRepository) RenameUsername(ctx context.Context, oldUsername string, newUsername string) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"username": oldUsername},
+		bson.M{"$set": bson.M{"username": newUsername}},
+	)
+	return err
+}
+
+// DeleteByUsername removes every playlist of a user.
+func (r *PlaylistRepository) DeleteByUsername(ctx context.Context, username string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"username": username})
+	return err
+}
@@ -57,3 +57,17 @@ func (r *SongRepository) GetAllSongs(ctx context.Context) ([]model.Song, error)
 	}
 	return songs, nil
 }
+
+// GetSongsByIDs fetches all songs whose ID is in ids with a single query.
+// Songs that do not exist are simply absent from the result.
+func (r *SongRepository) GetSongsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Song, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	var songs []model.Song
+	if err := cursor.All(ctx, &songs); err != nil {
+		return nil, err
+	}
+	return songs, nil
+}
@@ -0,0 +1,122 @@
+package mongo_repository
+
+import (
+	"context"
+	"log"
+	local_mongo "symphony-api/internal/persistence/connectors/mongo"
+	"symphony-api/internal/persistence/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type StoryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewStoryRepository also creates the indexes of the collection. The TTL index
+// on expires_at makes Mongo delete each story once it expires.
+func NewStoryRepository(conn *local_mongo.MongoConnection) *StoryRepository {
+	coll := conn.GetCollection("symphony", "stories")
+
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys: bson.D{{Key: "username", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to create story indexes: %v", err)
+	}
+
+	return &StoryRepository{collection: coll}
+}
+
+func (r *StoryRepository) InsertStory(ctx context.Context, story model.Story) (*mongo.InsertOneResult, error) {
+	return r.collection.InsertOne(ctx, story)
+}
+
+// GetActiveStoryByID returns the story if it exists and has not expired yet.
+func (r *StoryRepository) GetActiveStoryByID(ctx context.Context, id primitive.ObjectID) (*model.Story, error) {
+	var story model.Story
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&story)
+	if err != nil {
+		return nil, err
+	}
+	return &story, nil
+}
+
+// GetActiveStoriesByUsernames returns the stories that have not expired of all
+// given users, oldest first.
+func (r *StoryRepository) GetActiveStoriesByUsernames(ctx context.Context, usernames []string) ([]model.Story, error) {
+	filter := bson.M{
+		"username":   bson.M{"$in": usernames},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	stories := make([]model.Story, 0)
+	if err := cursor.All(ctx, &stories); err != nil {
+		return nil, err
+	}
+	return stories, nil
+}
+
+// AddView records that a user has seen a story. Seeing the same story again
+// does not add a new view.
+func (r *StoryRepository) AddView(ctx context.Context, id primitive.ObjectID, username string) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "views.username": bson.M{"$ne": username}},
+		bson.M{"$push": bson.M{"views": model.StoryView{Username: username, ViewedAt: time.Now()}}},
+	)
+	return err
+}
+
+// RenameUsername moves the stories and the story views of a user to a new
+// username.
+func (r *StoryRepository) RenameUsername(ctx context.Context, oldUsername string, newUsername string) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"username": oldUsername},
+		bson.M{"$set": bson.M{"username": newUsername}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateMany(
+		ctx,
+		bson.M{"views.username": oldUsername},
+		bson.M{"$set": bson.M{"views.$[view].username": newUsername}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []any{bson.M{"view.username": oldUsername}},
+		}),
+	)
+	return err
+}
+
+// DeleteByUsername removes the stories of a user and their views of the
+// stories of others.
+func (r *StoryRepository) DeleteByUsername(ctx context.Context, username string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"username": username})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateMany(
+		ctx,
+		bson.M{"views.username": username},
+		bson.M{"$pull": bson.M{"views": bson.M{"username": username}}},
+	)
+	return err
+}
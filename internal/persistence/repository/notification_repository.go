@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/model"
+)
+
+type NotificationRepository struct {
+	connection postgres.PostgreConnection
+}
+
+func NewNotificationRepository(connection postgres.PostgreConnection) *NotificationRepository {
+	return &NotificationRepository{
+		connection: connection,
+	}
+}
+
+// Notify records that the actor did something the recipients should know
+// about. Recipients with an unread notification of the same type and target
+// get the actor added to it; the others get a new one. The actor and the
+// recipients that muted the type are skipped.
+func (repository *NotificationRepository) Notify(recipientIds []int32, notificationType string, actorId int32, targetId int32) error {
+	if len(recipientIds) == 0 {
+		return nil
+	}
+
+	return repository.connection.Execute(
+		`
+		WITH recipient AS (
+			SELECT DISTINCT r.user_id
+			FROM unnest($1::int[]) AS r(user_id)
+			WHERE r.user_id <> $3
+				AND NOT EXISTS (
+					SELECT 1 FROM notification_preference p
+					WHERE p.user_id = r.user_id AND p.type = $2 AND p.muted
+				)
+		), notified AS (
+			INSERT INTO notification (user_id, type, target_id)
+			SELECT user_id, $2, $4 FROM recipient
+			ON CONFLICT (user_id, type, target_id) WHERE read_at IS NULL
+			DO UPDATE SET updated_at = now()
+			RETURNING id
+		)
+		INSERT INTO notification_actor (notification_id, actor_id)
+		SELECT id, $3 FROM notified
+		ON CONFLICT (notification_id, actor_id) DO UPDATE SET acted_at = now()
+		`,
+		recipientIds,
+		notificationType,
+		actorId,
+		targetId,
+	)
+}
+
+// List returns a page of the notifications of the user, most recently updated
+// first, with their most recent actors and the name of their target.
+func (repository *NotificationRepository) List(userId int32, limit int32, offset int32, unreadOnly bool) ([]*model.Notification, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT n.id, n.user_id, n.type, n.target_id, n.created_at, n.updated_at, n.read_at,
+			(
+				SELECT COUNT(*) FROM notification_actor a WHERE a.notification_id = n.id
+			) AS actor_count,
+			ARRAY(
+				SELECT u.username
+				FROM notification_actor a
+				JOIN users u ON u.id = a.actor_id
+				WHERE a.notification_id = n.id
+				ORDER BY a.acted_at DESC
+				LIMIT $4
+			) AS actors,
+			CASE n.type
+				WHEN 'community_joined' THEN (SELECT community_name FROM community WHERE id = n.target_id)
+				WHEN 'chat_message' THEN (SELECT name FROM chat WHERE chat_id = n.target_id)
+			END AS target_name
+		FROM notification n
+		WHERE n.user_id = $1 AND (NOT $5 OR n.read_at IS NULL)
+		ORDER BY n.updated_at DESC, n.id DESC
+		LIMIT $2 OFFSET $3
+		`,
+		userId,
+		limit,
+		offset,
+		model.MAX_NOTIFICATION_ACTORS,
+		unreadOnly,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := make([]*model.Notification, 0, len(data))
+	for _, row := range data {
+		notifications = append(notifications, model.MapToNotification(row))
+	}
+
+	return notifications, nil
+}
+
+func (repository *NotificationRepository) CountUnread(userId int32) (int64, error) {
+	data, err := repository.connection.ExecuteReturning(
+		"SELECT COUNT(*) AS unread_count FROM notification WHERE user_id = $1 AND read_at IS NULL",
+		userId,
+	)
+	if err != nil || len(data) == 0 {
+		return 0, err
+	}
+
+	count, _ := data[0]["unread_count"].(int64)
+	return count, nil
+}
+
+// MarkRead marks a notification of the user as read. It returns false when
+// the user has no such notification.
+func (repository *NotificationRepository) MarkRead(userId int32, notificationId int32) (bool, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		UPDATE notification SET read_at = COALESCE(read_at, now())
+		WHERE id = $1 AND user_id = $2
+		RETURNING id
+		`,
+		notificationId,
+		userId,
+	)
+	if err != nil {
+		return false, err
+	}
+	return len(data) > 0, nil
+}
+
+func (repository *NotificationRepository) MarkAllRead(userId int32) error {
+	return repository.connection.Execute(
+		"UPDATE notification SET read_at = now() WHERE user_id = $1 AND read_at IS NULL",
+		userId,
+	)
+}
+
+// Delete removes a notification of the user. It returns false when the user
+// has no such notification.
+func (repository *NotificationRepository) Delete(userId int32, notificationId int32) (bool, error) {
+	data, err := repository.connection.ExecuteReturning(
+		"DELETE FROM notification WHERE id = $1 AND user_id = $2 RETURNING id",
+		notificationId,
+		userId,
+	)
+	if err != nil {
+		return false, err
+	}
+	return len(data) > 0, nil
+}
+
+// ListMutedTypes returns the types of notification the user muted.
+func (repository *NotificationRepository) ListMutedTypes(userId int32) ([]string, error) {
+	data, err := repository.connection.ExecuteReturning(
+		"SELECT type FROM notification_preference WHERE user_id = $1 AND muted ORDER BY type",
+		userId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]string, 0, len(data))
+	for _, row := range data {
+		types = append(types, row["type"].(string))
+	}
+
+	return types, nil
+}
+
+func (repository *NotificationRepository) SetMuted(userId int32, notificationType string, muted bool) error {
+	return repository.connection.Execute(
+		`
+		INSERT INTO notification_preference (user_id, type, muted)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, type) DO UPDATE SET muted = EXCLUDED.muted
+		`,
+		userId,
+		notificationType,
+		muted,
+	)
+}
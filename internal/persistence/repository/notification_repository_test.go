@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"symphony-api/internal/persistence/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotificationRepository_Notify(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewNotificationRepository(mockConn)
+
+	mockConn.On("Execute", mock.Anything, []any{[]int32{2, 3}, model.NOTIFICATION_CHAT_MESSAGE, int32(1), int32(7)}).Return(nil)
+
+	err := repo.Notify([]int32{2, 3}, model.NOTIFICATION_CHAT_MESSAGE, 1, 7)
+
+	assert.NoError(t, err)
+	mockConn.AssertExpectations(t)
+}
+
+func TestNotificationRepository_Notify_NoRecipients(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewNotificationRepository(mockConn)
+
+	err := repo.Notify(nil, model.NOTIFICATION_CHAT_MESSAGE, 1, 7)
+
+	assert.NoError(t, err)
+	mockConn.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything)
+}
+
+func TestNotificationRepository_List(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewNotificationRepository(mockConn)
+
+	now := time.Now()
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(2), int32(20), int32(0), model.MAX_NOTIFICATION_ACTORS, true}).Return([]map[string]any{
+		{
+			"id":          int32(4),
+			"user_id":     int32(2),
+			"type":        model.NOTIFICATION_POST_MENTION,
+			"target_id":   int32(9),
+			"created_at":  now,
+			"updated_at":  now,
+			"read_at":     nil,
+			"actor_count": int64(5),
+			"actors":      []any{"ana", "bob", "carl"},
+			"target_name": nil,
+		},
+	}, nil)
+
+	notifications, err := repo.List(2, 20, 0, true)
+
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 1)
+	assert.Equal(t, "ana and 4 others mentioned you in a post", notifications[0].Summary())
+	mockConn.AssertExpectations(t)
+}
+
+func TestNotificationRepository_MarkRead_NotFound(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewNotificationRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(4), int32(2)}).Return([]map[string]any{}, nil)
+
+	found, err := repo.MarkRead(2, 4)
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	mockConn.AssertExpectations(t)
+}
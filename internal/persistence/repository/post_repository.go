@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"errors"
 	"symphony-api/internal/persistence/connectors/postgres"
 	"symphony-api/internal/persistence/model"
+	"time"
 )
 
 const POST_TABLE = "post"
 const POST_ID = "id"
+const POST_HASHTAG_TABLE = "post_hashtag"
+const POST_MENTION_TABLE = "post_mention"
 
 type PostRepository struct {
 	connection postgres.PostgreConnection
@@ -20,18 +24,35 @@ func NewPostRepository(connection postgres.PostgreConnection) *PostRepository {
 
 func (repository *PostRepository) Put(post *model.Post) (*model.Post, error) {
 	id, err := repository.connection.PutReturningId(post.ToMap(), POST_TABLE, POST_ID)
-	return model.NewPost(
+	if err != nil {
+		return nil, err
+	}
+
+	createdPost := model.NewPost(
 		id.(int32),
 		post.UserId,
 		post.Text,
 		post.UrlFoto,
 		post.LikeCount,
-	), err
+	)
+	createdPost.Attachment = post.Attachment
+
+	return createdPost, nil
 }
 
 func (repository *PostRepository) get(constraint map[string]any) ([]*model.Post, error) {
 	data, err := repository.connection.Get(constraint, POST_TABLE)
 
+	return mapToPosts(data, err)
+}
+
+func (repository *PostRepository) query(query string, args ...any) ([]*model.Post, error) {
+	data, err := repository.connection.ExecuteReturning(query, args...)
+
+	return mapToPosts(data, err)
+}
+
+func mapToPosts(data []map[string]any, err error) ([]*model.Post, error) {
 	if err != nil {
 		return nil, err
 	}
@@ -48,6 +69,7 @@ func (repository *PostRepository) get(constraint map[string]any) ([]*model.Post,
 func (repository *PostRepository) GetById(postId int32) (*model.Post, error) {
 	constraint := map[string]any{
 		"id": postId,
+		"hidden": false,
 	}
 
 	posts, err := repository.get(constraint)
@@ -60,7 +82,175 @@ func (repository *PostRepository) GetById(postId int32) (*model.Post, error) {
 func (repository *PostRepository) GetByUserId(userId int32) ([]*model.Post, error) {
 	constraint := map[string]any{
 		"user_id": userId,
+		"hidden": false,
 	}
 
 	return repository.get(constraint)
 }
+
+// ListAllByUserId returns every post of a user, hidden ones included, oldest
+// first.
+func (repository *PostRepository) ListAllByUserId(userId int32) ([]*model.Post, error) {
+	return repository.query(
+		"SELECT * FROM post WHERE user_id = $1 ORDER BY id",
+		userId,
+	)
+}
+
+func (repository *PostRepository) AddHashtags(postId int32, tags []string) error {
+	for _, tag := range tags {
+		err := repository.connection.Put(
+			map[string]any{
+				"post_id": postId,
+				"tag":     tag,
+			},
+			POST_HASHTAG_TABLE,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repository *PostRepository) AddMentions(postId int32, users []*model.User) error {
+	for _, user := range users {
+		err := repository.connection.Put(
+			map[string]any{
+				"post_id": postId,
+				"user_id": user.UserId,
+			},
+			POST_MENTION_TABLE,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Like records that the user liked the post and counts it. It returns false
+// when the user had already liked it.
+func (repository *PostRepository) Like(userId int32, postId int32) (bool, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		WITH liked AS (
+			INSERT INTO post_like (user_id, post_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+			RETURNING post_id
+		)
+		UPDATE post SET like_count = COALESCE(like_count, 0) + 1
+		WHERE id IN (SELECT post_id FROM liked)
+		RETURNING id
+		`,
+		userId,
+		postId,
+	)
+	if err != nil {
+		return false, err
+	}
+	return len(data) > 0, nil
+}
+
+// AddComment stores a comment of the user on the post and returns its id.
+func (repository *PostRepository) AddComment(userId int32, postId int32, text string) (int32, error) {
+	data, err := repository.connection.ExecuteReturning(
+		"INSERT INTO post_comment (user_id, post_id, text) VALUES ($1, $2, $3) RETURNING id_comment",
+		userId,
+		postId,
+		text,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, errors.New("could not create comment")
+	}
+
+	return data[0]["id_comment"].(int32), nil
+}
+
+func (repository *PostRepository) GetByHashtag(tag string) ([]*model.Post, error) {
+	return repository.query(
+		`
+		SELECT p.* FROM post p
+		JOIN post_hashtag ph ON p.id = ph.post_id
+		WHERE ph.tag = $1 AND NOT p.hidden
+		ORDER BY p.id DESC
+		`,
+		model.NormalizeHashtag(tag),
+	)
+}
+
+func (repository *PostRepository) GetByMentionedUserId(userId int32) ([]*model.Post, error) {
+	return repository.query(
+		`
+		SELECT p.* FROM post p
+		JOIN post_mention pm ON p.id = pm.post_id
+		WHERE pm.user_id = $1 AND NOT p.hidden
+		ORDER BY p.id DESC
+		`,
+		userId,
+	)
+}
+
+// ListTrendingHashtags returns the hashtags used by most posts since the given
+// time, most used first.
+func (repository *PostRepository) ListTrendingHashtags(since time.Time, limit int32) ([]*model.TrendingHashtag, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT tag, COUNT(*) AS post_count
+		FROM post_hashtag
+		WHERE tagged_at >= $1
+		GROUP BY tag
+		ORDER BY post_count DESC, tag
+		LIMIT $2
+		`,
+		since,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hashtags := make([]*model.TrendingHashtag, 0)
+	for _, hashtag := range data {
+		hashtags = append(hashtags, model.MapToTrendingHashtag(hashtag))
+	}
+	return hashtags, nil
+}
+
+// GetFeed returns the newest posts written by any of the given users or
+// attached to any of the given artists, skipping the posts of excluded users.
+// Only the posts of the viewer, of public profiles and of friends-only
+// profiles of the friends of the viewer are returned.
+func (repository *PostRepository) GetFeed(viewer string, friends []string, usernames []string, artistIds []string, excluded []string, limit int32) ([]*model.Post, error) {
+	return repository.query(
+		`
+		SELECT p.* FROM post p
+		JOIN users u ON p.user_id = u.id
+		WHERE NOT p.hidden
+			AND (
+				u.username = ANY($3)
+				OR (p.attachment_type = 'artist' AND p.attachment_id = ANY($4))
+			)
+			AND NOT u.username = ANY($5)
+			AND (
+				u.username = $1
+				OR u.profile_visibility = $7
+				OR (u.profile_visibility = $8 AND u.username = ANY($2))
+			)
+		ORDER BY p.id DESC
+		LIMIT $6
+		`,
+		viewer,
+		friends,
+		usernames,
+		artistIds,
+		excluded,
+		limit,
+		model.PROFILE_PUBLIC,
+		model.PROFILE_FRIENDS,
+	)
+}
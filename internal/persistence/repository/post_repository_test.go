@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"symphony-api/internal/persistence/model"
 
@@ -74,3 +76,109 @@ func TestPostRepository_GetByUserId(t *testing.T) {
 	assert.Equal(t, []*model.Post{post}, result)
 	mockConn.AssertExpectations(t)
 }
+
+func TestPostRepository_AddHashtags(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewPostRepository(mockConn)
+
+	mockConn.On("Put", map[string]any{"post_id": int32(1), "tag": "jazz"}, POST_HASHTAG_TABLE).Return(nil)
+	mockConn.On("Put", map[string]any{"post_id": int32(1), "tag": "rock"}, POST_HASHTAG_TABLE).Return(nil)
+
+	err := repo.AddHashtags(1, []string{"jazz", "rock"})
+
+	assert.NoError(t, err)
+	mockConn.AssertExpectations(t)
+}
+
+func TestPostRepository_AddMentions_Failure(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewPostRepository(mockConn)
+
+	mockConn.On("Put", map[string]any{"post_id": int32(1), "user_id": int32(2)}, POST_MENTION_TABLE).Return(errors.New("db error"))
+
+	err := repo.AddMentions(1, []*model.User{{UserId: 2}})
+
+	assert.Error(t, err)
+	mockConn.AssertExpectations(t)
+}
+
+func TestPostRepository_GetByHashtag(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewPostRepository(mockConn)
+	post, postMap := getPostTestData()
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{"jazz"}).Return([]map[string]any{postMap}, nil)
+
+	result, err := repo.GetByHashtag("#Jazz")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.Post{post}, result)
+	mockConn.AssertExpectations(t)
+}
+
+func TestPostRepository_GetFeed(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewPostRepository(mockConn)
+	post, postMap := getPostTestData()
+
+	friends := []string{"mary"}
+	usernames := []string{"john", "mary"}
+	artistIds := []string{"64b7f0c2a1b2c3d4e5f60718"}
+	excluded := []string{"spammer"}
+	mockConn.On("ExecuteReturning", mock.Anything, []any{"john", friends, usernames, artistIds, excluded, int32(20), model.PROFILE_PUBLIC, model.PROFILE_FRIENDS}).Return([]map[string]any{postMap}, nil)
+
+	result, err := repo.GetFeed("john", friends, usernames, artistIds, excluded, 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.Post{post}, result)
+	mockConn.AssertExpectations(t)
+}
+
+func TestPostRepository_ListTrendingHashtags(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewPostRepository(mockConn)
+	since := time.Now().Add(-24 * time.Hour)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{since, int32(2)}).Return([]map[string]any{
+		{"tag": "jazz", "post_count": int64(5)},
+		{"tag": "rock", "post_count": int64(3)},
+	}, nil)
+
+	result, err := repo.ListTrendingHashtags(since, 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "jazz", result[0].Tag)
+	assert.Equal(t, int64(5), result[0].PostCount)
+	mockConn.AssertExpectations(t)
+}
+
+func TestPostRepository_Like(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewPostRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(2), int32(1)}).Return([]map[string]any{{"id": int32(1)}}, nil).Once()
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(2), int32(1)}).Return([]map[string]any{}, nil).Once()
+
+	liked, err := repo.Like(2, 1)
+	assert.NoError(t, err)
+	assert.True(t, liked)
+
+	liked, err = repo.Like(2, 1)
+	assert.NoError(t, err)
+	assert.False(t, liked)
+	mockConn.AssertExpectations(t)
+}
+
+func TestPostRepository_AddComment(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewPostRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(2), int32(1), "Nice one"}).Return([]map[string]any{{"id_comment": int32(7)}}, nil)
+
+	commentId, err := repo.AddComment(2, 1, "Nice one")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), commentId)
+	mockConn.AssertExpectations(t)
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"fmt"
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/model"
+)
+
+const REPORT_TABLE_NAME = "REPORT"
+
+type ReportRepository struct {
+	connection postgres.PostgreConnection
+}
+
+func NewReportRepository(connection postgres.PostgreConnection) *ReportRepository {
+	return &ReportRepository{
+		connection: connection,
+	}
+}
+
+func (repository *ReportRepository) Put(report *model.Report) error {
+	id, err := repository.connection.PutReturningId(report.ToMap(), REPORT_TABLE_NAME, "report_id")
+	if err != nil {
+		return err
+	}
+
+	report.ReportId = id.(int32)
+	return nil
+}
+
+// HasOpenReport tells whether the user already has a report waiting for
+// review against the content.
+func (repository *ReportRepository) HasOpenReport(reporterId int32, contentType string, contentId int32) (bool, error) {
+	constraint := map[string]any{
+		"reporter_id":  reporterId,
+		"content_type": contentType,
+		"content_id":   contentId,
+		"status":       model.REPORT_STATUS_OPEN,
+	}
+
+	reports, err := repository.connection.Get(constraint, REPORT_TABLE_NAME)
+	if err != nil {
+		return false, err
+	}
+	return len(reports) > 0, nil
+}
+
+// CountOpenReporters returns how many distinct users have open reports
+// against a piece of content.
+func (repository *ReportRepository) CountOpenReporters(contentType string, contentId int32) (int64, error) {
+	result, err := repository.connection.ExecuteReturning(
+		`
+		SELECT COUNT(DISTINCT reporter_id) AS reporters
+		FROM report
+		WHERE content_type = $1 AND content_id = $2 AND status = $3
+		`,
+		contentType,
+		contentId,
+		model.REPORT_STATUS_OPEN,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0]["reporters"].(int64), nil
+}
+
+// ListModerationQueue returns the reported contents with reports in the given
+// status, grouped by content and ordered by the number of reporters.
+func (repository *ReportRepository) ListModerationQueue(status string, limit int32, offset int32) ([]*model.ModerationItem, error) {
+	result, err := repository.connection.ExecuteReturning(
+		`
+		SELECT
+			r.content_type,
+			r.content_id,
+			COUNT(DISTINCT r.reporter_id) AS reporter_count,
+			array_agg(DISTINCT r.reason) AS reasons,
+			MIN(r.created_at) AS first_reported,
+			MAX(r.created_at) AS last_reported,
+			COALESCE(CASE r.content_type
+				WHEN 'post' THEN (SELECT hidden FROM post WHERE id = r.content_id)
+				WHEN 'comment' THEN (SELECT hidden FROM post_comment WHERE id_comment = r.content_id)
+				WHEN 'chat_message' THEN (SELECT hidden FROM chat_message WHERE message_id = r.content_id)
+				WHEN 'community' THEN (SELECT hidden FROM community WHERE id = r.content_id)
+			END, FALSE) AS hidden
+		FROM report r
+		WHERE r.status = $1
+		GROUP BY r.content_type, r.content_id
+		ORDER BY reporter_count DESC, last_reported DESC
+		LIMIT $2 OFFSET $3
+		`,
+		status,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.ModerationItem, 0)
+	for _, item := range result {
+		items = append(items, model.MapToModerationItem(item))
+	}
+	return items, nil
+}
+
+// ResolveReports closes all open reports against a piece of content with the
+// given status.
+func (repository *ReportRepository) ResolveReports(contentType string, contentId int32, status string, moderatorId int32) error {
+	return repository.connection.Execute(
+		`
+		UPDATE report
+		SET status = $1, reviewed_by = $2, reviewed_at = CURRENT_TIMESTAMP
+		WHERE content_type = $3 AND content_id = $4 AND status = $5
+		`,
+		status,
+		moderatorId,
+		contentType,
+		contentId,
+		model.REPORT_STATUS_OPEN,
+	)
+}
+
+func (repository *ReportRepository) ContentExists(contentType string, contentId int32) (bool, error) {
+	content, err := model.GetReportableContent(contentType)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := repository.connection.Get(map[string]any{content.IdColumn: contentId}, content.TableName)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (repository *ReportRepository) SetContentHidden(contentType string, contentId int32, hidden bool) error {
+	content, err := model.GetReportableContent(contentType)
+	if err != nil {
+		return err
+	}
+
+	return repository.connection.Execute(
+		fmt.Sprintf("UPDATE %s SET hidden = $1 WHERE %s = $2", content.TableName, content.IdColumn),
+		hidden,
+		contentId,
+	)
+}
+
+func (repository *ReportRepository) DeleteContent(contentType string, contentId int32) error {
+	content, err := model.GetReportableContent(contentType)
+	if err != nil {
+		return err
+	}
+
+	return repository.connection.Execute(
+		fmt.Sprintf("DELETE FROM %s WHERE %s = $1", content.TableName, content.IdColumn),
+		contentId,
+	)
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"symphony-api/internal/persistence/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReportRepository_Put(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewReportRepository(mockConn)
+	report := model.NewReport(1, model.REPORTED_POST, 10, "spam")
+
+	mockConn.On("PutReturningId", report.ToMap(), REPORT_TABLE_NAME, "report_id").Return(int32(5), nil)
+
+	err := repo.Put(report)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), report.ReportId)
+	mockConn.AssertExpectations(t)
+}
+
+func TestReportRepository_HasOpenReport(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewReportRepository(mockConn)
+	constraint := map[string]any{
+		"reporter_id":  int32(1),
+		"content_type": model.REPORTED_COMMENT,
+		"content_id":   int32(10),
+		"status":       model.REPORT_STATUS_OPEN,
+	}
+
+	mockConn.On("Get", constraint, REPORT_TABLE_NAME).Return([]map[string]any{{"report_id": int32(5)}}, nil)
+
+	reported, err := repo.HasOpenReport(1, model.REPORTED_COMMENT, 10)
+
+	assert.NoError(t, err)
+	assert.True(t, reported)
+	mockConn.AssertExpectations(t)
+}
+
+func TestReportRepository_CountOpenReporters(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewReportRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{model.REPORTED_POST, int32(10), model.REPORT_STATUS_OPEN}).
+		Return([]map[string]any{{"reporters": int64(3)}}, nil)
+
+	count, err := repo.CountOpenReporters(model.REPORTED_POST, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	mockConn.AssertExpectations(t)
+}
+
+func TestReportRepository_ContentExists(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewReportRepository(mockConn)
+
+	mockConn.On("Get", map[string]any{"message_id": int32(7)}, "chat_message").Return([]map[string]any{}, nil)
+
+	exists, err := repo.ContentExists(model.REPORTED_CHAT_MESSAGE, 7)
+
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	mockConn.AssertExpectations(t)
+}
+
+func TestReportRepository_SetContentHidden(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewReportRepository(mockConn)
+
+	mockConn.On("Execute", "UPDATE community SET hidden = $1 WHERE id = $2", []any{true, int32(4)}).Return(nil)
+
+	err := repo.SetContentHidden(model.REPORTED_COMMUNITY, 4, true)
+
+	assert.NoError(t, err)
+	mockConn.AssertExpectations(t)
+}
+
+func TestReportRepository_DeleteContent_Failure(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewReportRepository(mockConn)
+
+	mockConn.On("Execute", "DELETE FROM post WHERE id = $1", []any{int32(4)}).Return(errors.New("db error"))
+
+	err := repo.DeleteContent(model.REPORTED_POST, 4)
+
+	assert.Error(t, err)
+	mockConn.AssertExpectations(t)
+}
+
+func TestReportRepository_InvalidContentType(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewReportRepository(mockConn)
+
+	err := repo.SetContentHidden("users", 1, true)
+
+	assert.Error(t, err)
+	mockConn.AssertExpectations(t)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/model"
+	"time"
+)
+
+type UserEventRepository struct {
+	connection postgres.PostgreConnection
+}
+
+func NewUserEventRepository(connection postgres.PostgreConnection) *UserEventRepository {
+	return &UserEventRepository{
+		connection: connection,
+	}
+}
+
+// Append adds an event to the log of the user and returns it with its id.
+func (repository *UserEventRepository) Append(userId int32, eventType string, data []byte) (*model.UserEvent, error) {
+	rows, err := repository.connection.ExecuteReturning(
+		`
+		INSERT INTO user_event (user_id, type, data)
+		VALUES ($1, $2, $3::jsonb)
+		RETURNING event_id, user_id, type, data::text AS data, created_at
+		`,
+		userId,
+		eventType,
+		string(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("could not store event")
+	}
+
+	return model.MapToUserEvent(rows[0]), nil
+}
+
+// ListAfter returns up to limit events of the user newer than afterId, oldest
+// first.
+func (repository *UserEventRepository) ListAfter(userId int32, afterId int64, limit int32) ([]*model.UserEvent, error) {
+	rows, err := repository.connection.ExecuteReturning(
+		`
+		SELECT event_id, user_id, type, data::text AS data, created_at
+		FROM user_event
+		WHERE user_id = $1 AND event_id > $2
+		ORDER BY event_id
+		LIMIT $3
+		`,
+		userId,
+		afterId,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*model.UserEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, model.MapToUserEvent(row))
+	}
+
+	return events, nil
+}
+
+// Exists tells whether the event is still in the log of the user.
+func (repository *UserEventRepository) Exists(userId int32, eventId int64) (bool, error) {
+	rows, err := repository.connection.ExecuteReturning(
+		"SELECT 1 AS found FROM user_event WHERE user_id = $1 AND event_id = $2",
+		userId,
+		eventId,
+	)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+// PruneOlderThan deletes up to limit events older than age and returns how
+// many were deleted.
+func (repository *UserEventRepository) PruneOlderThan(age time.Duration, limit int32) (int64, error) {
+	rows, err := repository.connection.ExecuteReturning(
+		`
+		WITH pruned AS (
+			DELETE FROM user_event
+			WHERE event_id IN (
+				SELECT event_id FROM user_event
+				WHERE created_at < now() - make_interval(secs => $1)
+				ORDER BY event_id
+				LIMIT $2
+			)
+			RETURNING 1
+		)
+		SELECT COUNT(*) AS pruned FROM pruned
+		`,
+		age.Seconds(),
+		limit,
+	)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+
+	pruned, _ := rows[0]["pruned"].(int64)
+	return pruned, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserEventRepository_Append(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewUserEventRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), "friend.request", `{"from":"john"}`}).Return([]map[string]any{
+		{
+			"event_id":   int64(7),
+			"user_id":    int32(1),
+			"type":       "friend.request",
+			"data":       `{"from":"john"}`,
+			"created_at": time.Now(),
+		},
+	}, nil)
+
+	event, err := repo.Append(1, "friend.request", []byte(`{"from":"john"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), event.EventId)
+	assert.Equal(t, json.RawMessage(`{"from":"john"}`), event.Data)
+	mockConn.AssertExpectations(t)
+}
+
+func TestUserEventRepository_ListAfter(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewUserEventRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), int64(7), int32(200)}).Return([]map[string]any{}, nil)
+
+	events, err := repo.ListAfter(1, 7, 200)
+
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	mockConn.AssertExpectations(t)
+}
+
+func TestUserEventRepository_Exists(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewUserEventRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), int64(7)}).Return([]map[string]any{{"found": int32(1)}}, nil)
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(1), int64(8)}).Return([]map[string]any{}, nil)
+
+	exists, err := repo.Exists(1, 7)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.Exists(1, 8)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestUserEventRepository_PruneOlderThan(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewUserEventRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{float64(3600), int32(5000)}).Return([]map[string]any{{"pruned": int64(12)}}, nil)
+
+	pruned, err := repo.PruneOlderThan(time.Hour, 5000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), pruned)
+	mockConn.AssertExpectations(t)
+}
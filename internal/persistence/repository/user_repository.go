@@ -2,7 +2,9 @@ package repository
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"time"
 	"symphony-api/internal/persistence/connectors/neo4j"
 	"symphony-api/internal/persistence/connectors/postgres"
 	"symphony-api/internal/persistence/model"
@@ -38,24 +40,497 @@ func (repository *UserRepository) Put(user *model.User) error {
 	return repository.connection.Put(user.ToMap(), USER_TABLE_NAME)
 }
 
-func (repository *UserRepository) AddFriendship(username1 string, username2 string) error {
-	return repository.neo4jConn.Execute(
+// SendFriendRequest creates a pending FRIEND_REQUEST edge from one user to
+// another. Sending the same request twice keeps the original timestamp. It
+// returns false when the users are already friends, one of them blocked the
+// other or one of them does not exist.
+func (repository *UserRepository) SendFriendRequest(from string, to string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (from:User {username:$from}), (to:User {username:$to})
+		WHERE NOT (from)-[:FRIENDS_WITH]-(to)
+			AND NOT (from)-[:BLOCKED]-(to)
+		MERGE (from)-[r:FRIEND_REQUEST]->(to)
+		ON CREATE SET r.sent_at = datetime()
+		RETURN to.username AS username
+		`,
+		map[string]any{
+			"from": from,
+			"to": to,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+func (repository *UserRepository) HasFriendRequest(from string, to string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$from})-[r:FRIEND_REQUEST]->(to:User {username:$to})
+		RETURN to.username AS username
+		LIMIT 1
+		`,
+		map[string]any{
+			"from": from,
+			"to": to,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+// AcceptFriendRequest replaces the pending request from requester to username
+// with a FRIENDS_WITH edge. It returns false when there was no such request.
+func (repository *UserRepository) AcceptFriendRequest(requester string, username string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (requester:User {username:$requester})-[r:FRIEND_REQUEST]->(u:User {username:$username})
+		DELETE r
+		MERGE (requester)-[f:FRIENDS_WITH]-(u)
+		ON CREATE SET f.since = datetime()
+		RETURN requester.username AS username
+		`,
+		map[string]any{
+			"requester": requester,
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+// DeleteFriendRequest removes the pending request from one user to another.
+// It is used both when the receiver declines and when the sender cancels.
+// It returns false when there was no such request.
+func (repository *UserRepository) DeleteFriendRequest(from string, to string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$from})-[r:FRIEND_REQUEST]->(to:User {username:$to})
+		DELETE r
+		RETURN to.username AS username
+		`,
+		map[string]any{
+			"from": from,
+			"to": to,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+func (repository *UserRepository) ListIncomingFriendRequests(username string) ([]*model.FriendRequest, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (from:User)-[r:FRIEND_REQUEST]->(to:User {username:$username})
+		RETURN from.username AS from, to.username AS to, r.sent_at AS sent_at
+		ORDER BY r.sent_at DESC
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getFriendRequestsFromRecords(result), nil
+}
+
+func (repository *UserRepository) ListOutgoingFriendRequests(username string) ([]*model.FriendRequest, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (from:User {username:$username})-[r:FRIEND_REQUEST]->(to:User)
+		RETURN from.username AS from, to.username AS to, r.sent_at AS sent_at
+		ORDER BY r.sent_at DESC
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getFriendRequestsFromRecords(result), nil
+}
+
+// RemoveFriendship deletes the FRIENDS_WITH edge between two users. It returns
+// false when they were not friends.
+func (repository *UserRepository) RemoveFriendship(username1 string, username2 string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$username1})-[f:FRIENDS_WITH]-(u2:User {username:$username2})
+		DELETE f
+		RETURN u2.username AS username
+		`,
+		map[string]any{
+			"username1": username1,
+			"username2": username2,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+func (repository *UserRepository) ListFriendshipsByUsername(username string) ([]*model.User, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (u:User {username:$username})-[:FRIENDS_WITH]-(friend:User)
+		RETURN friend.username AS friend
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.getAllUsers(getStringsFromRecord(result, "friend"))
+}
+
+// ListFriendUsernames returns only the usernames of the friends of a user,
+// without fetching their data from Postgres.
+func (repository *UserRepository) ListFriendUsernames(username string) ([]string, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (u:User {username:$username})-[:FRIENDS_WITH]-(friend:User)
+		RETURN friend.username AS friend
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getStringsFromRecord(result, "friend"), nil
+}
+
+func (repository *UserRepository) AreFriends(username1 string, username2 string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (u1:User {username:$username1})-[:FRIENDS_WITH]-(u2:User {username:$username2})
+		RETURN u2.username AS friend
+		LIMIT 1
+		`,
+		map[string]any{
+			"username1": username1,
+			"username2": username2,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+// FollowUser creates a FOLLOWS edge from follower to followee. Following is
+// one-sided and independent of friendship. It returns false when one of the
+// users does not exist or one of them blocked the other.
+func (repository *UserRepository) FollowUser(follower string, followee string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (follower:User {username:$follower}), (followee:User {username:$followee})
+		WHERE NOT (follower)-[:BLOCKED]-(followee)
+		MERGE (follower)-[f:FOLLOWS]->(followee)
+		ON CREATE SET f.since = datetime()
+		RETURN followee.username AS username
+		`,
+		map[string]any{
+			"follower": follower,
+			"followee": followee,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+// UnfollowUser returns false when follower was not following followee.
+func (repository *UserRepository) UnfollowUser(follower string, followee string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$follower})-[f:FOLLOWS]->(followee:User {username:$followee})
+		DELETE f
+		RETURN followee.username AS username
+		`,
+		map[string]any{
+			"follower": follower,
+			"followee": followee,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+// FollowArtist creates a FOLLOWS edge to the Artist node keyed by the Mongo
+// artist id, creating the node if needed. The caller must check the artist
+// exists in Mongo.
+func (repository *UserRepository) FollowArtist(follower string, artistId string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (follower:User {username:$follower})
+		MERGE (a:Artist {artist_id:$artistId})
+		MERGE (follower)-[f:FOLLOWS]->(a)
+		ON CREATE SET f.since = datetime()
+		RETURN a.artist_id AS artist_id
+		`,
+		map[string]any{
+			"follower": follower,
+			"artistId": artistId,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+// UnfollowArtist returns false when follower was not following the artist.
+func (repository *UserRepository) UnfollowArtist(follower string, artistId string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$follower})-[f:FOLLOWS]->(a:Artist {artist_id:$artistId})
+		DELETE f
+		RETURN a.artist_id AS artist_id
+		`,
+		map[string]any{
+			"follower": follower,
+			"artistId": artistId,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+func (repository *UserRepository) GetFollowCounts(username string) (*model.FollowCounts, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (u:User {username:$username})
+		RETURN
+			size([(follower:User)-[:FOLLOWS]->(u) | follower]) AS followers,
+			size([(u)-[:FOLLOWS]->(followee:User) | followee]) AS following_users,
+			size([(u)-[:FOLLOWS]->(artist:Artist) | artist]) AS following_artists
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	return &model.FollowCounts{
+		Followers: getInt64FromRecord(result[0], "followers"),
+		FollowingUsers: getInt64FromRecord(result[0], "following_users"),
+		FollowingArtists: getInt64FromRecord(result[0], "following_artists"),
+	}, nil
+}
+
+func (repository *UserRepository) ListFollowers(username string) ([]*model.User, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (follower:User)-[:FOLLOWS]->(:User {username:$username})
+		RETURN follower.username AS username
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.getAllUsers(getStringsFromRecord(result, "username"))
+}
+
+func (repository *UserRepository) ListFollowing(username string) ([]*model.User, error) {
+	usernames, err := repository.ListFollowingUsernames(username)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.getAllUsers(usernames)
+}
+
+// ListFollowingUsernames returns only the usernames of the users followed by
+// a user, without fetching their data from Postgres.
+func (repository *UserRepository) ListFollowingUsernames(username string) ([]string, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$username})-[:FOLLOWS]->(followee:User)
+		RETURN followee.username AS username
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getStringsFromRecord(result, "username"), nil
+}
+
+func (repository *UserRepository) ListFollowedArtistIds(username string) ([]string, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$username})-[:FOLLOWS]->(a:Artist)
+		RETURN a.artist_id AS artist_id
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getStringsFromRecord(result, "artist_id"), nil
+}
+
+func (repository *UserRepository) ListArtistFollowers(artistId string) ([]*model.User, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (follower:User)-[:FOLLOWS]->(:Artist {artist_id:$artistId})
+		RETURN follower.username AS username
+		`,
+		map[string]any{
+			"artistId": artistId,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.getAllUsers(getStringsFromRecord(result, "username"))
+}
+
+// BlockUser creates a BLOCKED edge from blocker to blocked and removes every
+// friendship, pending friend request and follow between them. It returns false
+// when one of the users does not exist.
+func (repository *UserRepository) BlockUser(blocker string, blocked string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (blocker:User {username:$blocker}), (blocked:User {username:$blocked})
+		OPTIONAL MATCH (blocker)-[r:FRIENDS_WITH|FRIEND_REQUEST|FOLLOWS]-(blocked)
+		DELETE r
+		WITH DISTINCT blocker, blocked
+		MERGE (blocker)-[b:BLOCKED]->(blocked)
+		ON CREATE SET b.since = datetime()
+		RETURN blocked.username AS username
+		`,
+		map[string]any{
+			"blocker": blocker,
+			"blocked": blocked,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+// UnblockUser returns false when blocker had not blocked the user.
+func (repository *UserRepository) UnblockUser(blocker string, blocked string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$blocker})-[b:BLOCKED]->(blocked:User {username:$blocked})
+		DELETE b
+		RETURN blocked.username AS username
+		`,
+		map[string]any{
+			"blocker": blocker,
+			"blocked": blocked,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+// IsBlocked tells whether any of the two users blocked the other.
+func (repository *UserRepository) IsBlocked(username1 string, username2 string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
 		`
-		MATCH (u1:User {username:$username1}), (u2:User {username:$username2})
-		MERGE (u1)-[:FRIENDS_WITH]-(u2)
+		MATCH (:User {username:$username1})-[:BLOCKED]-(u2:User {username:$username2})
+		RETURN u2.username AS username
+		LIMIT 1
 		`,
 		map[string]any{
 			"username1": username1,
 			"username2": username2,
 		},
 	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
 }
 
-func (repository *UserRepository) ListFriendshipsByUsername(username string) ([]*model.User, error) {
+func (repository *UserRepository) ListBlockedUsers(username string) ([]*model.User, error) {
 	result, err := repository.neo4jConn.ExecuteReturning(
 		`
-		MATCH (u:User {username:$username})-[:FRIENDS_WITH]-(friend:User)
-		RETURN friend.username AS friend
+		MATCH (:User {username:$username})-[:BLOCKED]->(blocked:User)
+		RETURN blocked.username AS username
 		`,
 		map[string]any{
 			"username": username,
@@ -66,7 +541,115 @@ func (repository *UserRepository) ListFriendshipsByUsername(username string) ([]
 		return nil, err
 	}
 
-	return repository.getAllUsers(getStringsFromRecord(result, "friend"))
+	return repository.getAllUsers(getStringsFromRecord(result, "username"))
+}
+
+// ListBlockRelatedUsernames returns the usernames of the users blocked by a
+// user together with the ones that blocked them.
+func (repository *UserRepository) ListBlockRelatedUsernames(username string) ([]string, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$username})-[:BLOCKED]-(other:User)
+		RETURN DISTINCT other.username AS username
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getStringsFromRecord(result, "username"), nil
+}
+
+// HasBlockAmong tells whether any of the given users blocked another one of
+// them.
+func (repository *UserRepository) HasBlockAmong(usernames []string) (bool, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (blocker:User)-[:BLOCKED]->(blocked:User)
+		WHERE blocker.username IN $usernames AND blocked.username IN $usernames
+		RETURN blocked.username AS username
+		LIMIT 1
+		`,
+		map[string]any{
+			"usernames": usernames,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(result) > 0, nil
+}
+
+func (repository *UserRepository) UpdatePrivacySettings(userId int32, profileVisibility string, messagePermission string) error {
+	return repository.connection.Execute(
+		`
+		UPDATE users
+		SET profile_visibility = $1, message_permission = $2
+		WHERE id = $3
+		`,
+		profileVisibility,
+		messagePermission,
+		userId,
+	)
+}
+
+// UpdateProfile writes the editable profile fields of the user, username
+// included, to Postgres. The Neo4j node is renamed separately with RenameNode.
+func (repository *UserRepository) UpdateProfile(user *model.User) error {
+	return repository.connection.Execute(
+		`
+		UPDATE users
+		SET username = $1, fullname = $2, bio = $3, avatar_url = $4, telephone = $5
+		WHERE id = $6
+		`,
+		user.Username,
+		user.Fullname,
+		user.Bio,
+		user.AvatarUrl,
+		user.Telephone,
+		user.UserId,
+	)
+}
+
+func (repository *UserRepository) RenameNode(oldUsername string, newUsername string) error {
+	return repository.neo4jConn.Execute(
+		`
+		MATCH (u:User {username:$oldUsername})
+		SET u.username = $newUsername
+		`,
+		map[string]any{
+			"oldUsername": oldUsername,
+			"newUsername": newUsername,
+		},
+	)
+}
+
+// Delete removes the user row. Posts, likes, comments, chat participation and
+// the other rows owned by the user are removed by the foreign keys.
+func (repository *UserRepository) Delete(userId int32) error {
+	return repository.connection.Execute(
+		"DELETE FROM users WHERE id = $1",
+		userId,
+	)
+}
+
+// DeleteNode removes the user node together with all its relationships.
+func (repository *UserRepository) DeleteNode(username string) error {
+	return repository.neo4jConn.Execute(
+		`
+		MATCH (u:User {username:$username})
+		DETACH DELETE u
+		`,
+		map[string]any{
+			"username": username,
+		},
+	)
 }
 
 func (repository *UserRepository) LikeGenre(username string, genreName string) (error) {
@@ -84,19 +667,20 @@ func (repository *UserRepository) LikeGenre(username string, genreName string) (
 	)
 }
 
+// getAllUsers hydrates the usernames returned by a graph query. Users that
+// are in the graph but not in Postgres are logged and left out instead of
+// failing the whole request.
 func (repository *UserRepository) getAllUsers(usernames []string) ([]*model.User, error) {
-	friends := make([]*model.User, 0)
-
-	for _, username := range usernames {
-		friend, err := repository.GetByUsername(username)
-		if err != nil {
-			return nil, err
-		}
+	users, missing, err := repository.GetByUsernames(usernames)
+	if err != nil {
+		return nil, err
+	}
 
-		friends = append(friends, friend)
+	if len(missing) > 0 {
+		log.Printf("Could not find users %v", missing)
 	}
 
-	return friends, nil
+	return users, nil
 }
 
 func (repository *UserRepository) ListLikedGenres(username string) ([]string, error) {
@@ -123,6 +707,8 @@ func (repository *UserRepository) GetRecommendationsOnGenre(username string) ([]
 		MATCH (u:User {username: $username})-[:LIKES]->(g:Genre)<-[:LIKES]-(other:User)
 		WHERE other.username <> $username
   			AND NOT (u)-[:FRIENDS_WITH]-(other)
+  			AND NOT (u)-[:FRIEND_REQUEST]-(other)
+  			AND NOT (u)-[:BLOCKED]-(other)
 		RETURN DISTINCT other.username AS username
 		LIMIT 10
 		`,
@@ -138,6 +724,201 @@ func (repository *UserRepository) GetRecommendationsOnGenre(username string) ([]
 	return repository.getAllUsers(getStringsFromRecord(result, "username"))
 }
 
+// recommendationSignals filters the candidates of a recommendation for user u
+// and returns the signals the graph knows about them: mutual friends and
+// shared genres. Friends, users with a pending friend request and blocked
+// users are left out.
+const recommendationSignals = `
+		WITH u, candidate
+		WHERE candidate <> u
+			AND NOT (u)-[:FRIENDS_WITH]-(candidate)
+			AND NOT (u)-[:FRIEND_REQUEST]-(candidate)
+			AND NOT (u)-[:BLOCKED]-(candidate)
+		WITH u, candidate,
+			size([(u)-[:FRIENDS_WITH]-(m:User)-[:FRIENDS_WITH]-(candidate) | m]) AS mutual_friends,
+			[(u)-[:LIKES]->(g:Genre)<-[:LIKES]-(candidate) | g.genre_name] AS shared_genres
+		RETURN candidate.username AS username,
+			mutual_friends,
+			shared_genres,
+			size([(u)-[:LIKES]->(g:Genre) | g]) AS user_genres,
+			size([(candidate)-[:LIKES]->(g:Genre) | g]) AS candidate_genres
+`
+
+// ListRecommendationCandidates returns the friends of friends of a user and
+// the users that like one of their genres, together with their graph signals
+// (see recommendationSignals). At most limit candidates are returned, the
+// ones with more mutual friends and shared genres first.
+func (repository *UserRepository) ListRecommendationCandidates(username string, limit int32) ([]*model.Recommendation, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (u:User {username:$username})
+		CALL {
+			WITH u
+			MATCH (u)-[:FRIENDS_WITH]-(:User)-[:FRIENDS_WITH]-(candidate:User)
+			RETURN candidate
+			UNION
+			WITH u
+			MATCH (u)-[:LIKES]->(:Genre)<-[:LIKES]-(candidate:User)
+			RETURN candidate
+		}
+		` + recommendationSignals + `
+		ORDER BY mutual_friends DESC, size(shared_genres) DESC, username
+		LIMIT $limit
+		`,
+		map[string]any{
+			"username": username,
+			"limit": limit,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getRecommendationsFromRecords(result), nil
+}
+
+// ListRecommendationSignals returns the graph signals of the given candidates
+// for a user (see recommendationSignals). Candidates missing from the graph
+// are left out.
+func (repository *UserRepository) ListRecommendationSignals(username string, candidates []string) ([]*model.Recommendation, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (u:User {username:$username}), (candidate:User)
+		WHERE candidate.username IN $candidates
+		` + recommendationSignals,
+		map[string]any{
+			"username": username,
+			"candidates": candidates,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getRecommendationsFromRecords(result), nil
+}
+
+// GetShortestPath returns the usernames along the shortest chain of
+// friendships between two users, both included, skipping users blocked by or
+// blocking the first one. It returns an empty slice when the users are not
+// connected within maxDepth friendships.
+func (repository *UserRepository) GetShortestPath(username string, target string, maxDepth int) ([]string, error) {
+	// Variable length bounds can not be query parameters, so maxDepth is
+	// formatted into the query. It is an int, so nothing else can get in.
+	result, err := repository.neo4jConn.ExecuteReturning(
+		fmt.Sprintf(
+			`
+			MATCH (u:User {username:$username}), (target:User {username:$target})
+			MATCH p = shortestPath((u)-[:FRIENDS_WITH*..%d]-(target))
+			WHERE none(n IN nodes(p) WHERE (u)-[:BLOCKED]-(n))
+			RETURN [n IN nodes(p) | n.username] AS usernames
+			`,
+			maxDepth,
+		),
+		map[string]any{
+			"username": username,
+			"target": target,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return make([]string, 0), nil
+	}
+
+	return getStringListFromRecord(result[0], "usernames"), nil
+}
+
+func (repository *UserRepository) ListMutualFriends(username1 string, username2 string) ([]*model.User, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$username1})-[:FRIENDS_WITH]-(friend:User)-[:FRIENDS_WITH]-(:User {username:$username2})
+		RETURN DISTINCT friend.username AS friend
+		ORDER BY friend
+		`,
+		map[string]any{
+			"username1": username1,
+			"username2": username2,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.getAllUsers(getStringsFromRecord(result, "friend"))
+}
+
+func (repository *UserRepository) ListSharedGenres(username1 string, username2 string) ([]string, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (:User {username:$username1})-[:LIKES]->(g:Genre)<-[:LIKES]-(:User {username:$username2})
+		RETURN DISTINCT g.genre_name AS genre
+		ORDER BY genre
+		`,
+		map[string]any{
+			"username1": username1,
+			"username2": username2,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return getStringsFromRecord(result, "genre"), nil
+}
+
+// GetSocialProximity tells, for each of the candidates, whether they are a
+// friend of the user or followed by them and how many friends they share.
+// Candidates with none of those are not in the map.
+func (repository *UserRepository) GetSocialProximity(username string, candidates []string) (map[string]*model.SocialProximity, error) {
+	result, err := repository.neo4jConn.ExecuteReturning(
+		`
+		MATCH (u:User {username:$username})
+		UNWIND $candidates AS candidate
+		MATCH (c:User {username:candidate})
+		WITH u, c,
+			EXISTS { (u)-[:FRIENDS_WITH]-(c) } AS friend,
+			EXISTS { (u)-[:FOLLOWS]->(c) } AS following,
+			size([(u)-[:FRIENDS_WITH]-(m:User)-[:FRIENDS_WITH]-(c) | m]) AS mutual_friends
+		WHERE friend OR following OR mutual_friends > 0
+		RETURN c.username AS username, friend, following, mutual_friends
+		`,
+		map[string]any{
+			"username": username,
+			"candidates": candidates,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	proximities := make(map[string]*model.SocialProximity, len(result))
+	for _, record := range result {
+		candidate, _ := record.Get("username")
+		friend, _ := record.Get("friend")
+		following, _ := record.Get("following")
+
+		proximity := &model.SocialProximity{
+			MutualFriends: getInt64FromRecord(record, "mutual_friends"),
+		}
+		proximity.IsFriend, _ = friend.(bool)
+		proximity.Following, _ = following.(bool)
+
+		name, _ := candidate.(string)
+		proximities[name] = proximity
+	}
+
+	return proximities, nil
+}
+
 func getStringsFromRecord(records []*neo4jDriver.Record, property string) []string {
 	properties := make([]string, 0)
 
@@ -152,6 +933,66 @@ func getStringsFromRecord(records []*neo4jDriver.Record, property string) []stri
 	return properties
 }
 
+func getInt64FromRecord(record *neo4jDriver.Record, property string) int64 {
+	value, _ := record.Get(property)
+	number, _ := value.(int64)
+
+	return number
+}
+
+func getStringListFromRecord(record *neo4jDriver.Record, property string) []string {
+	value, _ := record.Get(property)
+	values, _ := value.([]any)
+
+	texts := make([]string, 0, len(values))
+	for _, value := range values {
+		if text, ok := value.(string); ok {
+			texts = append(texts, text)
+		}
+	}
+
+	return texts
+}
+
+func getFriendRequestsFromRecords(records []*neo4jDriver.Record) []*model.FriendRequest {
+	requests := make([]*model.FriendRequest, 0, len(records))
+
+	for _, record := range records {
+		from, _ := record.Get("from")
+		to, _ := record.Get("to")
+		sentAt, _ := record.Get("sent_at")
+
+		request := &model.FriendRequest{}
+		request.From, _ = from.(string)
+		request.To, _ = to.(string)
+		request.SentAt, _ = sentAt.(time.Time)
+
+		requests = append(requests, request)
+	}
+
+	return requests
+}
+
+func getRecommendationsFromRecords(records []*neo4jDriver.Record) []*model.Recommendation {
+	recommendations := make([]*model.Recommendation, 0, len(records))
+
+	for _, record := range records {
+		username, _ := record.Get("username")
+
+		recommendation := &model.Recommendation{
+			MutualFriends: getInt64FromRecord(record, "mutual_friends"),
+			SharedGenres: getStringListFromRecord(record, "shared_genres"),
+			UserGenreCount: getInt64FromRecord(record, "user_genres"),
+			CandidateGenres: getInt64FromRecord(record, "candidate_genres"),
+		}
+		recommendation.Username, _ = username.(string)
+
+		recommendations = append(recommendations, recommendation)
+	}
+
+	return recommendations
+}
+
 func (repository *UserRepository) get(constraint map[string]any) ([]*model.User, error) {
 	data, err := repository.connection.Get(constraint, USER_TABLE_NAME)
 
@@ -169,7 +1010,14 @@ func (repository *UserRepository) GetById(userId int64) (*model.User, error) {
 	}
 
 	users, err := repository.get(constraint)
-	return users[0], err
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, errors.New("user not found")
+	}
+
+	return users[0], nil
 }
 
 func (repository *UserRepository) GetByUsername(username string) (*model.User, error) {
@@ -187,6 +1035,97 @@ func (repository *UserRepository) GetByUsername(username string) (*model.User, e
 	return users[0], err
 }
 
+// GetByUsernames fetches the users with the given usernames with a single
+// query. Users are returned in the order of usernames, once each; usernames
+// without a user are skipped and returned as missing.
+func (repository *UserRepository) GetByUsernames(usernames []string) ([]*model.User, []string, error) {
+	users := make([]*model.User, 0, len(usernames))
+	missing := make([]string, 0)
+
+	if len(usernames) == 0 {
+		return users, missing, nil
+	}
+
+	data, err := repository.connection.ExecuteReturning(
+		"SELECT * FROM users WHERE username = ANY($1)",
+		usernames,
+	)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byUsername := make(map[string]*model.User, len(data))
+	for _, user := range model.MapArrayToUsers(data) {
+		byUsername[user.Username] = user
+	}
+
+	seen := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		user, ok := byUsername[username]
+		if !ok {
+			missing = append(missing, username)
+			continue
+		}
+		users = append(users, user)
+	}
+
+	return users, missing, nil
+}
+
+// Search returns the users whose username or full name look like the query,
+// ignoring case and accents. Usernames are compared with trigram similarity
+// and full names with word similarity, so "joao" finds "João da Silva".
+// Users whose username or full name start with the query are flagged as
+// prefix matches. At most limit users are returned, best matches first.
+func (repository *UserRepository) Search(query string, limit int32) ([]*model.UserSearchResult, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		WITH search AS (
+			SELECT immutable_unaccent(lower($1)) AS term,
+				immutable_unaccent(lower($2)) || '%' AS prefix
+		), candidate AS (
+			SELECT u.*,
+				immutable_unaccent(lower(u.username)) AS search_username,
+				immutable_unaccent(lower(u.fullname)) AS search_fullname
+			FROM users u
+		)
+		SELECT c.*,
+			GREATEST(
+				similarity(c.search_username, s.term),
+				word_similarity(s.term, c.search_fullname)
+			)::float8 AS similarity,
+			(c.search_username LIKE s.prefix OR c.search_fullname LIKE s.prefix) AS prefix_match
+		FROM candidate c, search s
+		WHERE c.search_username LIKE s.prefix
+			OR c.search_fullname LIKE s.prefix
+			OR c.search_username % s.term
+			OR s.term <% c.search_fullname
+		ORDER BY prefix_match DESC, similarity DESC, c.username
+		LIMIT $3
+		`,
+		query,
+		model.EscapeLikePattern(query),
+		limit,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.UserSearchResult, 0, len(data))
+	for _, row := range data {
+		results = append(results, model.MapToUserSearchResult(row))
+	}
+
+	return results, nil
+}
+
 func (repository *UserRepository) ListUserCommunities(user *model.User) ([]*model.Community, error) {
 	constraint := map[string]any {
 		"uc.user_id": user.UserId,
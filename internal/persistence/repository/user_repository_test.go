@@ -6,6 +6,7 @@ import (
 
 	"symphony-api/internal/persistence/model"
 
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -39,13 +40,13 @@ func getFetchTestData() (*model.User, map[string]any) {
 		Username:     "john",
 		Fullname:     "John Doe",
 		Email:        "john@example.com",
-		Register_date: time.Now(),
+		Register_date: time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC),
 		Birth_date:    time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC),
 		Telephone:    "123456789",
 	}
 	userMap := user.ToMap()
 	userMap["id"] = int32(1)
-	userMap["register_date"] =  time.Now()
+	userMap["register_date"] = time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
 
 	return user, userMap
 }
@@ -78,4 +79,142 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 
 	assert.Equal(t, user, result)
 	mockConn.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestGetFriendRequestsFromRecords(t *testing.T) {
+	sentAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := []*neo4j.Record{
+		{
+			Keys:   []string{"from", "to", "sent_at"},
+			Values: []any{"john", "mary", sentAt},
+		},
+	}
+
+	requests := getFriendRequestsFromRecords(records)
+
+	assert.Equal(t, []*model.FriendRequest{
+		{From: "john", To: "mary", SentAt: sentAt},
+	}, requests)
+}
+
+func TestGetRecommendationsFromRecords(t *testing.T) {
+	records := []*neo4j.Record{
+		{
+			Keys:   []string{"username", "mutual_friends", "shared_genres", "user_genres", "candidate_genres"},
+			Values: []any{"mary", int64(3), []any{"Jazz", "Rock"}, int64(4), int64(2)},
+		},
+	}
+
+	recommendations := getRecommendationsFromRecords(records)
+
+	assert.Equal(t, []*model.Recommendation{
+		{
+			Username:        "mary",
+			MutualFriends:   3,
+			SharedGenres:    []string{"Jazz", "Rock"},
+			UserGenreCount:  4,
+			CandidateGenres: 2,
+		},
+	}, recommendations)
+}
+
+func TestGetStringListFromRecord(t *testing.T) {
+	record := &neo4j.Record{
+		Keys:   []string{"usernames"},
+		Values: []any{[]any{"john", "bob", "mary"}},
+	}
+
+	assert.Equal(t, []string{"john", "bob", "mary"}, getStringListFromRecord(record, "usernames"))
+	assert.Empty(t, getStringListFromRecord(record, "missing"))
+}
+
+func TestUserRepository_UpdatePrivacySettings(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	mockNeo4j := &MockNeo4jConn{}
+	repo := NewUserRepository(mockConn, mockNeo4j)
+
+	mockConn.On("Execute", mock.Anything, []any{model.PROFILE_FRIENDS, model.MESSAGES_NOBODY, int32(1)}).Return(nil)
+
+	err := repo.UpdatePrivacySettings(1, model.PROFILE_FRIENDS, model.MESSAGES_NOBODY)
+
+	assert.NoError(t, err)
+	mockConn.AssertExpectations(t)
+}
+
+func TestUserRepository_UpdateProfile(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	mockNeo4j := &MockNeo4jConn{}
+	repo := NewUserRepository(mockConn, mockNeo4j)
+
+	user, _ := getFetchTestData()
+	user.Bio = "Jazz lover"
+
+	mockConn.On("Execute", mock.Anything, []any{"john", "John Doe", "Jazz lover", "", "123456789", int32(1)}).Return(nil)
+
+	err := repo.UpdateProfile(user)
+
+	assert.NoError(t, err)
+	mockConn.AssertExpectations(t)
+}
+
+func TestUserRepository_GetByUsernames(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	mockNeo4j := &MockNeo4jConn{}
+	repo := NewUserRepository(mockConn, mockNeo4j)
+
+	_, johnMap := getFetchTestData()
+	_, maryMap := getFetchTestData()
+	maryMap["id"] = int32(2)
+	maryMap["username"] = "mary"
+
+	usernames := []string{"mary", "ghost", "john", "mary"}
+	mockConn.On("ExecuteReturning", mock.Anything, []any{usernames}).Return(
+		[]map[string]any{johnMap, maryMap},
+		nil,
+	)
+
+	users, missing, err := repo.GetByUsernames(usernames)
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "mary", users[0].Username)
+	assert.Equal(t, "john", users[1].Username)
+	assert.Equal(t, []string{"ghost"}, missing)
+	mockConn.AssertExpectations(t)
+}
+
+func TestUserRepository_GetByUsernames_Empty(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	mockNeo4j := &MockNeo4jConn{}
+	repo := NewUserRepository(mockConn, mockNeo4j)
+
+	users, missing, err := repo.GetByUsernames([]string{})
+
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+	assert.Empty(t, missing)
+	mockConn.AssertNotCalled(t, "ExecuteReturning", mock.Anything, mock.Anything)
+}
+
+func TestUserRepository_Search(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	mockNeo4j := &MockNeo4jConn{}
+	repo := NewUserRepository(mockConn, mockNeo4j)
+
+	user, userMap := getFetchTestData()
+	userMap["similarity"] = 0.75
+	userMap["prefix_match"] = true
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{"jo_n", `jo\_n`, int32(10)}).Return(
+		[]map[string]any{userMap},
+		nil,
+	)
+
+	results, err := repo.Search("jo_n", 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.UserSearchResult{
+		{User: user, Similarity: 0.75, PrefixMatch: true},
+	}, results)
+	mockConn.AssertExpectations(t)
+}
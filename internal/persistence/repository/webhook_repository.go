@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"errors"
+	"symphony-api/internal/persistence/connectors/postgres"
+	"symphony-api/internal/persistence/model"
+	"time"
+)
+
+// The payload is read as text so it is not decoded into a map on the way.
+const WEBHOOK_DELIVERY_COLUMNS = `delivery_id, subscription_id, event_type, payload::text AS payload, status,
+	attempts, next_attempt_at, replay_of, created_at, delivered_at`
+
+type WebhookRepository struct {
+	connection postgres.PostgreConnection
+}
+
+func NewWebhookRepository(connection postgres.PostgreConnection) *WebhookRepository {
+	return &WebhookRepository{
+		connection: connection,
+	}
+}
+
+func (repository *WebhookRepository) CreateSubscription(createdBy int32, url string, secret string, eventTypes []string) (*model.WebhookSubscription, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		INSERT INTO webhook_subscription (url, secret, event_types, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING *
+		`,
+		url,
+		secret,
+		eventTypes,
+		createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("could not create subscription")
+	}
+
+	return model.MapToWebhookSubscription(data[0]), nil
+}
+
+func (repository *WebhookRepository) ListSubscriptions() ([]*model.WebhookSubscription, error) {
+	data, err := repository.connection.ExecuteReturning(
+		"SELECT * FROM webhook_subscription ORDER BY subscription_id",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*model.WebhookSubscription, 0, len(data))
+	for _, row := range data {
+		subscriptions = append(subscriptions, model.MapToWebhookSubscription(row))
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscription returns nil when there is no such subscription.
+func (repository *WebhookRepository) GetSubscription(subscriptionId int32) (*model.WebhookSubscription, error) {
+	data, err := repository.connection.ExecuteReturning(
+		"SELECT * FROM webhook_subscription WHERE subscription_id = $1",
+		subscriptionId,
+	)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	return model.MapToWebhookSubscription(data[0]), nil
+}
+
+// UpdateSubscription replaces the url, event types and state of a
+// subscription. It returns nil when there is no such subscription.
+func (repository *WebhookRepository) UpdateSubscription(subscriptionId int32, url string, eventTypes []string, active bool) (*model.WebhookSubscription, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		UPDATE webhook_subscription SET url = $2, event_types = $3, active = $4
+		WHERE subscription_id = $1
+		RETURNING *
+		`,
+		subscriptionId,
+		url,
+		eventTypes,
+		active,
+	)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	return model.MapToWebhookSubscription(data[0]), nil
+}
+
+// DeleteSubscription removes a subscription along with its deliveries. It
+// returns false when there is no such subscription.
+func (repository *WebhookRepository) DeleteSubscription(subscriptionId int32) (bool, error) {
+	data, err := repository.connection.ExecuteReturning(
+		"DELETE FROM webhook_subscription WHERE subscription_id = $1 RETURNING subscription_id",
+		subscriptionId,
+	)
+	if err != nil {
+		return false, err
+	}
+	return len(data) > 0, nil
+}
+
+// Enqueue queues a delivery of the payload to every active subscription to
+// the event type.
+func (repository *WebhookRepository) Enqueue(eventType string, payload []byte) error {
+	return repository.connection.Execute(
+		`
+		INSERT INTO webhook_delivery (subscription_id, event_type, payload)
+		SELECT subscription_id, $1::text, $2::jsonb
+		FROM webhook_subscription
+		WHERE active AND $1::text = ANY(event_types)
+		`,
+		eventType,
+		string(payload),
+	)
+}
+
+// ClaimDue takes up to limit pending deliveries that are due, oldest first,
+// along with the url and secret of their subscription. Their next attempt is
+// pushed lease into the future, so other workers skip them meanwhile and they
+// are sent again if this one never records the attempt. Deliveries of paused
+// subscriptions wait until they are active again.
+func (repository *WebhookRepository) ClaimDue(limit int32, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		UPDATE webhook_delivery d
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM webhook_subscription s
+		WHERE s.subscription_id = d.subscription_id
+		AND d.delivery_id IN (
+			SELECT w.delivery_id
+			FROM webhook_delivery w
+			JOIN webhook_subscription ws ON ws.subscription_id = w.subscription_id
+			WHERE w.status = 'pending' AND w.next_attempt_at <= now() AND ws.active
+			ORDER BY w.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF w SKIP LOCKED
+		)
+		RETURNING d.delivery_id, d.subscription_id, d.event_type, d.payload::text AS payload, d.status,
+			d.attempts, d.next_attempt_at, d.replay_of, d.created_at, d.delivered_at, s.url, s.secret
+		`,
+		limit,
+		lease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToWebhookDeliveries(data), nil
+}
+
+// RecordAttempt logs an attempt to send a delivery and moves it to status.
+// Pending deliveries are tried again after retryIn.
+func (repository *WebhookRepository) RecordAttempt(attempt *model.WebhookAttempt, status string, retryIn time.Duration) error {
+	return repository.connection.Execute(
+		`
+		WITH logged AS (
+			INSERT INTO webhook_delivery_attempt (delivery_id, attempt, status_code, error, response_body, duration_ms)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		)
+		UPDATE webhook_delivery
+		SET attempts = $2,
+			status = $7::varchar,
+			next_attempt_at = now() + make_interval(secs => $8),
+			delivered_at = CASE WHEN $7::varchar = 'delivered' THEN now() ELSE delivered_at END
+		WHERE delivery_id = $1
+		`,
+		attempt.DeliveryId,
+		attempt.Attempt,
+		attempt.StatusCode,
+		attempt.Error,
+		attempt.ResponseBody,
+		attempt.DurationMs,
+		status,
+		retryIn.Seconds(),
+	)
+}
+
+// ListDeliveries returns a page of the deliveries of a subscription, newest
+// first. An empty status lists all of them.
+func (repository *WebhookRepository) ListDeliveries(subscriptionId int32, status string, limit int32, offset int32) ([]*model.WebhookDelivery, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		SELECT `+WEBHOOK_DELIVERY_COLUMNS+`
+		FROM webhook_delivery
+		WHERE subscription_id = $1 AND ($2::text = '' OR status = $2::text)
+		ORDER BY delivery_id DESC
+		LIMIT $3 OFFSET $4
+		`,
+		subscriptionId,
+		status,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToWebhookDeliveries(data), nil
+}
+
+// GetDelivery returns nil when there is no such delivery.
+func (repository *WebhookRepository) GetDelivery(deliveryId int64) (*model.WebhookDelivery, error) {
+	data, err := repository.connection.ExecuteReturning(
+		"SELECT "+WEBHOOK_DELIVERY_COLUMNS+" FROM webhook_delivery WHERE delivery_id = $1",
+		deliveryId,
+	)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	return model.MapToWebhookDelivery(data[0]), nil
+}
+
+// ListAttempts returns the log of the attempts to send a delivery, in the
+// order they were made.
+func (repository *WebhookRepository) ListAttempts(deliveryId int64) ([]*model.WebhookAttempt, error) {
+	data, err := repository.connection.ExecuteReturning(
+		"SELECT * FROM webhook_delivery_attempt WHERE delivery_id = $1 ORDER BY attempt",
+		deliveryId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]*model.WebhookAttempt, 0, len(data))
+	for _, row := range data {
+		attempts = append(attempts, model.MapToWebhookAttempt(row))
+	}
+
+	return attempts, nil
+}
+
+// Replay queues a new delivery with the same payload as an existing one, to
+// be sent right away. It returns nil when there is no such delivery.
+func (repository *WebhookRepository) Replay(deliveryId int64) (*model.WebhookDelivery, error) {
+	data, err := repository.connection.ExecuteReturning(
+		`
+		INSERT INTO webhook_delivery (subscription_id, event_type, payload, replay_of)
+		SELECT subscription_id, event_type, payload, delivery_id
+		FROM webhook_delivery
+		WHERE delivery_id = $1
+		RETURNING `+WEBHOOK_DELIVERY_COLUMNS,
+		deliveryId,
+	)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	return model.MapToWebhookDelivery(data[0]), nil
+}
+
+func mapToWebhookDeliveries(data []map[string]any) []*model.WebhookDelivery {
+	deliveries := make([]*model.WebhookDelivery, 0, len(data))
+	for _, row := range data {
+		deliveries = append(deliveries, model.MapToWebhookDelivery(row))
+	}
+	return deliveries
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"symphony-api/internal/persistence/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWebhookRepository_Enqueue(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewWebhookRepository(mockConn)
+
+	mockConn.On("Execute", mock.Anything, []any{"user.created", `{"type":"user.created"}`}).Return(nil)
+
+	err := repo.Enqueue("user.created", []byte(`{"type":"user.created"}`))
+
+	assert.NoError(t, err)
+	mockConn.AssertExpectations(t)
+}
+
+func TestWebhookRepository_ClaimDue(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewWebhookRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int32(20), float64(60)}).Return([]map[string]any{
+		{
+			"delivery_id":     int64(3),
+			"subscription_id": int32(1),
+			"event_type":      "user.created",
+			"payload":         `{"type":"user.created"}`,
+			"status":          model.WEBHOOK_DELIVERY_PENDING,
+			"attempts":        int32(1),
+			"next_attempt_at": time.Now(),
+			"replay_of":       nil,
+			"created_at":      time.Now(),
+			"delivered_at":    nil,
+			"url":             "https://partner.example.com/hooks",
+			"secret":          "whsec_abc",
+		},
+	}, nil)
+
+	deliveries, err := repo.ClaimDue(20, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, int64(3), deliveries[0].DeliveryId)
+	assert.Equal(t, json.RawMessage(`{"type":"user.created"}`), deliveries[0].Payload)
+	assert.Equal(t, "https://partner.example.com/hooks", deliveries[0].Url)
+	assert.Equal(t, "whsec_abc", deliveries[0].Secret)
+	mockConn.AssertExpectations(t)
+}
+
+func TestWebhookRepository_RecordAttempt(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewWebhookRepository(mockConn)
+
+	mockConn.On("Execute", mock.Anything, []any{
+		int64(3), int32(2), int32(500), "unexpected status 500 Internal Server Error", "broken", int32(12),
+		model.WEBHOOK_DELIVERY_PENDING, float64(60),
+	}).Return(nil)
+
+	err := repo.RecordAttempt(&model.WebhookAttempt{
+		DeliveryId:   3,
+		Attempt:      2,
+		StatusCode:   500,
+		Error:        "unexpected status 500 Internal Server Error",
+		ResponseBody: "broken",
+		DurationMs:   12,
+	}, model.WEBHOOK_DELIVERY_PENDING, time.Minute)
+
+	assert.NoError(t, err)
+	mockConn.AssertExpectations(t)
+}
+
+func TestWebhookRepository_Replay_NotFound(t *testing.T) {
+	mockConn := new(MockPostgreConnection)
+	repo := NewWebhookRepository(mockConn)
+
+	mockConn.On("ExecuteReturning", mock.Anything, []any{int64(9)}).Return([]map[string]any{}, nil)
+
+	delivery, err := repo.Replay(9)
+
+	assert.NoError(t, err)
+	assert.Nil(t, delivery)
+	mockConn.AssertExpectations(t)
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	mongo_repository "symphony-api/internal/persistence/repository/mongo"
+	"symphony-api/internal/persistence/storage"
+	"time"
+)
+
+type AccountService struct {
+	userRepository       *repository.UserRepository
+	postRepository       *repository.PostRepository
+	chatRepository       *repository.ChatRepository
+	dataExportRepository *repository.DataExportRepository
+	playlistRepository   *mongo_repository.PlaylistRepository
+	storyRepository      *mongo_repository.StoryRepository
+	exportStorage        storage.Storage
+	exportSlots          chan struct{}
+}
+
+func NewAccountService(
+	userRepository *repository.UserRepository,
+	postRepository *repository.PostRepository,
+	chatRepository *repository.ChatRepository,
+	dataExportRepository *repository.DataExportRepository,
+	playlistRepository *mongo_repository.PlaylistRepository,
+	storyRepository *mongo_repository.StoryRepository,
+	exportStorage storage.Storage,
+	maxConcurrentExports int,
+) *AccountService {
+	return &AccountService{
+		userRepository:       userRepository,
+		postRepository:       postRepository,
+		chatRepository:       chatRepository,
+		dataExportRepository: dataExportRepository,
+		playlistRepository:   playlistRepository,
+		storyRepository:      storyRepository,
+		exportStorage:        exportStorage,
+		exportSlots:          make(chan struct{}, max(maxConcurrentExports, 1)),
+	}
+}
+
+// GetAccount returns the user of the account.
+func (service *AccountService) GetAccount(userId int32) (*model.User, error) {
+	user, err := service.userRepository.GetById(int64(userId))
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	return user, nil
+}
+
+// DeleteAccount removes the user from the three stores. The Postgres row is
+// the account itself, so it goes first: once it is deleted the user is gone
+// even if cleaning up the rest fails. The keys of the exports are read before,
+// as their rows are deleted along with the user.
+func (service *AccountService) DeleteAccount(ctx context.Context, userId int32) error {
+	user, err := service.userRepository.GetById(int64(userId))
+	if err != nil {
+		return errors.New("user does not exist")
+	}
+
+	exports, err := service.dataExportRepository.ListByUserId(user.UserId)
+	if err != nil {
+		return err
+	}
+
+	if err := service.userRepository.Delete(user.UserId); err != nil {
+		return err
+	}
+
+	if err := service.cleanUpAccount(ctx, user, exports); err != nil {
+		log.Printf("Error cleaning up deleted account of user %d (%s): %v", user.UserId, user.Username, err)
+	}
+
+	return nil
+}
+
+// cleanUpAccount removes what a deleted user left outside Postgres. Every step
+// is attempted even if others fail, and each can safely run more than once.
+func (service *AccountService) cleanUpAccount(ctx context.Context, user *model.User, exports []*model.DataExport) error {
+	errs := make([]error, 0)
+
+	if err := service.playlistRepository.DeleteByUsername(ctx, user.Username); err != nil {
+		errs = append(errs, fmt.Errorf("playlists: %w", err))
+	}
+	if err := service.storyRepository.DeleteByUsername(ctx, user.Username); err != nil {
+		errs = append(errs, fmt.Errorf("stories: %w", err))
+	}
+	if err := service.userRepository.DeleteNode(user.Username); err != nil {
+		errs = append(errs, fmt.Errorf("graph node: %w", err))
+	}
+	for _, export := range exports {
+		if export.StorageKey == "" {
+			continue
+		}
+		err := service.exportStorage.Delete(ctx, export.StorageKey)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("export %s: %w", export.StorageKey, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RequestExport creates an export of the data of the user. The archive is
+// built in the background, with at most maxConcurrentExports being built at
+// a time; its status is followed with GetExport.
+func (service *AccountService) RequestExport(userId int32) (*model.DataExport, error) {
+	user, err := service.userRepository.GetById(int64(userId))
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	exportId, err := service.dataExportRepository.Put(user.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	go service.runExport(exportId, user)
+
+	return service.dataExportRepository.GetById(exportId)
+}
+
+// GetExport returns an export of the user. Exports of other users are
+// reported as not found.
+func (service *AccountService) GetExport(userId int32, exportId int32) (*model.DataExport, error) {
+	export, err := service.dataExportRepository.GetById(exportId)
+	if err != nil || export.UserId != userId {
+		return nil, errors.New("export not found")
+	}
+
+	return export, nil
+}
+
+// OpenExport returns a reader over the archive of a finished export.
+func (service *AccountService) OpenExport(ctx context.Context, userId int32, exportId int32) (io.ReadCloser, error) {
+	export, err := service.GetExport(userId, exportId)
+	if err != nil {
+		return nil, err
+	}
+	if export.Status != model.EXPORT_DONE {
+		return nil, errors.New("export is not ready")
+	}
+
+	return service.exportStorage.Get(ctx, export.StorageKey)
+}
+
+func (service *AccountService) runExport(exportId int32, user *model.User) {
+	service.exportSlots <- struct{}{}
+	defer func() { <-service.exportSlots }()
+
+	ctx := context.Background()
+	status, key := model.EXPORT_DONE, ""
+
+	archive, err := service.buildExport(ctx, user)
+	if err == nil {
+		key, err = newExportKey(exportId)
+	}
+	if err == nil {
+		err = service.exportStorage.Put(ctx, key, "application/zip", archive)
+	}
+	if err != nil {
+		log.Printf("Error exporting data of user %d: %v", user.UserId, err)
+		status, key = model.EXPORT_FAILED, ""
+	}
+
+	found, err := service.dataExportRepository.Finish(exportId, status, key)
+	if err != nil {
+		log.Printf("Error finishing export %d: %v", exportId, err)
+		return
+	}
+
+	// The account was deleted while the archive was being built.
+	if !found && key != "" {
+		if err := service.exportStorage.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Error deleting export %d of a deleted account: %v", exportId, err)
+		}
+	}
+}
+
+type exportedProfile struct {
+	Id                int32     `json:"id"`
+	Username          string    `json:"username"`
+	Fullname          string    `json:"fullname"`
+	Email             string    `json:"email"`
+	Telephone         string    `json:"telephone"`
+	Bio               string    `json:"bio"`
+	AvatarUrl         string    `json:"avatar_url"`
+	BirthDate         time.Time `json:"birth_date"`
+	RegisterDate      time.Time `json:"register_date"`
+	ProfileVisibility string    `json:"profile_visibility"`
+	MessagePermission string    `json:"message_permission"`
+}
+
+type exportedPost struct {
+	Id             int32  `json:"id"`
+	Text           string `json:"text"`
+	UrlFoto        string `json:"url_foto"`
+	LikeCount      int    `json:"like_count"`
+	AttachmentType string `json:"attachment_type,omitempty"`
+	AttachmentId   string `json:"attachment_id,omitempty"`
+}
+
+func (service *AccountService) buildExport(ctx context.Context, user *model.User) ([]byte, error) {
+	posts, err := service.postRepository.ListAllByUserId(user.UserId)
+	if err != nil {
+		return nil, err
+	}
+	exportedPosts := make([]exportedPost, 0, len(posts))
+	for _, post := range posts {
+		exported := exportedPost{
+			Id:        post.PostId,
+			Text:      post.Text,
+			UrlFoto:   post.UrlFoto,
+			LikeCount: post.LikeCount,
+		}
+		if post.Attachment != nil {
+			exported.AttachmentType = post.Attachment.Type
+			exported.AttachmentId = post.Attachment.Id
+		}
+		exportedPosts = append(exportedPosts, exported)
+	}
+
+	messages, err := service.chatRepository.ListMessagesByAuthor(user.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	friends, err := service.userRepository.ListFriendUsernames(user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	genres, err := service.userRepository.ListLikedGenres(user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	playlists, err := service.playlistRepository.GetPlaylistsByUsername(ctx, user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeExportArchive([]exportFile{
+		{"profile.json", exportedProfile{
+			Id:                user.UserId,
+			Username:          user.Username,
+			Fullname:          user.Fullname,
+			Email:             user.Email,
+			Telephone:         user.Telephone,
+			Bio:               user.Bio,
+			AvatarUrl:         user.AvatarUrl,
+			BirthDate:         user.Birth_date,
+			RegisterDate:      user.Register_date,
+			ProfileVisibility: user.ProfileVisibility,
+			MessagePermission: user.MessagePermission,
+		}},
+		{"posts.json", exportedPosts},
+		{"messages.json", messages},
+		{"friends.json", friends},
+		{"liked_genres.json", genres},
+		{"playlists.json", playlists},
+	})
+}
+
+type exportFile struct {
+	name    string
+	content any
+}
+
+// writeExportArchive bundles the files, encoded as indented JSON, in a ZIP.
+func writeExportArchive(files []exportFile) ([]byte, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// newExportKey returns an unguessable key, since the key is all it takes to
+// read the archive from the storage.
+func newExportKey(exportId int32) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("export-%d-%s.zip", exportId, hex.EncodeToString(random)), nil
+}
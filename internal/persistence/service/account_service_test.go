@@ -0,0 +1,48 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"symphony-api/internal/persistence/storage"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteExportArchive(t *testing.T) {
+	data, err := writeExportArchive([]exportFile{
+		{"friends.json", []string{"mary", "bob"}},
+		{"liked_genres.json", []string{"jazz"}},
+	})
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, archive.File, 2)
+	assert.Equal(t, "friends.json", archive.File[0].Name)
+	assert.Equal(t, "liked_genres.json", archive.File[1].Name)
+
+	file, err := archive.File[0].Open()
+	require.NoError(t, err)
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+
+	var friends []string
+	require.NoError(t, json.Unmarshal(content, &friends))
+	assert.Equal(t, []string{"mary", "bob"}, friends)
+}
+
+func TestNewExportKey(t *testing.T) {
+	key1, err := newExportKey(7)
+	require.NoError(t, err)
+	key2, err := newExportKey(7)
+	require.NoError(t, err)
+
+	assert.NoError(t, storage.ValidateKey(key1))
+	assert.Regexp(t, `^export-7-[0-9a-f]{32}\.zip$`, key1)
+	assert.NotEqual(t, key1, key2)
+}
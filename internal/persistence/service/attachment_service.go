@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"symphony-api/internal/persistence/model"
+	mongo_repository "symphony-api/internal/persistence/repository/mongo"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AttachmentService struct {
+	songRepository     *mongo_repository.SongRepository
+	artistRepository   *mongo_repository.ArtistRepository
+	playlistRepository *mongo_repository.PlaylistRepository
+}
+
+func NewAttachmentService(
+	songRepository *mongo_repository.SongRepository,
+	artistRepository *mongo_repository.ArtistRepository,
+	playlistRepository *mongo_repository.PlaylistRepository,
+) *AttachmentService {
+	return &AttachmentService{
+		songRepository:     songRepository,
+		artistRepository:   artistRepository,
+		playlistRepository: playlistRepository,
+	}
+}
+
+// Validate checks that the entity referenced by the attachment exists in Mongo.
+func (service *AttachmentService) Validate(ctx context.Context, attachment *model.Attachment) error {
+	var err error
+
+	switch attachment.Type {
+	case model.ATTACHMENT_SONG:
+		_, err = service.songRepository.GetSongByID(ctx, attachment.ObjectId())
+	case model.ATTACHMENT_ARTIST:
+		_, err = service.artistRepository.GetArtistByID(ctx, attachment.ObjectId())
+	case model.ATTACHMENT_PLAYLIST:
+		_, err = service.playlistRepository.GetPlaylistByID(ctx, attachment.ObjectId())
+	default:
+		return errors.New("invalid attachment type: " + attachment.Type)
+	}
+
+	if err != nil {
+		return errors.New(attachment.Type + " does not exist: " + attachment.Id)
+	}
+
+	return nil
+}
+
+// Hydrate fills the summary fields (title, artist name and cover) of every
+// attachment. It issues at most one query per collection, no matter how many
+// attachments are given. Attachments whose entity no longer exists are left
+// without summary.
+func (service *AttachmentService) Hydrate(ctx context.Context, attachments []*model.Attachment) error {
+	idsByType := map[string][]primitive.ObjectID{}
+	for _, attachment := range attachments {
+		if attachment != nil {
+			idsByType[attachment.Type] = append(idsByType[attachment.Type], attachment.ObjectId())
+		}
+	}
+
+	summaries := map[string]*model.Attachment{}
+
+	if ids := idsByType[model.ATTACHMENT_PLAYLIST]; len(ids) > 0 {
+		playlists, err := service.playlistRepository.GetPlaylistsByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, playlist := range playlists {
+			summaries[attachmentKey(model.ATTACHMENT_PLAYLIST, playlist.ID)] = &model.Attachment{
+				Title:    playlist.Name,
+				CoverUrl: playlist.ImageURL,
+			}
+		}
+	}
+
+	songsArtist := map[primitive.ObjectID][]*model.Attachment{}
+	if ids := idsByType[model.ATTACHMENT_SONG]; len(ids) > 0 {
+		songs, err := service.songRepository.GetSongsByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, song := range songs {
+			summary := &model.Attachment{Title: song.Title}
+			summaries[attachmentKey(model.ATTACHMENT_SONG, song.ID)] = summary
+			if !song.ArtistID.IsZero() {
+				songsArtist[song.ArtistID] = append(songsArtist[song.ArtistID], summary)
+			}
+		}
+	}
+
+	// Songs take their artist name and cover from the artist, so both the
+	// artist attachments and the artists of the songs are fetched together.
+	artistIds := idsByType[model.ATTACHMENT_ARTIST]
+	for artistId := range songsArtist {
+		artistIds = append(artistIds, artistId)
+	}
+
+	if len(artistIds) > 0 {
+		artists, err := service.artistRepository.GetArtistsByIDs(ctx, artistIds)
+		if err != nil {
+			return err
+		}
+		for _, artist := range artists {
+			summaries[attachmentKey(model.ATTACHMENT_ARTIST, artist.ID)] = &model.Attachment{
+				Title:      artist.Name,
+				ArtistName: artist.Name,
+				CoverUrl:   artist.ImageURL,
+			}
+			for _, summary := range songsArtist[artist.ID] {
+				summary.ArtistName = artist.Name
+				summary.CoverUrl = artist.ImageURL
+			}
+		}
+	}
+
+	for _, attachment := range attachments {
+		if attachment == nil {
+			continue
+		}
+		summary, ok := summaries[attachmentKey(attachment.Type, attachment.ObjectId())]
+		if ok {
+			attachment.Title = summary.Title
+			attachment.ArtistName = summary.ArtistName
+			attachment.CoverUrl = summary.CoverUrl
+		}
+	}
+
+	return nil
+}
+
+func attachmentKey(attachmentType string, id primitive.ObjectID) string {
+	return attachmentType + ":" + id.Hex()
+}
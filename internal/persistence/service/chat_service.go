@@ -1,23 +1,43 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"symphony-api/internal/persistence/model"
 	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/realtime"
 )
 
 type ChatService struct {
 	chatRepository *repository.ChatRepository
 	userRepository *repository.UserRepository
+	privacyService *PrivacyService
+	attachmentService *AttachmentService
+	eventService *EventService
+	notificationService *NotificationService
+	hub *realtime.Hub
 }
 
 func NewChatService(
 	chatRepository *repository.ChatRepository,
 	userRepository *repository.UserRepository,
+	privacyService *PrivacyService,
+	attachmentService *AttachmentService,
+	eventService *EventService,
+	notificationService *NotificationService,
+	hub *realtime.Hub,
 ) *ChatService {
 	return &ChatService{
 		chatRepository: chatRepository,
 		userRepository: userRepository,
+		privacyService: privacyService,
+		attachmentService: attachmentService,
+		eventService: eventService,
+		notificationService: notificationService,
+		hub: hub,
 	}
 }
 
@@ -49,6 +69,10 @@ func (service *ChatService) CreateChat(username1, username2 string) (*model.Chat
         return nil, errors.New("user does not exist: " + username2)
     }
 
+    if err := service.privacyService.CheckCanMessage(user1, user2); err != nil {
+        return nil, err
+    }
+
     existingChat, err := service.chatRepository.FindChatByUsers(user1.UserId, user2.UserId)
     if err != nil {
         return nil, err
@@ -77,18 +101,293 @@ func (service *ChatService) CreateChat(username1, username2 string) (*model.Chat
     return createdChat, nil
 }
 
-func (service *ChatService) ListUsersFromChat(chatId int32) ([]*model.User, error) {
-	chat, err := service.chatRepository.GetByChatId(chatId)
+// CreateGroupChat creates a named chat between the creator, who becomes its
+// admin, and the given users. The creator must be allowed to message each of
+// them, and none of the participants can have blocked another.
+func (service *ChatService) CreateGroupChat(creatorUsername string, name string, usernames []string) (*model.Chat, error) {
+	name, err := model.ValidateChatName(name)
 	if err != nil {
-		return nil, errors.New("chat does not exist")
+		return nil, err
+	}
+
+	participants := []string{creatorUsername}
+	seen := map[string]bool{creatorUsername: true}
+	for _, username := range usernames {
+		if !seen[username] {
+			seen[username] = true
+			participants = append(participants, username)
+		}
+	}
+
+	if len(participants) < 2 {
+		return nil, errors.New("group chats need at least one other participant")
+	}
+	if len(participants) > model.MAX_GROUP_CHAT_SIZE {
+		return nil, fmt.Errorf("group chats can have at most %d participants", model.MAX_GROUP_CHAT_SIZE)
+	}
+
+	users, missing, err := service.userRepository.GetByUsernames(participants)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, errors.New("user does not exist: " + missing[0])
+	}
+
+	creator := users[0]
+	for _, user := range users[1:] {
+		if err := service.privacyService.CheckCanMessage(creator, user); err != nil {
+			return nil, fmt.Errorf("%s: %w", user.Username, err)
+		}
 	}
 
-	users, err := service.chatRepository.ListUsersFromChat(chat)
+	blocked, err := service.userRepository.HasBlockAmong(participants)
 	if err != nil {
 		return nil, err
 	}
+	if blocked {
+		return nil, errors.New("some of the participants blocked each other")
+	}
 
-	return users, nil
+	memberIds := make([]int32, 0, len(users)-1)
+	for _, user := range users[1:] {
+		memberIds = append(memberIds, user.UserId)
+	}
+
+	return service.chatRepository.CreateGroup(name, creator.UserId, memberIds)
+}
+
+// ListMembers returns the participants of a chat with their roles.
+func (service *ChatService) ListMembers(chatId int32) ([]*model.ChatMember, error) {
+	if _, err := service.chatRepository.GetByChatId(chatId); err != nil {
+		return nil, errors.New("chat does not exist")
+	}
+
+	return service.chatRepository.ListMembers(chatId)
+}
+
+// AddParticipant adds a user to a group chat. Only admins can add
+// participants, and only users they are allowed to message who have no block
+// with any participant.
+func (service *ChatService) AddParticipant(chatId int32, actorUsername string, username string) error {
+	_, members, actor, err := service.getGroupAsAdmin(chatId, actorUsername)
+	if err != nil {
+		return err
+	}
+
+	if model.FindChatMember(members, username) != nil {
+		return errors.New("user is already part of the chat")
+	}
+	if len(members) >= model.MAX_GROUP_CHAT_SIZE {
+		return fmt.Errorf("group chats can have at most %d participants", model.MAX_GROUP_CHAT_SIZE)
+	}
+
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return errors.New("user does not exist: " + username)
+	}
+	if err := service.privacyService.CheckCanMessage(actor.User, user); err != nil {
+		return err
+	}
+
+	memberUsers := make([]*model.User, 0, len(members))
+	for _, member := range members {
+		memberUsers = append(memberUsers, member.User)
+	}
+	if err := service.checkNoBlocks(user, memberUsers); err != nil {
+		return err
+	}
+
+	if err := service.chatRepository.AddMember(chatId, user.UserId, model.CHAT_ROLE_MEMBER); err != nil {
+		return err
+	}
+
+	service.publish(chatId, realtime.EVENT_CHAT_MEMBER_ADDED, newChatMemberEvent(chatId, username))
+
+	return nil
+}
+
+// RemoveParticipant takes a user out of a group chat. Only admins can remove
+// participants; admins leave with LeaveChat instead of removing themselves.
+func (service *ChatService) RemoveParticipant(chatId int32, actorUsername string, username string) error {
+	if actorUsername == username {
+		return errors.New("use leave to exit a chat")
+	}
+
+	_, members, _, err := service.getGroupAsAdmin(chatId, actorUsername)
+	if err != nil {
+		return err
+	}
+
+	member := model.FindChatMember(members, username)
+	if member == nil {
+		return errors.New("user is not part of the chat")
+	}
+
+	if _, err := service.chatRepository.RemoveMember(chatId, member.User.UserId); err != nil {
+		return err
+	}
+
+	service.publish(chatId, realtime.EVENT_CHAT_MEMBER_REMOVED, newChatMemberEvent(chatId, username))
+
+	return nil
+}
+
+// SetRole makes a participant of a group chat an admin or a plain member.
+// Only admins can change roles, and a group always keeps at least one admin.
+func (service *ChatService) SetRole(chatId int32, actorUsername string, username string, role string) error {
+	if err := model.ValidateChatRole(role); err != nil {
+		return err
+	}
+
+	_, members, _, err := service.getGroupAsAdmin(chatId, actorUsername)
+	if err != nil {
+		return err
+	}
+
+	member := model.FindChatMember(members, username)
+	if member == nil {
+		return errors.New("user is not part of the chat")
+	}
+	if member.Role == role {
+		return nil
+	}
+	if member.IsAdmin() && countChatAdmins(members) == 1 {
+		return errors.New("a group chat must keep at least one admin")
+	}
+
+	return service.chatRepository.SetMemberRole(chatId, member.User.UserId, role)
+}
+
+// RenameGroup changes the name of a group chat. Only admins can rename it.
+func (service *ChatService) RenameGroup(chatId int32, actorUsername string, name string) error {
+	name, err := model.ValidateChatName(name)
+	if err != nil {
+		return err
+	}
+
+	if _, _, _, err := service.getGroupAsAdmin(chatId, actorUsername); err != nil {
+		return err
+	}
+
+	if err := service.chatRepository.Rename(chatId, name); err != nil {
+		return err
+	}
+
+	service.publish(chatId, realtime.EVENT_CHAT_RENAMED, map[string]any{"chat_id": chatId, "name": name})
+
+	return nil
+}
+
+// LeaveChat takes the user out of a group chat. When the last admin leaves,
+// the participant that joined first becomes admin; when the last participant
+// leaves, the chat is deleted.
+func (service *ChatService) LeaveChat(chatId int32, username string) error {
+	chat, err := service.chatRepository.GetByChatId(chatId)
+	if err != nil {
+		return errors.New("chat does not exist")
+	}
+	if !chat.IsGroup {
+		return errors.New("only group chats can be left")
+	}
+
+	members, err := service.chatRepository.ListMembers(chatId)
+	if err != nil {
+		return err
+	}
+
+	member := model.FindChatMember(members, username)
+	if member == nil {
+		return errors.New("user is not part of the chat")
+	}
+
+	if len(members) == 1 {
+		return service.chatRepository.Delete(chatId)
+	}
+
+	if _, err := service.chatRepository.RemoveMember(chatId, member.User.UserId); err != nil {
+		return err
+	}
+
+	if member.IsAdmin() && countChatAdmins(members) == 1 {
+		for _, successor := range members {
+			if successor != member {
+				if err := service.chatRepository.SetMemberRole(chatId, successor.User.UserId, model.CHAT_ROLE_ADMIN); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	service.publish(chatId, realtime.EVENT_CHAT_MEMBER_REMOVED, newChatMemberEvent(chatId, username))
+
+	return nil
+}
+
+// getGroupAsAdmin loads a group chat and its members, failing unless the
+// actor is one of its admins.
+func (service *ChatService) getGroupAsAdmin(chatId int32, actorUsername string) (*model.Chat, []*model.ChatMember, *model.ChatMember, error) {
+	chat, err := service.chatRepository.GetByChatId(chatId)
+	if err != nil {
+		return nil, nil, nil, errors.New("chat does not exist")
+	}
+	if !chat.IsGroup {
+		return nil, nil, nil, errors.New("participants can only be managed in group chats")
+	}
+
+	members, err := service.chatRepository.ListMembers(chatId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	actor := model.FindChatMember(members, actorUsername)
+	if actor == nil || !actor.IsAdmin() {
+		return nil, nil, nil, errors.New("only admins of the chat can do this")
+	}
+
+	return chat, members, actor, nil
+}
+
+// checkNoBlocks fails when the user blocked or was blocked by any of the
+// others.
+func (service *ChatService) checkNoBlocks(user *model.User, others []*model.User) error {
+	related, err := service.userRepository.ListBlockRelatedUsernames(user.Username)
+	if err != nil {
+		return err
+	}
+	if len(related) == 0 {
+		return nil
+	}
+
+	blocked := make(map[string]bool, len(related))
+	for _, username := range related {
+		blocked[username] = true
+	}
+	for _, other := range others {
+		if blocked[other.Username] {
+			return errors.New("a participant of the chat blocked you or was blocked by you")
+		}
+	}
+
+	return nil
+}
+
+func countChatAdmins(members []*model.ChatMember) int {
+	admins := 0
+	for _, member := range members {
+		if member.IsAdmin() {
+			admins++
+		}
+	}
+	return admins
+}
+
+func newChatMemberEvent(chatId int32, username string) map[string]any {
+	return map[string]any{
+		"chat_id": chatId,
+		"username": username,
+	}
 }
 
 func (service *ChatService) ListChatsByUser(username string) ([]*model.Chat, error) {
@@ -105,15 +404,338 @@ func (service *ChatService) ListChatsByUser(username string) ([]*model.Chat, err
 	return chats, nil
 }
 
-func (service *ChatService) AddMessageToChatAndReturn(chatId int32, authorId int32, message string) (*model.ChatMessage, error) {
+// AddMessageToChatAndReturn sends a message to a chat. The attachment, when
+// given, must reference a song, artist or playlist that exists.
+func (service *ChatService) AddMessageToChatAndReturn(chatId int32, authorId int32, message string, attachment *model.Attachment) (*model.ChatMessage, error) {
     chat, err := service.chatRepository.GetByChatId(chatId)
     if err != nil || chat == nil {
         return nil, errors.New("chat does not exist")
     }
-    return service.chatRepository.AddMessageToChatAndReturn(chatId, authorId, message)
+
+    participants, err := service.chatRepository.ListUsersFromChat(chat)
+    if err != nil {
+        return nil, err
+    }
+
+    var author *model.User
+    for _, participant := range participants {
+        if participant.UserId == authorId {
+            author = participant
+        }
+    }
+    if author == nil {
+        return nil, errors.New("author is not part of the chat")
+    }
+
+    // Group participants agreed to the group when they were added, so the
+    // message permissions are only checked in direct chats. Blocks made since
+    // then still keep the message from being sent.
+    if chat.IsGroup {
+        if err := service.checkNoBlocks(author, participants); err != nil {
+            return nil, err
+        }
+    } else {
+        for _, participant := range participants {
+            if participant.UserId == authorId {
+                continue
+            }
+            if err := service.privacyService.CheckCanMessage(author, participant); err != nil {
+                return nil, err
+            }
+        }
+    }
+
+    if attachment != nil {
+        if err := service.attachmentService.Validate(context.Background(), attachment); err != nil {
+            return nil, err
+        }
+    }
+
+    created, err := service.chatRepository.AddMessageToChatAndReturn(chatId, authorId, message, attachment)
+    if err != nil {
+        return nil, err
+    }
+    service.HydrateAttachments([]*model.ChatMessage{created})
+
+    service.publish(chatId, realtime.EVENT_CHAT_MESSAGE, created)
+
+    recipients := make([]*model.User, 0, len(participants))
+    for _, participant := range participants {
+        if participant.UserId != authorId {
+            recipients = append(recipients, participant)
+        }
+    }
+    service.eventService.PublishToUsers(recipients, realtime.EVENT_CHAT_MESSAGE, created)
+    service.notificationService.Notify(recipients, model.NOTIFICATION_CHAT_MESSAGE, author, chatId)
+
+    return created, nil
+}
+
+// MarkRead marks the chat as read by the user up to the given message, or up
+// to its newest message when messageId is 0, and returns the resulting read
+// marker. The other participants are notified so they can show the receipt.
+func (service *ChatService) MarkRead(chatId int32, username string, messageId int32) (int32, error) {
+	if messageId < 0 {
+		return 0, errors.New("invalid message id")
+	}
+
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return 0, errors.New("user does not exist")
+	}
+
+	isParticipant, err := service.chatRepository.IsParticipant(chatId, user.UserId)
+	if err != nil {
+		return 0, err
+	}
+	if !isParticipant {
+		return 0, errors.New("user is not part of the chat")
+	}
+
+	lastRead, err := service.chatRepository.MarkRead(chatId, user.UserId, messageId)
+	if err != nil {
+		return 0, err
+	}
+
+	if lastRead > 0 {
+		service.publish(chatId, realtime.EVENT_CHAT_READ, map[string]any{
+			"chat_id": chatId,
+			"username": username,
+			"last_read_message_id": lastRead,
+		})
+	}
+
+	return lastRead, nil
 }
 
-func (service *ChatService) ListChatMessages(chatId int32, limit int32) ([]*model.ChatMessage, error) {
+// EditMessage replaces the text of a message written by the user. The
+// previous text is kept in the history of the message.
+func (service *ChatService) EditMessage(messageId int32, username string, text string) (*model.ChatMessage, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("message can not be empty")
+	}
+
+	message, user, err := service.getMessageAsParticipant(messageId, username)
+	if err != nil {
+		return nil, err
+	}
+	if message.AuthorId != user.UserId {
+		return nil, errors.New("only the author can edit a message")
+	}
+	if message.IsDeleted() {
+		return nil, errors.New("message was deleted")
+	}
+	if message.Message == text {
+		return message, nil
+	}
+
+	edited, err := service.chatRepository.EditMessage(messageId, text)
+	if err != nil {
+		return nil, err
+	}
+	if edited == nil {
+		return nil, errors.New("message was deleted")
+	}
+	service.HydrateAttachments([]*model.ChatMessage{edited})
+
+	service.publish(edited.ChatId, realtime.EVENT_CHAT_MESSAGE_EDITED, edited)
+
+	return edited, nil
+}
+
+// DeleteMessage deletes a message written by the user for every participant,
+// leaving a tombstone in its place.
+func (service *ChatService) DeleteMessage(messageId int32, username string) error {
+	message, user, err := service.getMessageAsParticipant(messageId, username)
+	if err != nil {
+		return err
+	}
+	if message.AuthorId != user.UserId {
+		return errors.New("only the author can delete a message")
+	}
+	if message.IsDeleted() {
+		return nil
+	}
+
+	deleted, err := service.chatRepository.DeleteMessage(messageId)
+	if err != nil {
+		return err
+	}
+
+	if deleted != nil {
+		service.publish(deleted.ChatId, realtime.EVENT_CHAT_MESSAGE_DELETED, map[string]any{
+			"chat_id": deleted.ChatId,
+			"message_id": deleted.MessageId,
+			"deleted_at": deleted.DeletedAt,
+		})
+	}
+
+	return nil
+}
+
+// ListMessageEdits returns the previous versions of a message, oldest first.
+func (service *ChatService) ListMessageEdits(messageId int32, username string) ([]*model.ChatMessageEdit, error) {
+	if _, _, err := service.getMessageAsParticipant(messageId, username); err != nil {
+		return nil, err
+	}
+
+	return service.chatRepository.ListMessageEdits(messageId)
+}
+
+// React adds or removes a reaction of the user to a message and returns the
+// reactions the message has afterwards.
+func (service *ChatService) React(messageId int32, username string, emoji string, add bool) ([]*model.ChatReaction, error) {
+	emoji, err := model.ValidateReaction(emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	message, user, err := service.getMessageAsParticipant(messageId, username)
+	if err != nil {
+		return nil, err
+	}
+	if message.IsDeleted() {
+		return nil, errors.New("message was deleted")
+	}
+
+	var changed bool
+	eventType := realtime.EVENT_CHAT_REACTION_ADDED
+	if add {
+		changed, err = service.chatRepository.AddReaction(messageId, user.UserId, emoji)
+	} else {
+		changed, err = service.chatRepository.RemoveReaction(messageId, user.UserId, emoji)
+		eventType = realtime.EVENT_CHAT_REACTION_REMOVED
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		service.publish(message.ChatId, eventType, map[string]any{
+			"chat_id": message.ChatId,
+			"message_id": messageId,
+			"username": username,
+			"emoji": emoji,
+		})
+	}
+
+	return service.chatRepository.ListReactions([]int32{messageId})
+}
+
+// ListReactions returns the reactions to the given messages, by message.
+func (service *ChatService) ListReactions(messages []*model.ChatMessage) (map[int32][]*model.ChatReaction, error) {
+	messageIds := make([]int32, 0, len(messages))
+	for _, message := range messages {
+		messageIds = append(messageIds, message.MessageId)
+	}
+
+	reactions, err := service.chatRepository.ListReactions(messageIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.GroupReactionsByMessage(reactions), nil
+}
+
+// getMessageAsParticipant returns a visible message along with the user, as
+// long as the user takes part in its chat.
+func (service *ChatService) getMessageAsParticipant(messageId int32, username string) (*model.ChatMessage, *model.User, error) {
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, nil, errors.New("user does not exist")
+	}
+
+	message, err := service.chatRepository.GetMessage(messageId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if message == nil {
+		return nil, nil, errors.New("message does not exist")
+	}
+
+	isParticipant, err := service.chatRepository.IsParticipant(message.ChatId, user.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isParticipant {
+		return nil, nil, errors.New("user is not part of the chat")
+	}
+
+	return message, user, nil
+}
+
+// GetInbox returns a page of the chats of the user, most recently active first.
+func (service *ChatService) GetInbox(username string, limit int32, offset int32) ([]*model.ChatInboxEntry, error) {
+	if limit <= 0 || offset < 0 {
+		return nil, errors.New("limit must be greater than zero and offset can not be negative")
+	}
+
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	return service.chatRepository.GetInbox(user.UserId, limit, offset)
+}
+
+// CountUnread returns how many unread messages the user has in each chat.
+func (service *ChatService) CountUnread(username string) (map[int32]int64, error) {
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	return service.chatRepository.CountUnreadByUser(user.UserId)
+}
+
+// ListReadStates returns how far each participant of the chat has read. Only
+// participants may see it.
+func (service *ChatService) ListReadStates(chatId int32, username string) ([]*model.ChatReadState, error) {
+	if err := service.checkParticipant(chatId, username); err != nil {
+		return nil, err
+	}
+
+	return service.chatRepository.ListReadStates(chatId)
+}
+
+// checkParticipant fails unless the user takes part in the chat.
+func (service *ChatService) checkParticipant(chatId int32, username string) error {
+	isParticipant, err := service.IsParticipant(chatId, username)
+	if err != nil {
+		return err
+	}
+	if !isParticipant {
+		return errors.New("user is not part of the chat")
+	}
+
+	return nil
+}
+
+// IsParticipant tells whether the user takes part in the chat.
+func (service *ChatService) IsParticipant(chatId int32, username string) (bool, error) {
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return false, errors.New("user does not exist")
+	}
+
+	return service.chatRepository.IsParticipant(chatId, user.UserId)
+}
+
+// publish notifies the subscribers of the chat. The change is already stored,
+// so a failure is only logged and clients fall back to polling.
+func (service *ChatService) publish(chatId int32, eventType string, data any) {
+	event, err := realtime.NewEvent(realtime.ChatTopic(chatId), eventType, data)
+	if err == nil {
+		err = service.hub.Publish(context.Background(), event)
+	}
+	if err != nil {
+		log.Printf("Error publishing %s event of chat %d: %v", eventType, chatId, err)
+	}
+}
+
+// ListChatMessages returns a page of the history of a chat. Only participants
+// may read it.
+func (service *ChatService) ListChatMessages(chatId int32, username string, query *model.ChatHistoryQuery) (*model.ChatHistoryPage, error) {
 	chat, err := service.chatRepository.GetByChatId(chatId)
 	if err != nil {
 		return nil, errors.New("chat does not exist")
@@ -123,14 +745,81 @@ func (service *ChatService) ListChatMessages(chatId int32, limit int32) ([]*mode
 		return nil, errors.New("chat not found")
 	}
 
-	if limit <= 0 {
-		return nil, errors.New("limit must be greater than zero")
+	if err := service.checkParticipant(chatId, username); err != nil {
+		return nil, err
+	}
+
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	page, err := service.chatRepository.ListMessagesFromChat(chatId, query)
+	if err != nil {
+		return nil, err
+	}
+	service.HydrateAttachments(page.Messages)
+
+	return page, nil
+}
+
+// SearchMessages searches the messages of the chats the user participates in,
+// or only those of one chat of theirs when chatId is not 0.
+func (service *ChatService) SearchMessages(username string, text string, chatId int32, limit int32) ([]*model.ChatMessageSearchResult, error) {
+	text, err := model.NormalizeSearchQuery(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > model.MAX_CHAT_HISTORY_LIMIT {
+		return nil, errors.New("limit must be between 1 and 100")
+	}
+
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	if chatId != 0 {
+		isParticipant, err := service.chatRepository.IsParticipant(chatId, user.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if !isParticipant {
+			return nil, errors.New("user is not part of the chat")
+		}
 	}
 
-	messages, err := service.chatRepository.ListMessagesFromChat(chatId, limit)
+	results, err := service.chatRepository.SearchMessages(user.UserId, text, chatId, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	return messages, nil
-}
\ No newline at end of file
+	messages := make([]*model.ChatMessage, 0, len(results))
+	for _, result := range results {
+		messages = append(messages, result.Message)
+	}
+	service.HydrateAttachments(messages)
+
+	return results, nil
+}
+
+// HydrateAttachments fills the summary of the attachments of all messages in
+// a single batch. A failure here only degrades the response, so it is logged
+// instead of failing the request.
+func (service *ChatService) HydrateAttachments(messages []*model.ChatMessage) {
+	attachments := make([]*model.Attachment, 0)
+	for _, message := range messages {
+		if message.Attachment != nil {
+			attachments = append(attachments, message.Attachment)
+		}
+	}
+
+	if len(attachments) == 0 {
+		return
+	}
+
+	err := service.attachmentService.Hydrate(context.Background(), attachments)
+	if err != nil {
+		log.Printf("Error hydrating chat message attachments: %v", err)
+	}
+}
@@ -2,22 +2,30 @@ package service
 
 import (
 	"errors"
+	"log"
 	"symphony-api/internal/persistence/model"
 	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/webhook"
 )
 
 type CommunityService struct {
 	communityRepository *repository.CommunityRepository
 	userRepository *repository.UserRepository
+	notificationService *NotificationService
+	webhookService *WebhookService
 }
 
 func NewCommunityService(
 	communityRepository *repository.CommunityRepository,
 	userRepository *repository.UserRepository,
+	notificationService *NotificationService,
+	webhookService *WebhookService,
 	) *CommunityService {
 	return &CommunityService{
 		communityRepository: communityRepository,
 		userRepository: userRepository,
+		notificationService: notificationService,
+		webhookService: webhookService,
 	}
 }
 
@@ -35,8 +43,45 @@ func (service *CommunityService) AddUserToCommunity(username string, communityNa
 	}
 
 	err = service.communityRepository.AddUserToCommunity(user, community)
+	if err != nil {
+		return err
+	}
+
+	// Partners only learn about members anyone can see.
+	if model.CanSeeProfile(user.ProfileVisibility, false, false) {
+		service.webhookService.Publish(webhook.EVENT_COMMUNITY_MEMBER_JOINED, map[string]any{
+			"community_id": community.Id,
+			"community_name": community.CommunityName,
+			"username": user.Username,
+		})
+	}
+
+	service.notifyFriendsInCommunity(user, community)
+
+	return nil
+}
 
-	return err
+// notifyFriendsInCommunity tells the friends of the user who are already
+// members that they joined. Other members are not told, as large communities
+// would flood them.
+func (service *CommunityService) notifyFriendsInCommunity(user *model.User, community *model.Community) {
+	friends, err := service.userRepository.ListFriendUsernames(user.Username)
+	if err != nil {
+		log.Printf("Error listing friends of user %d: %v", user.UserId, err)
+		return
+	}
+	if len(friends) == 0 {
+		return
+	}
+
+	members, err := service.communityRepository.ListMembersAmong(community, friends)
+	if err != nil {
+		log.Printf("Error listing members of community %d: %v", community.Id, err)
+		return
+	}
+	if len(members) > 0 {
+		service.notificationService.Notify(members, model.NOTIFICATION_COMMUNITY_JOINED, user, community.Id)
+	}
 }
 
 func (service *CommunityService) ListUsersFromCommunity(communityName string) ([]*model.User, error) {
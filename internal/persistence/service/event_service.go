@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/realtime"
+	"time"
+)
+
+// EventService is how the other services tell a user something happened.
+// Events are appended to the event log of the user, so a stream that was
+// disconnected can resume, and pushed to the streams open at the moment.
+type EventService struct {
+	eventRepository *repository.UserEventRepository
+	hub             *realtime.Hub
+}
+
+func NewEventService(eventRepository *repository.UserEventRepository, hub *realtime.Hub) *EventService {
+	return &EventService{
+		eventRepository: eventRepository,
+		hub:             hub,
+	}
+}
+
+// Publish sends an event to a user. The change that caused it is already
+// stored, so a failure is only logged.
+func (service *EventService) Publish(user *model.User, eventType string, data any) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Error encoding %s event for user %d: %v", eventType, user.UserId, err)
+		return
+	}
+
+	stored, err := service.eventRepository.Append(user.UserId, eventType, encoded)
+	if err != nil {
+		log.Printf("Error storing %s event for user %d: %v", eventType, user.UserId, err)
+		return
+	}
+
+	err = service.hub.Publish(context.Background(), toRealtimeEvent(stored))
+	if err != nil {
+		log.Printf("Error publishing %s event for user %d: %v", eventType, user.UserId, err)
+	}
+}
+
+// PublishToUsers sends the same event to several users.
+func (service *EventService) PublishToUsers(users []*model.User, eventType string, data any) {
+	for _, user := range users {
+		service.Publish(user, eventType, data)
+	}
+}
+
+// Replay returns the events of the user newer than lastEventId, oldest first.
+// Streams that did not get any event yet start with the live ones. It
+// reports false, returning no events, when the stream can not resume: the
+// last event was pruned from the log or more than USER_EVENT_MAX_REPLAY
+// followed it.
+func (service *EventService) Replay(userId int32, lastEventId int64) ([]*realtime.Event, bool, error) {
+	events := make([]*realtime.Event, 0)
+	if lastEventId <= 0 {
+		return events, true, nil
+	}
+
+	exists, err := service.eventRepository.Exists(userId, lastEventId)
+	if err != nil {
+		return nil, false, err
+	}
+	if !exists {
+		return events, false, nil
+	}
+
+	stored, err := service.eventRepository.ListAfter(userId, lastEventId, model.USER_EVENT_MAX_REPLAY+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(stored) > model.USER_EVENT_MAX_REPLAY {
+		return events, false, nil
+	}
+
+	for _, event := range stored {
+		events = append(events, toRealtimeEvent(event))
+	}
+
+	return events, true, nil
+}
+
+// RunRetention prunes the events older than retention from the log every
+// interval until ctx is done.
+func (service *EventService) RunRetention(ctx context.Context, retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(max(interval, time.Minute))
+	defer ticker.Stop()
+
+	for {
+		service.pruneEvents(retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (service *EventService) pruneEvents(retention time.Duration) {
+	for {
+		pruned, err := service.eventRepository.PruneOlderThan(retention, model.USER_EVENT_PRUNE_BATCH)
+		if err != nil {
+			log.Printf("Error pruning user events: %v", err)
+			return
+		}
+		if pruned < model.USER_EVENT_PRUNE_BATCH {
+			return
+		}
+	}
+}
+
+func toRealtimeEvent(event *model.UserEvent) *realtime.Event {
+	return &realtime.Event{
+		Id:    event.EventId,
+		Topic: realtime.UserTopic(event.UserId),
+		Type:  event.Type,
+		Data:  event.Data,
+	}
+}
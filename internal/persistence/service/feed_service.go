@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+)
+
+type FeedService struct {
+	postRepository *repository.PostRepository
+	userRepository *repository.UserRepository
+}
+
+func NewFeedService(
+	postRepository *repository.PostRepository,
+	userRepository *repository.UserRepository,
+) *FeedService {
+	return &FeedService{
+		postRepository: postRepository,
+		userRepository: userRepository,
+	}
+}
+
+// GetFeed returns the newest posts of the user, their friends and the users
+// they follow, together with the posts attached to the artists they follow.
+// Posts of users blocked by or blocking the user are left out, and so are the
+// posts of profiles the user is not allowed to see.
+func (service *FeedService) GetFeed(username string, limit int32) ([]*model.Post, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be greater than zero")
+	}
+
+	if _, err := service.userRepository.GetByUsername(username); err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	friends, err := service.userRepository.ListFriendUsernames(username)
+	if err != nil {
+		return nil, err
+	}
+
+	following, err := service.userRepository.ListFollowingUsernames(username)
+	if err != nil {
+		return nil, err
+	}
+
+	artistIds, err := service.userRepository.ListFollowedArtistIds(username)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := service.userRepository.ListBlockRelatedUsernames(username)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := append([]string{username}, friends...)
+	authors = append(authors, following...)
+
+	return service.postRepository.GetFeed(username, friends, authors, artistIds, blocked, limit)
+}
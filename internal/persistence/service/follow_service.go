@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	mongo_repository "symphony-api/internal/persistence/repository/mongo"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FollowService struct {
+	userRepository   *repository.UserRepository
+	artistRepository *mongo_repository.ArtistRepository
+}
+
+func NewFollowService(
+	userRepository *repository.UserRepository,
+	artistRepository *mongo_repository.ArtistRepository,
+) *FollowService {
+	return &FollowService{
+		userRepository:   userRepository,
+		artistRepository: artistRepository,
+	}
+}
+
+func (service *FollowService) FollowUser(follower string, followee string) error {
+	if follower == followee {
+		return errors.New("users cannot follow themselves")
+	}
+
+	// FollowUser refuses to follow across a block, which is reported as if the
+	// user did not exist.
+	followed, err := service.userRepository.FollowUser(follower, followee)
+	if err != nil {
+		return err
+	}
+	if !followed {
+		return errors.New("user does not exist")
+	}
+
+	return nil
+}
+
+func (service *FollowService) UnfollowUser(follower string, followee string) error {
+	unfollowed, err := service.userRepository.UnfollowUser(follower, followee)
+	if err != nil {
+		return err
+	}
+	if !unfollowed {
+		return errors.New("user is not being followed")
+	}
+
+	return nil
+}
+
+func (service *FollowService) FollowArtist(ctx context.Context, follower string, artistId string) error {
+	id, err := primitive.ObjectIDFromHex(artistId)
+	if err != nil {
+		return errors.New("invalid artist id: " + artistId)
+	}
+
+	if _, err := service.artistRepository.GetArtistByID(ctx, id); err != nil {
+		return errors.New("artist does not exist")
+	}
+
+	followed, err := service.userRepository.FollowArtist(follower, artistId)
+	if err != nil {
+		return err
+	}
+	if !followed {
+		return errors.New("user does not exist")
+	}
+
+	return nil
+}
+
+func (service *FollowService) UnfollowArtist(follower string, artistId string) error {
+	unfollowed, err := service.userRepository.UnfollowArtist(follower, artistId)
+	if err != nil {
+		return err
+	}
+	if !unfollowed {
+		return errors.New("artist is not being followed")
+	}
+
+	return nil
+}
+
+func (service *FollowService) GetCounts(username string) (*model.FollowCounts, error) {
+	return service.userRepository.GetFollowCounts(username)
+}
+
+func (service *FollowService) ListFollowers(username string) ([]*model.User, error) {
+	return service.userRepository.ListFollowers(username)
+}
+
+func (service *FollowService) ListFollowing(username string) ([]*model.User, error) {
+	return service.userRepository.ListFollowing(username)
+}
+
+// ListFollowedArtists returns the artists followed by a user, fetched from
+// Mongo in a single query.
+func (service *FollowService) ListFollowedArtists(ctx context.Context, username string) ([]model.Artist, error) {
+	artistIds, err := service.userRepository.ListFollowedArtistIds(username)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(artistIds))
+	for _, artistId := range artistIds {
+		if id, err := primitive.ObjectIDFromHex(artistId); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return []model.Artist{}, nil
+	}
+
+	return service.artistRepository.GetArtistsByIDs(ctx, ids)
+}
+
+func (service *FollowService) ListArtistFollowers(artistId string) ([]*model.User, error) {
+	return service.userRepository.ListArtistFollowers(artistId)
+}
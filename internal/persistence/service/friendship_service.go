@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/realtime"
+)
+
+type FriendshipService struct {
+	userRepository      *repository.UserRepository
+	eventService        *EventService
+	notificationService *NotificationService
+}
+
+func NewFriendshipService(
+	userRepository *repository.UserRepository,
+	eventService *EventService,
+	notificationService *NotificationService,
+) *FriendshipService {
+	return &FriendshipService{
+		userRepository:      userRepository,
+		eventService:        eventService,
+		notificationService: notificationService,
+	}
+}
+
+// SendRequest sends a friend request from one user to another. When the
+// receiver had already sent a request to the sender, that request is accepted
+// instead and true is returned.
+func (service *FriendshipService) SendRequest(from string, to string) (bool, error) {
+	if from == to {
+		return false, errors.New("users cannot befriend themselves")
+	}
+
+	users, err := service.checkUsersExist(from, to)
+	if err != nil {
+		return false, err
+	}
+	sender, receiver := users[0], users[1]
+
+	blocked, err := service.userRepository.IsBlocked(from, to)
+	if err != nil {
+		return false, err
+	}
+	if blocked {
+		return false, errors.New("user does not exist: " + to)
+	}
+
+	friends, err := service.userRepository.AreFriends(from, to)
+	if err != nil {
+		return false, err
+	}
+	if friends {
+		return false, errors.New("users are already friends")
+	}
+
+	pending, err := service.userRepository.HasFriendRequest(to, from)
+	if err != nil {
+		return false, err
+	}
+	if pending {
+		_, err = service.userRepository.AcceptFriendRequest(to, from)
+		if err != nil {
+			return false, err
+		}
+		service.eventService.Publish(receiver, realtime.EVENT_FRIEND_ACCEPTED, map[string]any{
+			"username": from,
+		})
+		service.notificationService.Notify([]*model.User{receiver}, model.NOTIFICATION_FRIEND_ACCEPTED, sender, 0)
+		return true, nil
+	}
+
+	sent, err := service.userRepository.SendFriendRequest(from, to)
+	if err != nil {
+		return false, err
+	}
+	if !sent {
+		return false, errors.New("users are already friends")
+	}
+
+	service.eventService.Publish(receiver, realtime.EVENT_FRIEND_REQUEST, map[string]any{
+		"username": from,
+	})
+	service.notificationService.Notify([]*model.User{receiver}, model.NOTIFICATION_FRIEND_REQUEST, sender, 0)
+
+	return false, nil
+}
+
+// AcceptRequest accepts the request requester sent to username.
+func (service *FriendshipService) AcceptRequest(username string, requester string) error {
+	accepted, err := service.userRepository.AcceptFriendRequest(requester, username)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return errors.New("friend request does not exist")
+	}
+
+	users, _, err := service.userRepository.GetByUsernames([]string{requester, username})
+	if err == nil && len(users) == 2 {
+		service.eventService.Publish(users[0], realtime.EVENT_FRIEND_ACCEPTED, map[string]any{
+			"username": username,
+		})
+		service.notificationService.Notify(users[:1], model.NOTIFICATION_FRIEND_ACCEPTED, users[1], 0)
+	}
+
+	return nil
+}
+
+// DeclineRequest removes the request requester sent to username.
+func (service *FriendshipService) DeclineRequest(username string, requester string) error {
+	return service.deleteRequest(requester, username)
+}
+
+// CancelRequest removes the request username sent to target.
+func (service *FriendshipService) CancelRequest(username string, target string) error {
+	return service.deleteRequest(username, target)
+}
+
+func (service *FriendshipService) Unfriend(username string, friend string) error {
+	removed, err := service.userRepository.RemoveFriendship(username, friend)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("users are not friends")
+	}
+
+	return nil
+}
+
+func (service *FriendshipService) ListIncomingRequests(username string) ([]*model.FriendRequest, error) {
+	return service.userRepository.ListIncomingFriendRequests(username)
+}
+
+func (service *FriendshipService) ListOutgoingRequests(username string) ([]*model.FriendRequest, error) {
+	return service.userRepository.ListOutgoingFriendRequests(username)
+}
+
+func (service *FriendshipService) deleteRequest(from string, to string) error {
+	deleted, err := service.userRepository.DeleteFriendRequest(from, to)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("friend request does not exist")
+	}
+
+	return nil
+}
+
+// checkUsersExist returns the users in the order their usernames were given,
+// failing when any of them does not exist.
+func (service *FriendshipService) checkUsersExist(usernames ...string) ([]*model.User, error) {
+	users, missing, err := service.userRepository.GetByUsernames(usernames)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, errors.New("user does not exist: " + missing[0])
+	}
+
+	return users, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/storage"
+
+	// Registers the gif decoder used by image.Decode.
+	_ "image/gif"
+)
+
+const THUMBNAIL_MAX_SIZE = 320
+const MAX_IMAGE_PIXELS = 40_000_000
+
+var ErrMediaTooLarge = errors.New("file is too large")
+var ErrUnsupportedMedia = errors.New("unsupported file type, only jpeg, png and gif images are accepted")
+
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+type MediaService struct {
+	storage   storage.Storage
+	maxBytes  int64
+	publicUrl string
+}
+
+// NewMediaService creates a service that stores images of at most maxBytes
+// bytes. publicUrl is the base URL under which the stored objects are served.
+func NewMediaService(storage storage.Storage, maxBytes int64, publicUrl string) *MediaService {
+	return &MediaService{
+		storage:   storage,
+		maxBytes:  maxBytes,
+		publicUrl: strings.TrimRight(publicUrl, "/"),
+	}
+}
+
+func (service *MediaService) MaxBytes() int64 {
+	return service.maxBytes
+}
+
+// URL returns the address from which clients can download an object.
+func (service *MediaService) URL(key string) string {
+	return service.publicUrl + "/" + key
+}
+
+// Upload validates an image, stores it together with a thumbnail and returns
+// their description. The content type is sniffed from the bytes, the one
+// declared by the client is ignored. Objects are keyed by the hash of their
+// content, so uploading the same image twice stores it only once.
+func (service *MediaService) Upload(ctx context.Context, reader io.Reader) (*model.Media, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, service.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > service.maxBytes {
+		return nil, ErrMediaTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := mediaExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedMedia
+	}
+
+	// Check the dimensions before decoding so a small file can not make us
+	// allocate a huge image.
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedMedia
+	}
+	if config.Width*config.Height > MAX_IMAGE_PIXELS {
+		return nil, ErrMediaTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedMedia
+	}
+
+	thumbnail, thumbnailType, err := encodeThumbnail(img, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	name := hex.EncodeToString(hash[:])
+	media := &model.Media{
+		Key:          name + extension,
+		ThumbnailKey: name + "_thumb" + mediaExtensions[thumbnailType],
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Width:        config.Width,
+		Height:       config.Height,
+	}
+
+	if err := service.storage.Put(ctx, media.Key, contentType, data); err != nil {
+		return nil, err
+	}
+	if err := service.storage.Put(ctx, media.ThumbnailKey, thumbnailType, thumbnail); err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (service *MediaService) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return service.storage.Get(ctx, key)
+}
+
+// ContentTypeOfKey returns the content type of an object from its extension.
+func ContentTypeOfKey(key string) string {
+	extension := filepath.Ext(key)
+	for contentType, mediaExtension := range mediaExtensions {
+		if extension == mediaExtension {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
+
+// encodeThumbnail scales the image down to fit a THUMBNAIL_MAX_SIZE square.
+// PNG images keep their format to preserve transparency, everything else is
+// encoded as JPEG.
+func encodeThumbnail(img image.Image, contentType string) ([]byte, string, error) {
+	thumbnail := resize(img, THUMBNAIL_MAX_SIZE)
+
+	var buffer bytes.Buffer
+	var err error
+	thumbnailType := "image/jpeg"
+
+	if contentType == "image/png" {
+		thumbnailType = "image/png"
+		err = png.Encode(&buffer, thumbnail)
+	} else {
+		err = jpeg.Encode(&buffer, thumbnail, &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("could not encode thumbnail: %w", err)
+	}
+
+	return buffer.Bytes(), thumbnailType, nil
+}
+
+// resize scales the image so its largest side is at most maxSize, averaging
+// the source pixels covered by each destination pixel.
+func resize(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	scale := 1.0
+	if width > maxSize || height > maxSize {
+		scale = float64(maxSize) / float64(max(width, height))
+	}
+	dstWidth := max(1, int(float64(width)*scale))
+	dstHeight := max(1, int(float64(height)*scale))
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		srcY0 := bounds.Min.Y + y*height/dstHeight
+		srcY1 := max(srcY0+1, bounds.Min.Y+(y+1)*height/dstHeight)
+		for x := 0; x < dstWidth; x++ {
+			srcX0 := bounds.Min.X + x*width/dstWidth
+			srcX1 := max(srcX0+1, bounds.Min.X+(x+1)*width/dstWidth)
+
+			var r, g, b, a, count uint64
+			for sy := srcY0; sy < srcY1; sy++ {
+				for sx := srcX0; sx < srcX1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: uint16(a / count),
+			})
+		}
+	}
+
+	return dst
+}
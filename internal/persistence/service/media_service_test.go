@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"symphony-api/internal/persistence/storage"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestPng(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: 255, A: 255})
+	}
+	var buffer bytes.Buffer
+	require.NoError(t, png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
+
+func TestMediaService_Upload(t *testing.T) {
+	localStorage := storage.NewLocalStorage(t.TempDir())
+	service := NewMediaService(localStorage, 1<<20, "/media")
+
+	media, err := service.Upload(context.Background(), bytes.NewReader(encodeTestPng(t, 800, 400)))
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", media.ContentType)
+	assert.Equal(t, 800, media.Width)
+	assert.Equal(t, 400, media.Height)
+	assert.True(t, strings.HasSuffix(media.Key, ".png"))
+	assert.True(t, strings.HasSuffix(media.ThumbnailKey, "_thumb.png"))
+
+	reader, err := localStorage.Get(context.Background(), media.ThumbnailKey)
+	require.NoError(t, err)
+	defer reader.Close()
+	thumbnail, err := png.Decode(reader)
+	require.NoError(t, err)
+	assert.Equal(t, THUMBNAIL_MAX_SIZE, thumbnail.Bounds().Dx())
+	assert.Equal(t, THUMBNAIL_MAX_SIZE/2, thumbnail.Bounds().Dy())
+}
+
+func TestMediaService_Upload_TooLarge(t *testing.T) {
+	service := NewMediaService(storage.NewLocalStorage(t.TempDir()), 10, "/media")
+
+	_, err := service.Upload(context.Background(), bytes.NewReader(encodeTestPng(t, 10, 10)))
+
+	assert.ErrorIs(t, err, ErrMediaTooLarge)
+}
+
+func TestMediaService_Upload_Unsupported(t *testing.T) {
+	service := NewMediaService(storage.NewLocalStorage(t.TempDir()), 1<<20, "/media")
+
+	_, err := service.Upload(context.Background(), strings.NewReader("<html>not an image</html>"))
+
+	assert.ErrorIs(t, err, ErrUnsupportedMedia)
+}
+
+func TestMediaService_URL(t *testing.T) {
+	service := NewMediaService(storage.NewLocalStorage(t.TempDir()), 10, "http://localhost:8080/media/")
+
+	assert.Equal(t, "http://localhost:8080/media/abc.jpg", service.URL("abc.jpg"))
+}
+
+func TestContentTypeOfKey(t *testing.T) {
+	assert.Equal(t, "image/jpeg", ContentTypeOfKey("abc_thumb.jpg"))
+	assert.Equal(t, "application/octet-stream", ContentTypeOfKey("abc"))
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+)
+
+const (
+	MODERATION_DISMISS = "dismiss"
+	MODERATION_HIDE    = "hide"
+	MODERATION_DELETE  = "delete"
+)
+
+const MAX_REPORT_REASON_LENGTH = 500
+
+type ModerationService struct {
+	reportRepository *repository.ReportRepository
+	userRepository   *repository.UserRepository
+	chatRepository   *repository.ChatRepository
+	hideThreshold    int64
+}
+
+// NewModerationService creates the service. Content is hidden automatically
+// once hideThreshold distinct users have open reports against it.
+func NewModerationService(
+	reportRepository *repository.ReportRepository,
+	userRepository *repository.UserRepository,
+	chatRepository *repository.ChatRepository,
+	hideThreshold int64,
+) *ModerationService {
+	return &ModerationService{
+		reportRepository: reportRepository,
+		userRepository:   userRepository,
+		chatRepository:   chatRepository,
+		hideThreshold:    hideThreshold,
+	}
+}
+
+func (service *ModerationService) Report(username string, contentType string, contentId int32, reason string) (*model.Report, error) {
+	if _, err := model.GetReportableContent(contentType); err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		return nil, errors.New("a reason must be provided")
+	}
+	if len(reason) > MAX_REPORT_REASON_LENGTH {
+		return nil, errors.New("reason is too long")
+	}
+
+	reporter, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	exists, err := service.reportRepository.ContentExists(contentType, contentId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("reported content does not exist")
+	}
+
+	// Chat messages can only be seen, and so reported, by the participants of
+	// the chat. Anyone else is told they do not exist.
+	if contentType == model.REPORTED_CHAT_MESSAGE {
+		participant, err := service.isMessageParticipant(reporter, contentId)
+		if err != nil {
+			return nil, err
+		}
+		if !participant {
+			return nil, errors.New("reported content does not exist")
+		}
+	}
+
+	alreadyReported, err := service.reportRepository.HasOpenReport(reporter.UserId, contentType, contentId)
+	if err != nil {
+		return nil, err
+	}
+	if alreadyReported {
+		return nil, errors.New("content already reported by user")
+	}
+
+	report := model.NewReport(reporter.UserId, contentType, contentId, reason)
+	if err := service.reportRepository.Put(report); err != nil {
+		return nil, err
+	}
+
+	reporters, err := service.reportRepository.CountOpenReporters(contentType, contentId)
+	if err != nil {
+		log.Printf("Error counting reporters of %s %d: %v", contentType, contentId, err)
+		return report, nil
+	}
+
+	if reporters >= service.hideThreshold {
+		if err := service.reportRepository.SetContentHidden(contentType, contentId, true); err != nil {
+			log.Printf("Error hiding %s %d: %v", contentType, contentId, err)
+		}
+	}
+
+	return report, nil
+}
+
+func (service *ModerationService) ListQueue(moderatorUsername string, status string, limit int32, offset int32) ([]*model.ModerationItem, error) {
+	if _, err := service.getModerator(moderatorUsername); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || offset < 0 {
+		return nil, errors.New("limit must be greater than zero and offset can not be negative")
+	}
+
+	return service.reportRepository.ListModerationQueue(status, limit, offset)
+}
+
+// Review applies the decision of a moderator to a reported content and closes
+// all its open reports.
+func (service *ModerationService) Review(moderatorUsername string, contentType string, contentId int32, action string) error {
+	moderator, err := service.getModerator(moderatorUsername)
+	if err != nil {
+		return err
+	}
+
+	exists, err := service.reportRepository.ContentExists(contentType, contentId)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("reported content does not exist")
+	}
+
+	var status string
+	switch action {
+	case MODERATION_DISMISS:
+		status = model.REPORT_STATUS_DISMISSED
+		err = service.reportRepository.SetContentHidden(contentType, contentId, false)
+	case MODERATION_HIDE:
+		status = model.REPORT_STATUS_HIDDEN
+		err = service.reportRepository.SetContentHidden(contentType, contentId, true)
+	case MODERATION_DELETE:
+		status = model.REPORT_STATUS_DELETED
+		err = service.reportRepository.DeleteContent(contentType, contentId)
+	default:
+		return errors.New("invalid moderation action: " + action)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return service.reportRepository.ResolveReports(contentType, contentId, status, moderator.UserId)
+}
+
+func (service *ModerationService) getModerator(username string) (*model.User, error) {
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+	if !user.IsAdmin {
+		return nil, errors.New("user is not a moderator")
+	}
+	return user, nil
+}
+
+func (service *ModerationService) isMessageParticipant(user *model.User, messageId int32) (bool, error) {
+	message, err := service.chatRepository.GetMessage(messageId)
+	if err != nil || message == nil {
+		return false, err
+	}
+
+	return service.chatRepository.IsParticipant(message.ChatId, user.UserId)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+)
+
+type NotificationService struct {
+	notificationRepository *repository.NotificationRepository
+	userRepository         *repository.UserRepository
+}
+
+func NewNotificationService(
+	notificationRepository *repository.NotificationRepository,
+	userRepository *repository.UserRepository,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepository: notificationRepository,
+		userRepository:         userRepository,
+	}
+}
+
+// Notify tells the recipients that the actor did something about the target.
+// The change that caused it is already stored, so a failure is only logged.
+func (service *NotificationService) Notify(recipients []*model.User, notificationType string, actor *model.User, targetId int32) {
+	recipientIds := make([]int32, 0, len(recipients))
+	for _, recipient := range recipients {
+		recipientIds = append(recipientIds, recipient.UserId)
+	}
+
+	err := service.notificationRepository.Notify(recipientIds, notificationType, actor.UserId, targetId)
+	if err != nil {
+		log.Printf("Error creating %s notifications from user %d: %v", notificationType, actor.UserId, err)
+	}
+}
+
+// List returns a page of the notifications of the user along with how many
+// of them are unread.
+func (service *NotificationService) List(username string, limit int32, offset int32, unreadOnly bool) ([]*model.Notification, int64, error) {
+	if limit <= 0 || offset < 0 {
+		return nil, 0, errors.New("limit must be greater than zero and offset can not be negative")
+	}
+
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, 0, errors.New("user does not exist")
+	}
+
+	notifications, err := service.notificationRepository.List(user.UserId, limit, offset, unreadOnly)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unread, err := service.notificationRepository.CountUnread(user.UserId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, unread, nil
+}
+
+func (service *NotificationService) MarkRead(username string, notificationId int32) error {
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return errors.New("user does not exist")
+	}
+
+	found, err := service.notificationRepository.MarkRead(user.UserId, notificationId)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("notification does not exist")
+	}
+
+	return nil
+}
+
+func (service *NotificationService) MarkAllRead(username string) error {
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return errors.New("user does not exist")
+	}
+
+	return service.notificationRepository.MarkAllRead(user.UserId)
+}
+
+func (service *NotificationService) Delete(username string, notificationId int32) error {
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return errors.New("user does not exist")
+	}
+
+	found, err := service.notificationRepository.Delete(user.UserId, notificationId)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("notification does not exist")
+	}
+
+	return nil
+}
+
+// ListPreferences returns every type of notification, telling which ones the
+// user muted.
+func (service *NotificationService) ListPreferences(username string) ([]*model.NotificationPreference, error) {
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	muted, err := service.notificationRepository.ListMutedTypes(user.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewNotificationPreferences(muted), nil
+}
+
+// SetPreference mutes or unmutes a type of notification for the user. Muting
+// only stops new notifications; the existing ones are kept.
+func (service *NotificationService) SetPreference(username string, notificationType string, muted bool) error {
+	if err := model.ValidateNotificationType(notificationType); err != nil {
+		return err
+	}
+
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return errors.New("user does not exist")
+	}
+
+	return service.notificationRepository.SetMuted(user.UserId, notificationType, muted)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"errors"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+)
+
+type PrivacyService struct {
+	userRepository *repository.UserRepository
+}
+
+func NewPrivacyService(userRepository *repository.UserRepository) *PrivacyService {
+	return &PrivacyService{
+		userRepository: userRepository,
+	}
+}
+
+func (service *PrivacyService) Block(blocker string, blocked string) error {
+	if blocker == blocked {
+		return errors.New("users cannot block themselves")
+	}
+
+	done, err := service.userRepository.BlockUser(blocker, blocked)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errors.New("user does not exist")
+	}
+
+	return nil
+}
+
+func (service *PrivacyService) Unblock(blocker string, blocked string) error {
+	done, err := service.userRepository.UnblockUser(blocker, blocked)
+	if err != nil {
+		return err
+	}
+	if !done {
+		return errors.New("user is not blocked")
+	}
+
+	return nil
+}
+
+func (service *PrivacyService) ListBlocked(username string) ([]*model.User, error) {
+	return service.userRepository.ListBlockedUsers(username)
+}
+
+func (service *PrivacyService) UpdateSettings(username string, profileVisibility string, messagePermission string) (*model.User, error) {
+	if err := model.ValidatePrivacySettings(profileVisibility, messagePermission); err != nil {
+		return nil, err
+	}
+
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	err = service.userRepository.UpdatePrivacySettings(user.UserId, profileVisibility, messagePermission)
+	if err != nil {
+		return nil, err
+	}
+
+	user.ProfileVisibility = profileVisibility
+	user.MessagePermission = messagePermission
+
+	return user, nil
+}
+
+// CheckCanViewProfile fails when the viewer is not allowed to see the profile
+// of the user. An empty viewer stands for an anonymous request. Blocked users
+// get the same error as if the user did not exist.
+func (service *PrivacyService) CheckCanViewProfile(viewer string, user *model.User) error {
+	if viewer == user.Username {
+		return nil
+	}
+
+	isFriend := false
+	if viewer != "" {
+		blocked, err := service.userRepository.IsBlocked(viewer, user.Username)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return errors.New("user not found")
+		}
+
+		if user.ProfileVisibility == model.PROFILE_FRIENDS {
+			isFriend, err = service.userRepository.AreFriends(viewer, user.Username)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !model.CanSeeProfile(user.ProfileVisibility, false, isFriend) {
+		return errors.New("this profile is not visible")
+	}
+
+	return nil
+}
+
+// CheckCanMessage fails when the sender is not allowed to message the
+// recipient, either because one of them blocked the other or because of the
+// message permission of the recipient.
+func (service *PrivacyService) CheckCanMessage(sender *model.User, recipient *model.User) error {
+	blocked, err := service.userRepository.IsBlocked(sender.Username, recipient.Username)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errors.New("you cannot message this user")
+	}
+
+	isFriend := false
+	if recipient.MessagePermission == model.MESSAGES_FRIENDS {
+		isFriend, err = service.userRepository.AreFriends(sender.Username, recipient.Username)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !model.CanReceiveMessage(recipient.MessagePermission, isFriend) {
+		return errors.New("this user does not accept messages from you")
+	}
+
+	return nil
+}
+
+// ResolveViews chooses the projection of user data the viewer gets for each of
+// the users. It fetches the friends of the viewer once, no matter how many
+// users are given.
+func (service *PrivacyService) ResolveViews(viewer string, users []*model.User) (model.UserViews, error) {
+	var friends []string
+
+	if viewer != "" && len(users) > 0 {
+		var err error
+		friends, err = service.userRepository.ListFriendUsernames(viewer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return model.NewUserViews(viewer, users, friends), nil
+}
+
+// FilterVisibleUsers keeps the users whose profile the viewer can see, like
+// CheckCanViewProfile does for one user. It fetches the friends and blocks of
+// the viewer once, no matter how many users are given.
+func (service *PrivacyService) FilterVisibleUsers(viewer string, users []*model.User) ([]*model.User, error) {
+	var friends, blocked []string
+
+	if viewer != "" && len(users) > 0 {
+		var err error
+		friends, err = service.userRepository.ListFriendUsernames(viewer)
+		if err != nil {
+			return nil, err
+		}
+		blocked, err = service.userRepository.ListBlockRelatedUsernames(viewer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return visibleUsers(viewer, users, friends, blocked), nil
+}
+
+// FilterBlockedUsers leaves out the users blocked by the viewer or blocking
+// them.
+func (service *PrivacyService) FilterBlockedUsers(viewer string, users []*model.User) ([]*model.User, error) {
+	if viewer == "" || len(users) == 0 {
+		return users, nil
+	}
+
+	blocked, err := service.userRepository.ListBlockRelatedUsernames(viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutUsernames(users, blocked), nil
+}
+
+// FilterVisiblePosts keeps the posts whose author the viewer can see the
+// profile of. Posts whose author can not be read are left out.
+func (service *PrivacyService) FilterVisiblePosts(viewer string, posts []*model.Post) ([]*model.Post, error) {
+	authors := make([]*model.User, 0)
+	authorOf := make(map[int32]*model.User)
+	for _, post := range posts {
+		if _, ok := authorOf[post.UserId]; ok {
+			continue
+		}
+		author, err := service.userRepository.GetById(int64(post.UserId))
+		if err == nil {
+			authors = append(authors, author)
+		}
+		authorOf[post.UserId] = author
+	}
+
+	visibleAuthors, err := service.FilterVisibleUsers(viewer, authors)
+	if err != nil {
+		return nil, err
+	}
+
+	isVisible := make(map[int32]bool, len(visibleAuthors))
+	for _, author := range visibleAuthors {
+		isVisible[author.UserId] = true
+	}
+
+	visible := make([]*model.Post, 0, len(posts))
+	for _, post := range posts {
+		if isVisible[post.UserId] {
+			visible = append(visible, post)
+		}
+	}
+
+	return visible, nil
+}
+
+func visibleUsers(viewer string, users []*model.User, friends []string, blocked []string) []*model.User {
+	isFriend := make(map[string]bool, len(friends))
+	for _, friend := range friends {
+		isFriend[friend] = true
+	}
+
+	visible := make([]*model.User, 0, len(users))
+	for _, user := range withoutUsernames(users, blocked) {
+		if model.CanSeeProfile(user.ProfileVisibility, user.Username == viewer, isFriend[user.Username]) {
+			visible = append(visible, user)
+		}
+	}
+
+	return visible
+}
+
+func withoutUsernames(users []*model.User, usernames []string) []*model.User {
+	excluded := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		excluded[username] = true
+	}
+
+	kept := make([]*model.User, 0, len(users))
+	for _, user := range users {
+		if !excluded[user.Username] {
+			kept = append(kept, user)
+		}
+	}
+
+	return kept
+}
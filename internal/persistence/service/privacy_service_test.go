@@ -0,0 +1,30 @@
+package service
+
+import (
+	"symphony-api/internal/persistence/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisibleUsers(t *testing.T) {
+	john := &model.User{Username: "john", ProfileVisibility: model.PROFILE_PRIVATE}
+	mary := &model.User{Username: "mary", ProfileVisibility: model.PROFILE_FRIENDS}
+	bob := &model.User{Username: "bob", ProfileVisibility: model.PROFILE_FRIENDS}
+	alice := &model.User{Username: "alice", ProfileVisibility: model.PROFILE_PUBLIC}
+	eve := &model.User{Username: "eve", ProfileVisibility: model.PROFILE_PUBLIC}
+	carl := &model.User{Username: "carl", ProfileVisibility: model.PROFILE_PRIVATE}
+
+	visible := visibleUsers("john", []*model.User{john, mary, bob, alice, eve, carl}, []string{"mary", "carl"}, []string{"eve"})
+
+	assert.Equal(t, []*model.User{john, mary, alice}, visible)
+}
+
+func TestVisibleUsers_Anonymous(t *testing.T) {
+	mary := &model.User{Username: "mary", ProfileVisibility: model.PROFILE_FRIENDS}
+	alice := &model.User{Username: "alice", ProfileVisibility: model.PROFILE_PUBLIC}
+
+	visible := visibleUsers("", []*model.User{mary, alice}, nil, nil)
+
+	assert.Equal(t, []*model.User{alice}, visible)
+}
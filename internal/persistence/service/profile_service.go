@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	mongo_repository "symphony-api/internal/persistence/repository/mongo"
+)
+
+type ProfileService struct {
+	userRepository     *repository.UserRepository
+	playlistRepository *mongo_repository.PlaylistRepository
+	storyRepository    *mongo_repository.StoryRepository
+}
+
+func NewProfileService(
+	userRepository *repository.UserRepository,
+	playlistRepository *mongo_repository.PlaylistRepository,
+	storyRepository *mongo_repository.StoryRepository,
+) *ProfileService {
+	return &ProfileService{
+		userRepository:     userRepository,
+		playlistRepository: playlistRepository,
+		storyRepository:    storyRepository,
+	}
+}
+
+// UpdateProfile applies the changes to the profile of a user. The username is
+// duplicated in the Neo4j node and in the Mongo playlists and stories, so a
+// username change is applied to Postgres first and then to the other stores.
+// When one of the later steps fails, the steps already done are reverted.
+func (service *ProfileService) UpdateProfile(ctx context.Context, username string, update *model.ProfileUpdate) (*model.User, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	updated := update.Apply(user)
+	renamed := updated.Username != user.Username
+
+	if renamed {
+		if _, err := service.userRepository.GetByUsername(updated.Username); err == nil {
+			return nil, errors.New("username is already taken")
+		}
+	}
+
+	if err := service.userRepository.UpdateProfile(updated); err != nil {
+		return nil, err
+	}
+
+	if !renamed {
+		return updated, nil
+	}
+
+	if err := service.rename(ctx, user.Username, updated.Username); err != nil {
+		if revertErr := service.userRepository.UpdateProfile(user); revertErr != nil {
+			log.Printf("Error reverting profile of user %d: %v", user.UserId, revertErr)
+		}
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// rename moves the username in Neo4j and Mongo. When a step fails, every step
+// started so far is undone, the failed one included, since an update of many
+// documents may have been partially applied.
+func (service *ProfileService) rename(ctx context.Context, oldUsername string, newUsername string) error {
+	steps := []struct {
+		name string
+		do   func(from string, to string) error
+	}{
+		{"user node", service.userRepository.RenameNode},
+		{"playlists", func(from string, to string) error {
+			return service.playlistRepository.RenameUsername(ctx, from, to)
+		}},
+		{"stories", func(from string, to string) error {
+			return service.storyRepository.RenameUsername(ctx, from, to)
+		}},
+	}
+
+	for i, step := range steps {
+		if err := step.do(oldUsername, newUsername); err != nil {
+			log.Printf("Error renaming %s of %s: %v", step.name, oldUsername, err)
+
+			for j := i; j >= 0; j-- {
+				if undoErr := steps[j].do(newUsername, oldUsername); undoErr != nil {
+					log.Printf("Error reverting rename of %s of %s: %v", steps[j].name, oldUsername, undoErr)
+				}
+			}
+			return errors.New("could not change username")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	mongo_repository "symphony-api/internal/persistence/repository/mongo"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MAX_RECOMMENDATION_CANDIDATES bounds how many candidates the graph gives
+// for a single request, and MAX_RECOMMENDATION_CO_MEMBERS how many are added
+// from the communities and from the playlists of the user each.
+const (
+	MAX_RECOMMENDATION_CANDIDATES = 200
+	MAX_RECOMMENDATION_CO_MEMBERS = 50
+)
+
+type RecommendationService struct {
+	userRepository      *repository.UserRepository
+	communityRepository *repository.CommunityRepository
+	playlistRepository  *mongo_repository.PlaylistRepository
+}
+
+func NewRecommendationService(
+	userRepository *repository.UserRepository,
+	communityRepository *repository.CommunityRepository,
+	playlistRepository *mongo_repository.PlaylistRepository,
+) *RecommendationService {
+	return &RecommendationService{
+		userRepository:      userRepository,
+		communityRepository: communityRepository,
+		playlistRepository:  playlistRepository,
+	}
+}
+
+// Recommend returns a page of the users the given user may want to befriend,
+// best first. Candidates come from the graph (friends of friends and users
+// liking the same genres), from the communities of the user and from the
+// playlists sharing songs with theirs. They are scored with the mutual
+// friends, the genre similarity, the shared communities and the songs found
+// in both users' playlists.
+func (service *RecommendationService) Recommend(ctx context.Context, username string, limit int32, offset int32) ([]*model.Recommendation, error) {
+	if limit <= 0 || offset < 0 {
+		return nil, errors.New("limit must be greater than zero and offset can not be negative")
+	}
+
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+
+	recommendations, err := service.userRepository.ListRecommendationCandidates(username, MAX_RECOMMENDATION_CANDIDATES)
+	if err != nil {
+		return nil, err
+	}
+
+	coMembers, err := service.listCoMembers(ctx, user, recommendations)
+	if err != nil {
+		return nil, err
+	}
+	if len(coMembers) > 0 {
+		more, err := service.userRepository.ListRecommendationSignals(username, coMembers)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, more...)
+	}
+
+	if len(recommendations) == 0 {
+		return recommendations, nil
+	}
+
+	usernames := make([]string, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		usernames = append(usernames, recommendation.Username)
+	}
+
+	sharedCommunities, err := service.communityRepository.CountSharedCommunities(user.UserId, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedSongs, err := service.countSharedSongs(ctx, username, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recommendation := range recommendations {
+		recommendation.SharedCommunities = sharedCommunities[recommendation.Username]
+		recommendation.SharedSongs = sharedSongs[recommendation.Username]
+		recommendation.Rank()
+	}
+
+	model.SortRecommendations(recommendations)
+
+	page := paginateRecommendations(recommendations, limit, offset)
+
+	pageUsernames := make([]string, 0, len(page))
+	for _, recommendation := range page {
+		pageUsernames = append(pageUsernames, recommendation.Username)
+	}
+
+	users, _, err := service.userRepository.GetByUsernames(pageUsernames)
+	if err != nil {
+		return nil, err
+	}
+
+	byUsername := make(map[string]*model.User, len(users))
+	for _, user := range users {
+		byUsername[user.Username] = user
+	}
+
+	// Candidates missing from Postgres are dropped from the page.
+	hydrated := make([]*model.Recommendation, 0, len(page))
+	for _, recommendation := range page {
+		if user, ok := byUsername[recommendation.Username]; ok {
+			recommendation.User = user
+			hydrated = append(hydrated, recommendation)
+		}
+	}
+
+	return hydrated, nil
+}
+
+// listCoMembers returns the users sharing a community with the user or having
+// a playlist with one of their songs, leaving out the known candidates.
+func (service *RecommendationService) listCoMembers(ctx context.Context, user *model.User, known []*model.Recommendation) ([]string, error) {
+	seen := make(map[string]bool, len(known))
+	for _, recommendation := range known {
+		seen[recommendation.Username] = true
+	}
+
+	peers, err := service.communityRepository.ListCommunityPeers(user.UserId, MAX_RECOMMENDATION_CO_MEMBERS)
+	if err != nil {
+		return nil, err
+	}
+
+	playlists, err := service.playlistRepository.GetPlaylistsByUsername(ctx, user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	songIds := make([]primitive.ObjectID, 0)
+	for _, playlist := range playlists {
+		for _, song := range playlist.Songs {
+			songIds = append(songIds, song.SongID)
+		}
+	}
+	if len(songIds) > 0 {
+		owners, err := service.playlistRepository.ListUsernamesWithSongs(ctx, songIds, user.Username, MAX_RECOMMENDATION_CO_MEMBERS)
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, owners...)
+	}
+
+	coMembers := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		if !seen[peer] {
+			seen[peer] = true
+			coMembers = append(coMembers, peer)
+		}
+	}
+
+	return coMembers, nil
+}
+
+// countSharedSongs counts, for each candidate, the distinct songs of their
+// playlists that are also in one of the playlists of the user.
+func (service *RecommendationService) countSharedSongs(ctx context.Context, username string, candidates []string) (map[string]int64, error) {
+	counts := make(map[string]int64)
+
+	playlists, err := service.playlistRepository.GetPlaylistsByUsernames(ctx, append([]string{username}, candidates...))
+	if err != nil {
+		return nil, err
+	}
+
+	songs := make(map[primitive.ObjectID]bool)
+	for _, playlist := range playlists {
+		if playlist.Username != username {
+			continue
+		}
+		for _, song := range playlist.Songs {
+			songs[song.SongID] = true
+		}
+	}
+
+	if len(songs) == 0 {
+		return counts, nil
+	}
+
+	seen := make(map[string]map[primitive.ObjectID]bool)
+	for _, playlist := range playlists {
+		if playlist.Username == username {
+			continue
+		}
+		if seen[playlist.Username] == nil {
+			seen[playlist.Username] = make(map[primitive.ObjectID]bool)
+		}
+		for _, song := range playlist.Songs {
+			if songs[song.SongID] && !seen[playlist.Username][song.SongID] {
+				seen[playlist.Username][song.SongID] = true
+				counts[playlist.Username]++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+func paginateRecommendations(recommendations []*model.Recommendation, limit int32, offset int32) []*model.Recommendation {
+	if int(offset) >= len(recommendations) {
+		return make([]*model.Recommendation, 0)
+	}
+
+	end := int(offset) + int(limit)
+	if end > len(recommendations) {
+		end = len(recommendations)
+	}
+
+	return recommendations[offset:end]
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+)
+
+type SocialPathService struct {
+	userRepository      *repository.UserRepository
+	communityRepository *repository.CommunityRepository
+	privacyService      *PrivacyService
+}
+
+func NewSocialPathService(
+	userRepository *repository.UserRepository,
+	communityRepository *repository.CommunityRepository,
+	privacyService *PrivacyService,
+) *SocialPathService {
+	return &SocialPathService{
+		userRepository:      userRepository,
+		communityRepository: communityRepository,
+		privacyService:      privacyService,
+	}
+}
+
+// GetConnection returns the shortest chain of friendships from the user to
+// the target, looking at most maxDepth friendships away, together with the
+// mutual friends, communities and genres they share. The user must be allowed
+// to see the profile of the target. Users of the path the user can not see
+// are redacted, and mutual friends they can not see are left out.
+func (service *SocialPathService) GetConnection(username string, target string, maxDepth int) (*model.SocialConnection, error) {
+	if maxDepth < 1 || maxDepth > model.MAX_SOCIAL_PATH_DEPTH {
+		return nil, fmt.Errorf("max depth must be between 1 and %d", model.MAX_SOCIAL_PATH_DEPTH)
+	}
+	if username == target {
+		return nil, errors.New("users must be different")
+	}
+
+	users, missing, err := service.userRepository.GetByUsernames([]string{username, target})
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, errors.New("user does not exist: " + missing[0])
+	}
+	user, targetUser := users[0], users[1]
+
+	if err := service.privacyService.CheckCanViewProfile(username, targetUser); err != nil {
+		return nil, err
+	}
+
+	pathUsernames, err := service.userRepository.GetShortestPath(username, target, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	path, missing, err := service.userRepository.GetByUsernames(pathUsernames)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		// The graph points to users that are gone, so the chain is broken.
+		path = make([]*model.User, 0)
+	}
+
+	path, err = service.redactHiddenUsers(username, path)
+	if err != nil {
+		return nil, err
+	}
+
+	mutualFriends, err := service.userRepository.ListMutualFriends(username, target)
+	if err != nil {
+		return nil, err
+	}
+
+	mutualFriends, err = service.privacyService.FilterVisibleUsers(username, mutualFriends)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedCommunities, err := service.communityRepository.ListSharedCommunities(user.UserId, targetUser.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedGenres, err := service.userRepository.ListSharedGenres(username, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SocialConnection{
+		Path:              path,
+		MutualFriends:     mutualFriends,
+		SharedCommunities: sharedCommunities,
+		SharedGenres:      sharedGenres,
+	}, nil
+}
+
+// redactHiddenUsers replaces the users of the path the user can not see with
+// nil, keeping the length of the path.
+func (service *SocialPathService) redactHiddenUsers(username string, path []*model.User) ([]*model.User, error) {
+	visible, err := service.privacyService.FilterVisibleUsers(username, path)
+	if err != nil {
+		return nil, err
+	}
+
+	isVisible := make(map[string]bool, len(visible))
+	for _, user := range visible {
+		isVisible[user.Username] = true
+	}
+
+	redacted := make([]*model.User, len(path))
+	for i, user := range path {
+		if isVisible[user.Username] {
+			redacted[i] = user
+		}
+	}
+
+	return redacted, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	mongo_repository "symphony-api/internal/persistence/repository/mongo"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const MAX_STORY_CAPTION_LENGTH = 200
+
+type StoryService struct {
+	storyRepository   *mongo_repository.StoryRepository
+	userRepository    *repository.UserRepository
+	attachmentService *AttachmentService
+}
+
+func NewStoryService(
+	storyRepository *mongo_repository.StoryRepository,
+	userRepository *repository.UserRepository,
+	attachmentService *AttachmentService,
+) *StoryService {
+	return &StoryService{
+		storyRepository:   storyRepository,
+		userRepository:    userRepository,
+		attachmentService: attachmentService,
+	}
+}
+
+func (service *StoryService) CreateStory(ctx context.Context, username string, songId string, caption string) (*model.Story, *model.Attachment, error) {
+	if len(caption) > MAX_STORY_CAPTION_LENGTH {
+		return nil, nil, errors.New("caption is too long")
+	}
+
+	if _, err := service.userRepository.GetByUsername(username); err != nil {
+		return nil, nil, errors.New("user does not exist")
+	}
+
+	song, err := model.NewAttachment(model.ATTACHMENT_SONG, songId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := service.attachmentService.Validate(ctx, song); err != nil {
+		return nil, nil, err
+	}
+
+	story := model.NewStory(username, song.ObjectId(), caption)
+	if _, err := service.storyRepository.InsertStory(ctx, *story); err != nil {
+		return nil, nil, err
+	}
+
+	if err := service.attachmentService.Hydrate(ctx, []*model.Attachment{song}); err != nil {
+		return nil, nil, err
+	}
+
+	return story, song, nil
+}
+
+// GetStoryTray lists the friends of the user that have active stories.
+func (service *StoryService) GetStoryTray(ctx context.Context, username string) ([]*model.StoryTrayItem, error) {
+	friends, err := service.userRepository.ListFriendUsernames(username)
+	if err != nil {
+		return nil, err
+	}
+	if len(friends) == 0 {
+		return []*model.StoryTrayItem{}, nil
+	}
+
+	stories, err := service.storyRepository.GetActiveStoriesByUsernames(ctx, friends)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.BuildStoryTray(stories, username), nil
+}
+
+// ListStories returns the active stories of owner, which can only be seen by
+// the owner and their friends, with the song of each story hydrated.
+func (service *StoryService) ListStories(ctx context.Context, owner string, viewer string) ([]model.Story, []*model.Attachment, error) {
+	if err := service.checkCanSee(owner, viewer); err != nil {
+		return nil, nil, err
+	}
+
+	stories, err := service.storyRepository.GetActiveStoriesByUsernames(ctx, []string{owner})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	songs := make([]*model.Attachment, len(stories))
+	for i, story := range stories {
+		songs[i] = &model.Attachment{Type: model.ATTACHMENT_SONG, Id: story.SongID.Hex()}
+	}
+	if err := service.attachmentService.Hydrate(ctx, songs); err != nil {
+		return nil, nil, err
+	}
+
+	return stories, songs, nil
+}
+
+// ViewStory records that a friend of the author has seen the story. Views of
+// the author are not recorded.
+func (service *StoryService) ViewStory(ctx context.Context, storyId string, viewer string) error {
+	story, err := service.getActiveStory(ctx, storyId)
+	if err != nil {
+		return err
+	}
+	if err := service.checkCanSee(story.Username, viewer); err != nil {
+		return err
+	}
+	if story.Username == viewer {
+		return nil
+	}
+
+	return service.storyRepository.AddView(ctx, story.ID, viewer)
+}
+
+// ListViewers returns who has seen a story. Only the author can see it.
+func (service *StoryService) ListViewers(ctx context.Context, storyId string, username string) ([]model.StoryView, error) {
+	story, err := service.getActiveStory(ctx, storyId)
+	if err != nil {
+		return nil, err
+	}
+	if story.Username != username {
+		return nil, errors.New("only the author can list the viewers of a story")
+	}
+
+	return story.Views, nil
+}
+
+func (service *StoryService) getActiveStory(ctx context.Context, storyId string) (*model.Story, error) {
+	id, err := primitive.ObjectIDFromHex(storyId)
+	if err != nil {
+		return nil, errors.New("invalid story id")
+	}
+
+	story, err := service.storyRepository.GetActiveStoryByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("story not found")
+	}
+	return story, nil
+}
+
+func (service *StoryService) checkCanSee(owner string, viewer string) error {
+	if owner == viewer {
+		return nil
+	}
+
+	friends, err := service.userRepository.AreFriends(owner, viewer)
+	if err != nil {
+		return err
+	}
+	if !friends {
+		return errors.New("stories are only visible to friends")
+	}
+	return nil
+}
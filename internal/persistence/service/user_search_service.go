@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+)
+
+const MAX_SEARCH_RESULTS = 50
+
+type UserSearchService struct {
+	userRepository *repository.UserRepository
+}
+
+func NewUserSearchService(userRepository *repository.UserRepository) *UserSearchService {
+	return &UserSearchService{
+		userRepository: userRepository,
+	}
+}
+
+// Search finds users by username or full name. When a viewer is given, users
+// blocked by or blocking them are left out and the results are boosted by how
+// close each user is to the viewer in the social graph.
+func (service *UserSearchService) Search(viewer string, query string, limit int32) ([]*model.UserSearchResult, error) {
+	if limit <= 0 || limit > MAX_SEARCH_RESULTS {
+		return nil, errors.New("limit must be between 1 and 50")
+	}
+
+	query, err := model.NormalizeSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := service.userRepository.Search(query, model.MAX_SEARCH_CANDIDATES)
+	if err != nil {
+		return nil, err
+	}
+
+	if viewer != "" && len(results) > 0 {
+		results, err = service.applyViewer(viewer, results)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, result := range results {
+		result.Rank()
+	}
+	model.SortUserSearchResults(results)
+
+	if len(results) > int(limit) {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+func (service *UserSearchService) applyViewer(viewer string, results []*model.UserSearchResult) ([]*model.UserSearchResult, error) {
+	blocked, err := service.userRepository.ListBlockRelatedUsernames(viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	isBlocked := make(map[string]bool, len(blocked))
+	for _, username := range blocked {
+		isBlocked[username] = true
+	}
+
+	visible := make([]*model.UserSearchResult, 0, len(results))
+	usernames := make([]string, 0, len(results))
+	for _, result := range results {
+		if !isBlocked[result.User.Username] {
+			visible = append(visible, result)
+			usernames = append(usernames, result.User.Username)
+		}
+	}
+
+	proximities, err := service.userRepository.GetSocialProximity(viewer, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range visible {
+		result.ApplyProximity(proximities[result.User.Username])
+	}
+
+	return visible, nil
+}
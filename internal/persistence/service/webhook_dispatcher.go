@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"log"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/webhook"
+	"sync"
+	"time"
+)
+
+// Number of deliveries claimed and sent at a time.
+const WEBHOOK_DISPATCH_BATCH = 20
+
+// WebhookDispatcher sends the queued webhook deliveries. Failed ones are
+// retried with an exponential backoff until maxAttempts, when they are
+// marked dead and only sent again if replayed. Several dispatchers can run
+// against the same database.
+type WebhookDispatcher struct {
+	webhookRepository *repository.WebhookRepository
+	sender            *webhook.Sender
+	maxAttempts       int32
+	lease             time.Duration
+}
+
+func NewWebhookDispatcher(
+	webhookRepository *repository.WebhookRepository,
+	guard *webhook.Guard,
+	timeout time.Duration,
+	maxAttempts int32,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookRepository: webhookRepository,
+		sender:            webhook.NewSender(timeout, guard),
+		maxAttempts:       max(maxAttempts, 1),
+		// Long enough for a whole batch to be sent before anyone else can
+		// claim it again.
+		lease: 2*timeout + 30*time.Second,
+	}
+}
+
+// Run sends the due deliveries every interval until ctx is done.
+func (dispatcher *WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(max(interval, time.Second))
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := dispatcher.DeliverDue(ctx)
+			if err != nil {
+				log.Printf("Error claiming webhook deliveries: %v", err)
+				break
+			}
+			if sent < WEBHOOK_DISPATCH_BATCH {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue claims a batch of due deliveries and makes one attempt to send
+// each of them, returning how many there were.
+func (dispatcher *WebhookDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := dispatcher.webhookRepository.ClaimDue(WEBHOOK_DISPATCH_BATCH, dispatcher.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wait sync.WaitGroup
+	for _, delivery := range deliveries {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			dispatcher.deliver(ctx, delivery)
+		}()
+	}
+	wait.Wait()
+
+	return len(deliveries), nil
+}
+
+func (dispatcher *WebhookDispatcher) deliver(ctx context.Context, delivery *model.WebhookDelivery) {
+	result := dispatcher.sender.Send(ctx, &webhook.Delivery{
+		Id:        delivery.DeliveryId,
+		EventType: delivery.EventType,
+		Url:       delivery.Url,
+		Secret:    delivery.Secret,
+		Payload:   delivery.Payload,
+	})
+
+	attempt := delivery.Attempts + 1
+	status, retryIn := nextDeliveryState(result.Succeeded(), attempt, dispatcher.maxAttempts)
+	if status == model.WEBHOOK_DELIVERY_DEAD {
+		log.Printf("Webhook delivery %d to subscription %d is dead after %d attempts: %s",
+			delivery.DeliveryId, delivery.SubscriptionId, attempt, result.Error)
+	}
+
+	err := dispatcher.webhookRepository.RecordAttempt(&model.WebhookAttempt{
+		DeliveryId:   delivery.DeliveryId,
+		Attempt:      attempt,
+		StatusCode:   int32(result.StatusCode),
+		Error:        result.Error,
+		ResponseBody: result.ResponseBody,
+		DurationMs:   int32(result.Duration.Milliseconds()),
+	}, status, retryIn)
+	if err != nil {
+		// The claim expires, so the delivery is attempted again.
+		log.Printf("Error recording attempt %d of webhook delivery %d: %v", attempt, delivery.DeliveryId, err)
+	}
+}
+
+// nextDeliveryState tells the status of a delivery after the given attempt
+// and, while it is pending, how long until the next one.
+func nextDeliveryState(succeeded bool, attempt int32, maxAttempts int32) (string, time.Duration) {
+	if succeeded {
+		return model.WEBHOOK_DELIVERY_DELIVERED, 0
+	}
+	if attempt >= maxAttempts {
+		return model.WEBHOOK_DELIVERY_DEAD, 0
+	}
+	return model.WEBHOOK_DELIVERY_PENDING, webhook.RetryDelay(int(attempt))
+}
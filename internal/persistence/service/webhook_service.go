@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/persistence/repository"
+	"symphony-api/internal/webhook"
+)
+
+// WebhookService manages the webhook subscriptions of partners and queues
+// the platform events they subscribed to. Deliveries are sent later by the
+// WebhookDispatcher. Subscriptions and their logs are only managed by admins.
+type WebhookService struct {
+	webhookRepository *repository.WebhookRepository
+	userRepository    *repository.UserRepository
+	guard             *webhook.Guard
+}
+
+func NewWebhookService(
+	webhookRepository *repository.WebhookRepository,
+	userRepository *repository.UserRepository,
+	guard *webhook.Guard,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepository: webhookRepository,
+		userRepository:    userRepository,
+		guard:             guard,
+	}
+}
+
+// Publish queues an event for the subscriptions to its type. The change that
+// caused it is already stored, so a failure is only logged.
+func (service *WebhookService) Publish(eventType string, data any) {
+	payload, err := webhook.NewPayload(eventType, data)
+	if err != nil {
+		log.Printf("Error encoding %s webhook payload: %v", eventType, err)
+		return
+	}
+
+	if err := service.webhookRepository.Enqueue(eventType, payload); err != nil {
+		log.Printf("Error queueing %s webhook deliveries: %v", eventType, err)
+	}
+}
+
+// CreateSubscription registers a URL to receive events of the given types.
+// The returned subscription carries the secret deliveries are signed with,
+// which is not shown again.
+func (service *WebhookService) CreateSubscription(username string, url string, eventTypes []string) (*model.WebhookSubscription, error) {
+	admin, err := service.getAdmin(username)
+	if err != nil {
+		return nil, err
+	}
+
+	eventTypes = uniqueEventTypes(eventTypes)
+	if err := service.validateSubscription(url, eventTypes); err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return service.webhookRepository.CreateSubscription(admin.UserId, url, secret, eventTypes)
+}
+
+func (service *WebhookService) ListSubscriptions(username string) ([]*model.WebhookSubscription, error) {
+	if _, err := service.getAdmin(username); err != nil {
+		return nil, err
+	}
+
+	return service.webhookRepository.ListSubscriptions()
+}
+
+// UpdateSubscription replaces the url and event types of a subscription, and
+// pauses or resumes it. Deliveries of a paused subscription stay queued.
+func (service *WebhookService) UpdateSubscription(username string, subscriptionId int32, url string, eventTypes []string, active bool) (*model.WebhookSubscription, error) {
+	if _, err := service.getAdmin(username); err != nil {
+		return nil, err
+	}
+
+	eventTypes = uniqueEventTypes(eventTypes)
+	if err := service.validateSubscription(url, eventTypes); err != nil {
+		return nil, err
+	}
+
+	subscription, err := service.webhookRepository.UpdateSubscription(subscriptionId, url, eventTypes, active)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, errors.New("subscription does not exist")
+	}
+
+	return subscription, nil
+}
+
+func (service *WebhookService) DeleteSubscription(username string, subscriptionId int32) error {
+	if _, err := service.getAdmin(username); err != nil {
+		return err
+	}
+
+	found, err := service.webhookRepository.DeleteSubscription(subscriptionId)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("subscription does not exist")
+	}
+
+	return nil
+}
+
+// ListDeliveries returns a page of the deliveries of a subscription, newest
+// first. Dead deliveries, which ran out of attempts, are listed with the
+// dead status.
+func (service *WebhookService) ListDeliveries(username string, subscriptionId int32, status string, limit int32, offset int32) ([]*model.WebhookDelivery, error) {
+	if _, err := service.getAdmin(username); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || offset < 0 {
+		return nil, errors.New("limit must be greater than zero and offset can not be negative")
+	}
+	if err := model.ValidateWebhookDeliveryStatus(status); err != nil {
+		return nil, err
+	}
+
+	subscription, err := service.webhookRepository.GetSubscription(subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, errors.New("subscription does not exist")
+	}
+
+	return service.webhookRepository.ListDeliveries(subscriptionId, status, limit, offset)
+}
+
+// GetDeliveryLog returns a delivery along with the log of its attempts.
+func (service *WebhookService) GetDeliveryLog(username string, deliveryId int64) (*model.WebhookDelivery, []*model.WebhookAttempt, error) {
+	if _, err := service.getAdmin(username); err != nil {
+		return nil, nil, err
+	}
+
+	delivery, err := service.webhookRepository.GetDelivery(deliveryId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if delivery == nil {
+		return nil, nil, errors.New("delivery does not exist")
+	}
+
+	attempts, err := service.webhookRepository.ListAttempts(deliveryId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return delivery, attempts, nil
+}
+
+// Replay queues a delivery again with the same payload, as a new delivery
+// with its own attempts. It is how dead deliveries are sent once the
+// receiver is fixed.
+func (service *WebhookService) Replay(username string, deliveryId int64) (*model.WebhookDelivery, error) {
+	if _, err := service.getAdmin(username); err != nil {
+		return nil, err
+	}
+
+	delivery, err := service.webhookRepository.Replay(deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, errors.New("delivery does not exist")
+	}
+
+	return delivery, nil
+}
+
+func (service *WebhookService) getAdmin(username string) (*model.User, error) {
+	user, err := service.userRepository.GetByUsername(username)
+	if err != nil {
+		return nil, errors.New("user does not exist")
+	}
+	if !user.IsAdmin {
+		return nil, errors.New("user is not an admin")
+	}
+	return user, nil
+}
+
+// validateSubscription refuses URLs that point inside the network, unless
+// they are allowed by the guard. They are checked again on every delivery,
+// as the host may resolve elsewhere by then.
+func (service *WebhookService) validateSubscription(url string, eventTypes []string) error {
+	if err := service.guard.ValidateUrl(context.Background(), url); err != nil {
+		return err
+	}
+	return webhook.ValidateEventTypes(eventTypes)
+}
+
+func uniqueEventTypes(eventTypes []string) []string {
+	seen := make(map[string]bool, len(eventTypes))
+	unique := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !seen[eventType] {
+			seen[eventType] = true
+			unique = append(unique, eventType)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"symphony-api/internal/persistence/model"
+	"symphony-api/internal/webhook"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextDeliveryState(t *testing.T) {
+	status, retryIn := nextDeliveryState(true, 3, 10)
+	assert.Equal(t, model.WEBHOOK_DELIVERY_DELIVERED, status)
+	assert.Equal(t, time.Duration(0), retryIn)
+
+	status, retryIn = nextDeliveryState(false, 1, 10)
+	assert.Equal(t, model.WEBHOOK_DELIVERY_PENDING, status)
+	assert.Equal(t, webhook.BASE_RETRY_DELAY, retryIn)
+
+	status, retryIn = nextDeliveryState(false, 3, 10)
+	assert.Equal(t, model.WEBHOOK_DELIVERY_PENDING, status)
+	assert.Equal(t, 4*webhook.BASE_RETRY_DELAY, retryIn)
+
+	status, _ = nextDeliveryState(false, 10, 10)
+	assert.Equal(t, model.WEBHOOK_DELIVERY_DEAD, status)
+}
+
+func TestUniqueEventTypes(t *testing.T) {
+	eventTypes := uniqueEventTypes([]string{webhook.EVENT_POST_CREATED, webhook.EVENT_USER_CREATED, webhook.EVENT_POST_CREATED})
+
+	assert.Equal(t, []string{webhook.EVENT_POST_CREATED, webhook.EVENT_USER_CREATED}, eventTypes)
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+type LocalStorage struct {
+	directory string
+}
+
+// NewLocalStorage creates a storage that keeps every object as a file inside
+// directory. The directory is created if it does not exist.
+func NewLocalStorage(directory string) *LocalStorage {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		log.Fatalf("Failed to create media directory %s: %v", directory, err)
+	}
+
+	return &LocalStorage{
+		directory: directory,
+	}
+}
+
+func (storage *LocalStorage) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial object.
+	file, err := os.CreateTemp(storage.directory, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), storage.path(key))
+}
+
+func (storage *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(storage.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (storage *LocalStorage) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	err := os.Remove(storage.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (storage *LocalStorage) path(key string) string {
+	return filepath.Join(storage.directory, key)
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"symphony-api/pkg/config"
+)
+
+var ErrNotFound = errors.New("object not found")
+var ErrInvalidKey = errors.New("invalid object key")
+
+// Keys are flat file names, so they can be used as a path segment of the
+// local storage and of the S3 bucket without escaping.
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]{0,254}$`)
+
+// Storage stores binary objects, such as uploaded images, by key.
+type Storage interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorage creates the storage selected by the MEDIA_STORAGE environment
+// variable: "local" (default) keeps the objects in MEDIA_LOCAL_DIR and "s3"
+// keeps them in an S3 compatible bucket configured by the S3_* variables.
+func NewStorage() Storage {
+	return newStorage(
+		config.GetEnv("MEDIA_LOCAL_DIR", "uploads"),
+		config.GetEnv("S3_BUCKET", "symphony"),
+	)
+}
+
+// NewExportStorage creates the storage for data exports. It uses the same
+// backend as NewStorage, but keeps the objects apart from the public media in
+// EXPORT_LOCAL_DIR or in the S3_EXPORT_BUCKET bucket.
+func NewExportStorage() Storage {
+	return newStorage(
+		config.GetEnv("EXPORT_LOCAL_DIR", "exports"),
+		config.GetEnv("S3_EXPORT_BUCKET", "symphony-exports"),
+	)
+}
+
+func newStorage(localDir string, bucket string) Storage {
+	switch config.GetEnv("MEDIA_STORAGE", "local") {
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  config.GetEnv("S3_ENDPOINT", "http://localhost:9000"),
+			Bucket:    bucket,
+			Region:    config.GetEnv("S3_REGION", "us-east-1"),
+			AccessKey: config.GetEnv("S3_ACCESS_KEY", ""),
+			SecretKey: config.GetEnv("S3_SECRET_KEY", ""),
+		})
+	default:
+		return NewLocalStorage(localDir)
+	}
+}
+
+func ValidateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return ErrInvalidKey
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_PutGetDelete(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+	ctx := context.Background()
+
+	err := storage.Put(ctx, "photo.jpg", "image/jpeg", []byte("content"))
+	require.NoError(t, err)
+
+	reader, err := storage.Get(ctx, "photo.jpg")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	assert.Equal(t, "content", string(data))
+
+	require.NoError(t, storage.Delete(ctx, "photo.jpg"))
+	_, err = storage.Get(ctx, "photo.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStorage_InvalidKey(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+
+	for _, key := range []string{"../secret", "a/b.jpg", ".hidden", ""} {
+		err := storage.Put(context.Background(), key, "image/jpeg", []byte("content"))
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+// fakeS3 is a minimal in-memory stand-in for an S3 compatible service.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	auth    []string
+}
+
+func (s3 *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s3.mu.Lock()
+	defer s3.mu.Unlock()
+
+	s3.auth = append(s3.auth, r.Header.Get("Authorization"))
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s3.objects[r.URL.Path] = data
+	case http.MethodGet:
+		data, ok := s3.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(s3.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Storage_PutGetDelete(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	storage := NewS3Storage(S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "symphony",
+		Region:    "us-east-1",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	storage.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	require.NoError(t, storage.Put(ctx, "cover.png", "image/png", []byte("png")))
+	assert.Equal(t, []byte("png"), fake.objects["/symphony/cover.png"])
+
+	reader, err := storage.Get(ctx, "cover.png")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	assert.Equal(t, "png", string(data))
+
+	require.NoError(t, storage.Delete(ctx, "cover.png"))
+	_, err = storage.Get(ctx, "cover.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, auth := range fake.auth {
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/20240501/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+	}
+}
@@ -0,0 +1,130 @@
+package realtime
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Authenticator tells who opens a stream (the chat WebSocket or the event
+// stream) or acts on their own account, and whether the page doing so may.
+//
+// Streams and accounts carry private data, so the user is never taken from a
+// parameter.
+// Clients present a stream token, "<user id>.<expiry unix time>.<signature>"
+// where the signature is the hex HMAC-SHA256 of "<user id>.<expiry>" keyed
+// with the secret shared with the service that authenticates users, which
+// mints them with SignStreamToken. Browsers can not set headers on
+// WebSockets or EventSource, so the token is read from the Authorization
+// bearer header or, failing that, from the access_token parameter.
+//
+// Browsers always send Origin on these requests, so pages of other sites are
+// told apart by it. Requests without Origin do not come from a page.
+type Authenticator struct {
+	secret         []byte
+	allowedOrigins []string
+}
+
+// NewAuthenticator returns nil when there is no secret, in which case the
+// streams can not be authenticated and must not be served. With no allowed
+// origins only pages of the host serving the API are accepted.
+func NewAuthenticator(secret string, allowedOrigins []string) *Authenticator {
+	if secret == "" {
+		return nil
+	}
+
+	origins := make([]string, 0, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, strings.TrimSuffix(origin, "/"))
+		}
+	}
+
+	return &Authenticator{
+		secret:         []byte(secret),
+		allowedOrigins: origins,
+	}
+}
+
+// Authenticate returns the id of the user the stream token of the request
+// was issued to.
+func (authenticator *Authenticator) Authenticate(r *http.Request) (int32, error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		token = r.URL.Query().Get("access_token")
+	}
+	if token == "" {
+		return 0, errors.New("missing stream token")
+	}
+
+	return VerifyStreamToken(authenticator.secret, token, time.Now())
+}
+
+// CheckOrigin rejects requests made by pages of origins that are not allowed.
+func (authenticator *Authenticator) CheckOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	if len(authenticator.allowedOrigins) == 0 {
+		parsed, err := url.Parse(origin)
+		if err == nil && parsed.Host == r.Host {
+			return nil
+		}
+		return errors.New("origin not allowed: " + origin)
+	}
+
+	for _, allowed := range authenticator.allowedOrigins {
+		if origin == allowed {
+			return nil
+		}
+	}
+	return errors.New("origin not allowed: " + origin)
+}
+
+// SignStreamToken issues a stream token for the user, valid until expiresAt.
+func SignStreamToken(secret []byte, userId int32, expiresAt time.Time) string {
+	claims := strconv.FormatInt(int64(userId), 10) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return claims + "." + signClaims(secret, claims)
+}
+
+// VerifyStreamToken returns the user a stream token was issued to, failing
+// when it was not signed with the secret or has expired.
+func VerifyStreamToken(secret []byte, token string, now time.Time) (int32, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, errors.New("malformed stream token")
+	}
+
+	claims := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(signClaims(secret, claims)), []byte(parts[2])) {
+		return 0, errors.New("invalid stream token")
+	}
+
+	userId, err := strconv.ParseInt(parts[0], 10, 32)
+	if err != nil || userId <= 0 {
+		return 0, errors.New("malformed stream token")
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, errors.New("malformed stream token")
+	}
+	if !now.Before(time.Unix(expiresAt, 0)) {
+		return 0, errors.New("stream token expired")
+	}
+
+	return int32(userId), nil
+}
+
+func signClaims(secret []byte, claims string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(claims))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,79 @@
+package realtime
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyStreamToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1700000000, 0)
+	token := SignStreamToken(secret, 7, now.Add(time.Hour))
+
+	userId, err := VerifyStreamToken(secret, token, now)
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), userId)
+
+	_, err = VerifyStreamToken([]byte("other"), token, now)
+	assert.Error(t, err)
+
+	_, err = VerifyStreamToken(secret, token, now.Add(2*time.Hour))
+	assert.Error(t, err)
+
+	// Changing the user invalidates the signature.
+	forged := "8" + token[1:]
+	_, err = VerifyStreamToken(secret, forged, now)
+	assert.Error(t, err)
+
+	_, err = VerifyStreamToken(secret, "7.123", now)
+	assert.Error(t, err)
+}
+
+func TestNewAuthenticator_WithoutSecret(t *testing.T) {
+	assert.Nil(t, NewAuthenticator("", nil))
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	authenticator := NewAuthenticator("secret", nil)
+	token := SignStreamToken([]byte("secret"), 3, time.Now().Add(time.Minute))
+
+	request := httptest.NewRequest("GET", "/api/events", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	userId, err := authenticator.Authenticate(request)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), userId)
+
+	request = httptest.NewRequest("GET", "/api/events?access_token="+token, nil)
+	userId, err = authenticator.Authenticate(request)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), userId)
+
+	request = httptest.NewRequest("GET", "/api/events?username=john", nil)
+	_, err = authenticator.Authenticate(request)
+	assert.Error(t, err)
+}
+
+func TestAuthenticator_CheckOrigin(t *testing.T) {
+	sameHost := NewAuthenticator("secret", nil)
+	allowList := NewAuthenticator("secret", []string{"https://app.symphony.com/", " "})
+
+	request := httptest.NewRequest("GET", "http://api.symphony.com/api/chat/ws", nil)
+	assert.NoError(t, sameHost.CheckOrigin(request))
+	assert.NoError(t, allowList.CheckOrigin(request))
+
+	request.Header.Set("Origin", "http://api.symphony.com")
+	assert.NoError(t, sameHost.CheckOrigin(request))
+	assert.Error(t, allowList.CheckOrigin(request))
+
+	request.Header.Set("Origin", "https://app.symphony.com")
+	assert.Error(t, sameHost.CheckOrigin(request))
+	assert.NoError(t, allowList.CheckOrigin(request))
+
+	request.Header.Set("Origin", "https://evil.example.com")
+	assert.Error(t, sameHost.CheckOrigin(request))
+	assert.Error(t, allowList.CheckOrigin(request))
+}
@@ -0,0 +1,60 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// Broker carries events to the hubs of every API instance. Publish must hand
+// the event to every handler registered with Subscribe, in this process and
+// in the others sharing the broker.
+type Broker interface {
+	Publish(ctx context.Context, event *Event) error
+	// Subscribe registers a handler for every published event and returns a
+	// function that removes it.
+	Subscribe(handler func(*Event)) func()
+}
+
+// LocalBroker delivers events to the handlers of the current process only. It
+// is enough while the API runs as a single instance.
+type LocalBroker struct {
+	mu       sync.RWMutex
+	handlers map[int]func(*Event)
+	nextId   int
+}
+
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{
+		handlers: make(map[int]func(*Event)),
+	}
+}
+
+func (broker *LocalBroker) Publish(ctx context.Context, event *Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	broker.mu.RLock()
+	defer broker.mu.RUnlock()
+
+	for _, handler := range broker.handlers {
+		handler(event)
+	}
+
+	return nil
+}
+
+func (broker *LocalBroker) Subscribe(handler func(*Event)) func() {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	id := broker.nextId
+	broker.nextId++
+	broker.handlers[id] = handler
+
+	return func() {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		delete(broker.handlers, id)
+	}
+}
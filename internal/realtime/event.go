@@ -0,0 +1,65 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	EVENT_CHAT_MESSAGE          = "chat.message"
+	EVENT_CHAT_MEMBER_ADDED     = "chat.member_added"
+	EVENT_CHAT_MEMBER_REMOVED   = "chat.member_removed"
+	EVENT_CHAT_RENAMED          = "chat.renamed"
+	EVENT_CHAT_READ             = "chat.read"
+	EVENT_CHAT_MESSAGE_EDITED   = "chat.message_edited"
+	EVENT_CHAT_MESSAGE_DELETED  = "chat.message_deleted"
+	EVENT_CHAT_REACTION_ADDED   = "chat.reaction_added"
+	EVENT_CHAT_REACTION_REMOVED = "chat.reaction_removed"
+)
+
+// Events addressed to a single user, delivered through their event stream.
+const (
+	EVENT_FRIEND_REQUEST  = "friend.request"
+	EVENT_FRIEND_ACCEPTED = "friend.accepted"
+	EVENT_POST_LIKED      = "post.liked"
+	EVENT_POST_COMMENTED  = "post.commented"
+	EVENT_POST_MENTION    = "post.mention"
+
+	// Sent when a stream can not resume from the last event the client got,
+	// which must then reload its state.
+	EVENT_STREAM_RESET = "stream.reset"
+)
+
+// Event is something that happened and that subscribers of its topic should
+// learn about right away. Data is kept encoded so events can cross process
+// boundaries through a Broker unchanged. Id is only set on events that were
+// persisted, so clients can resume after them.
+type Event struct {
+	Id    int64           `json:"id,omitempty"`
+	Topic string          `json:"topic"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+func NewEvent(topic string, eventType string, data any) (*Event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		Topic: topic,
+		Type:  eventType,
+		Data:  encoded,
+	}, nil
+}
+
+// ChatTopic is the topic of the events of a chat.
+func ChatTopic(chatId int32) string {
+	return fmt.Sprintf("chat:%d", chatId)
+}
+
+// UserTopic is the topic of the events addressed to a user.
+func UserTopic(userId int32) string {
+	return fmt.Sprintf("user:%d", userId)
+}
@@ -0,0 +1,176 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const DEFAULT_SUBSCRIBER_BUFFER = 64
+
+var ErrSlowConsumer = errors.New("subscriber is not keeping up with its events")
+
+// Subscriber receives the events of the topics it joined. Its channel is
+// closed when it is unsubscribed or dropped by the hub.
+type Subscriber struct {
+	events chan *Event
+	err    error
+}
+
+// Events returns the channel the events of the subscriber arrive on.
+func (subscriber *Subscriber) Events() <-chan *Event {
+	return subscriber.events
+}
+
+// Err tells why the events channel was closed: nil when the subscriber was
+// unsubscribed and ErrSlowConsumer when the hub dropped it. It must only be
+// called once the channel is closed.
+func (subscriber *Subscriber) Err() error {
+	return subscriber.err
+}
+
+// Hub fans the events coming from a Broker out to the local subscribers of
+// their topics. Delivery never blocks: a subscriber whose buffer is full is
+// dropped, so one slow connection can not hold back the others.
+type Hub struct {
+	broker     Broker
+	bufferSize int
+
+	mu          sync.RWMutex
+	topics      map[string]map[*Subscriber]bool
+	subscribers map[*Subscriber]map[string]bool
+
+	stop func()
+}
+
+func NewHub(broker Broker, bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DEFAULT_SUBSCRIBER_BUFFER
+	}
+
+	hub := &Hub{
+		broker:      broker,
+		bufferSize:  bufferSize,
+		topics:      make(map[string]map[*Subscriber]bool),
+		subscribers: make(map[*Subscriber]map[string]bool),
+	}
+	hub.stop = broker.Subscribe(hub.deliver)
+
+	return hub
+}
+
+// Publish sends the event through the broker to the subscribers of its topic
+// on every instance.
+func (hub *Hub) Publish(ctx context.Context, event *Event) error {
+	return hub.broker.Publish(ctx, event)
+}
+
+func (hub *Hub) Subscribe() *Subscriber {
+	subscriber := &Subscriber{
+		events: make(chan *Event, hub.bufferSize),
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.subscribers[subscriber] = make(map[string]bool)
+
+	return subscriber
+}
+
+// Join adds a topic to the subscriber. It does nothing once the subscriber is
+// gone.
+func (hub *Hub) Join(subscriber *Subscriber, topic string) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	topics, ok := hub.subscribers[subscriber]
+	if !ok {
+		return
+	}
+
+	topics[topic] = true
+	if hub.topics[topic] == nil {
+		hub.topics[topic] = make(map[*Subscriber]bool)
+	}
+	hub.topics[topic][subscriber] = true
+}
+
+func (hub *Hub) Leave(subscriber *Subscriber, topic string) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if topics, ok := hub.subscribers[subscriber]; ok {
+		delete(topics, topic)
+		hub.removeFromTopic(subscriber, topic)
+	}
+}
+
+// Unsubscribe removes the subscriber from all its topics and closes its
+// channel.
+func (hub *Hub) Unsubscribe(subscriber *Subscriber) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.drop(subscriber, nil)
+}
+
+// Close stops receiving events from the broker and unsubscribes everyone.
+func (hub *Hub) Close() {
+	hub.stop()
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for subscriber := range hub.subscribers {
+		hub.drop(subscriber, nil)
+	}
+}
+
+func (hub *Hub) deliver(event *Event) {
+	slow := make([]*Subscriber, 0)
+
+	hub.mu.RLock()
+	for subscriber := range hub.topics[event.Topic] {
+		select {
+		case subscriber.events <- event:
+		default:
+			slow = append(slow, subscriber)
+		}
+	}
+	hub.mu.RUnlock()
+
+	if len(slow) == 0 {
+		return
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for _, subscriber := range slow {
+		hub.drop(subscriber, ErrSlowConsumer)
+	}
+}
+
+// drop must be called with the write lock held.
+func (hub *Hub) drop(subscriber *Subscriber, err error) {
+	topics, ok := hub.subscribers[subscriber]
+	if !ok {
+		return
+	}
+
+	for topic := range topics {
+		hub.removeFromTopic(subscriber, topic)
+	}
+	delete(hub.subscribers, subscriber)
+
+	subscriber.err = err
+	close(subscriber.events)
+}
+
+func (hub *Hub) removeFromTopic(subscriber *Subscriber, topic string) {
+	delete(hub.topics[topic], subscriber)
+	if len(hub.topics[topic]) == 0 {
+		delete(hub.topics, topic)
+	}
+}
@@ -0,0 +1,96 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(t *testing.T, topic string) *Event {
+	event, err := NewEvent(topic, EVENT_CHAT_MESSAGE, map[string]string{"message": "hi"})
+	require.NoError(t, err)
+	return event
+}
+
+func TestHub_DeliversToTopicSubscribers(t *testing.T) {
+	hub := NewHub(NewLocalBroker(), 4)
+	defer hub.Close()
+
+	member := hub.Subscribe()
+	hub.Join(member, ChatTopic(1))
+	outsider := hub.Subscribe()
+	hub.Join(outsider, ChatTopic(2))
+
+	event := newTestEvent(t, ChatTopic(1))
+	require.NoError(t, hub.Publish(context.Background(), event))
+
+	assert.Equal(t, event, <-member.Events())
+	assert.Len(t, outsider.Events(), 0)
+}
+
+func TestHub_Leave(t *testing.T) {
+	hub := NewHub(NewLocalBroker(), 4)
+	defer hub.Close()
+
+	subscriber := hub.Subscribe()
+	hub.Join(subscriber, ChatTopic(1))
+	hub.Leave(subscriber, ChatTopic(1))
+
+	require.NoError(t, hub.Publish(context.Background(), newTestEvent(t, ChatTopic(1))))
+
+	assert.Len(t, subscriber.Events(), 0)
+}
+
+func TestHub_DropsSlowConsumer(t *testing.T) {
+	hub := NewHub(NewLocalBroker(), 1)
+	defer hub.Close()
+
+	slow := hub.Subscribe()
+	hub.Join(slow, ChatTopic(1))
+	fast := hub.Subscribe()
+	hub.Join(fast, ChatTopic(1))
+
+	require.NoError(t, hub.Publish(context.Background(), newTestEvent(t, ChatTopic(1))))
+	<-fast.Events()
+	require.NoError(t, hub.Publish(context.Background(), newTestEvent(t, ChatTopic(1))))
+
+	<-slow.Events()
+	_, open := <-slow.Events()
+	assert.False(t, open)
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+
+	_, open = <-fast.Events()
+	assert.True(t, open)
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	hub := NewHub(NewLocalBroker(), 4)
+	defer hub.Close()
+
+	subscriber := hub.Subscribe()
+	hub.Join(subscriber, ChatTopic(1))
+	hub.Unsubscribe(subscriber)
+	hub.Unsubscribe(subscriber)
+
+	_, open := <-subscriber.Events()
+	assert.False(t, open)
+	assert.NoError(t, subscriber.Err())
+}
+
+func TestHub_SharesBrokerAcrossHubs(t *testing.T) {
+	broker := NewLocalBroker()
+	first := NewHub(broker, 4)
+	defer first.Close()
+	second := NewHub(broker, 4)
+	defer second.Close()
+
+	subscriber := second.Subscribe()
+	second.Join(subscriber, ChatTopic(1))
+
+	event := newTestEvent(t, ChatTopic(1))
+	require.NoError(t, first.Publish(context.Background(), event))
+
+	assert.Equal(t, event, <-subscriber.Events())
+}
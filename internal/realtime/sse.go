@@ -0,0 +1,35 @@
+package realtime
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteSSE writes the event in the Server-Sent Events format. The id line is
+// only written for persisted events, so the browser only resumes after them.
+func WriteSSE(w io.Writer, event *Event) error {
+	var frame strings.Builder
+
+	if event.Id > 0 {
+		fmt.Fprintf(&frame, "id: %d\n", event.Id)
+	}
+	fmt.Fprintf(&frame, "event: %s\n", event.Type)
+
+	// Data is JSON, which never needs a raw newline, but a line break inside
+	// it would end the field, so every line gets its own data prefix.
+	for _, line := range strings.Split(string(event.Data), "\n") {
+		fmt.Fprintf(&frame, "data: %s\n", line)
+	}
+	frame.WriteString("\n")
+
+	_, err := io.WriteString(w, frame.String())
+	return err
+}
+
+// WriteSSEComment writes a comment line, which clients ignore. It keeps idle
+// connections from being closed by proxies.
+func WriteSSEComment(w io.Writer, comment string) error {
+	_, err := fmt.Fprintf(w, ": %s\n\n", comment)
+	return err
+}
@@ -0,0 +1,32 @@
+package realtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSSE(t *testing.T) {
+	var buffer bytes.Buffer
+	event := &Event{Id: 42, Topic: UserTopic(1), Type: EVENT_FRIEND_REQUEST, Data: json.RawMessage(`{"from":"john"}`)}
+
+	assert.NoError(t, WriteSSE(&buffer, event))
+	assert.Equal(t, "id: 42\nevent: friend.request\ndata: {\"from\":\"john\"}\n\n", buffer.String())
+}
+
+func TestWriteSSE_MultilineWithoutId(t *testing.T) {
+	var buffer bytes.Buffer
+	event := &Event{Type: EVENT_POST_MENTION, Data: json.RawMessage("{\n\"post_id\":1\n}")}
+
+	assert.NoError(t, WriteSSE(&buffer, event))
+	assert.Equal(t, "event: post.mention\ndata: {\ndata: \"post_id\":1\ndata: }\n\n", buffer.String())
+}
+
+func TestWriteSSEComment(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.NoError(t, WriteSSEComment(&buffer, "keepalive"))
+	assert.Equal(t, ": keepalive\n\n", buffer.String())
+}
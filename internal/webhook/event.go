@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Platform events partners can subscribe to. Payloads only carry ids and
+// names, partners fetch anything else through the API.
+const (
+	EVENT_USER_CREATED            = "user.created"
+	EVENT_POST_CREATED            = "post.created"
+	EVENT_COMMUNITY_CREATED       = "community.created"
+	EVENT_COMMUNITY_MEMBER_JOINED = "community.member_joined"
+)
+
+var EVENT_TYPES = []string{
+	EVENT_USER_CREATED,
+	EVENT_POST_CREATED,
+	EVENT_COMMUNITY_CREATED,
+	EVENT_COMMUNITY_MEMBER_JOINED,
+}
+
+// Payload is the body of every delivery. It is built once when the event
+// happens, so retries and replays send exactly the same bytes.
+type Payload struct {
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func NewPayload(eventType string, data any) ([]byte, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&Payload{
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      encoded,
+	})
+}
+
+// ValidateEventTypes checks that at least one event type is given and that
+// all of them are known.
+func ValidateEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return errors.New("at least one event type is required")
+	}
+
+	for _, eventType := range eventTypes {
+		known := false
+		for _, candidate := range EVENT_TYPES {
+			if eventType == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errors.New("invalid event type: " + eventType)
+		}
+	}
+
+	return nil
+}
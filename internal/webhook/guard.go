@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// Ranges that are not covered by the netip predicates but must not be
+// reached either: "this network", carrier-grade NAT, IETF protocol
+// assignments, benchmarking, reserved and NAT64.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// Guard keeps deliveries from reaching the internal network: loopback,
+// private, link-local (cloud metadata) and reserved addresses. URLs are
+// checked when they are registered and addresses again when connecting, so
+// a name that later resolves to an internal address is still refused.
+// Allowing private targets is only meant for development, to deliver to a
+// receiver running locally.
+type Guard struct {
+	allowPrivate bool
+	resolver     *net.Resolver
+}
+
+func NewGuard(allowPrivate bool) *Guard {
+	return &Guard{
+		allowPrivate: allowPrivate,
+		resolver:     net.DefaultResolver,
+	}
+}
+
+// ValidateUrl checks that deliveries can be sent to the URL.
+func (guard *Guard) ValidateUrl(ctx context.Context, rawUrl string) error {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return errors.New("invalid webhook url")
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return errors.New("webhook url must be http or https")
+	}
+	if parsed.Hostname() == "" {
+		return errors.New("webhook url must have a host")
+	}
+	if parsed.User != nil {
+		return errors.New("webhook url can not have credentials")
+	}
+	if guard.allowPrivate {
+		return nil
+	}
+
+	if addr, err := netip.ParseAddr(parsed.Hostname()); err == nil {
+		return checkAddress(addr)
+	}
+
+	addrs, err := guard.resolver.LookupNetIP(ctx, "ip", parsed.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return errors.New("could not resolve webhook host: " + parsed.Hostname())
+	}
+	for _, addr := range addrs {
+		if err := checkAddress(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// control is run by the dialer with the address about to be connected to,
+// after the name was resolved.
+func (guard *Guard) control(network string, address string, _ syscall.RawConn) error {
+	if guard.allowPrivate {
+		return nil
+	}
+
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	return checkAddress(addrPort.Addr())
+}
+
+// IsInternalAddress tells whether the address belongs to the internal
+// network or is not meant to be reached at all.
+func IsInternalAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkAddress(addr netip.Addr) error {
+	if IsInternalAddress(addr) {
+		return errors.New("webhook target is an internal address: " + addr.String())
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	EVENT_HEADER    = "X-Symphony-Event"
+	DELIVERY_HEADER = "X-Symphony-Delivery"
+	USER_AGENT      = "Symphony-Webhooks/1.0"
+
+	DEFAULT_TIMEOUT = 10 * time.Second
+
+	// How much of the response body is kept in the delivery log.
+	MAX_LOGGED_RESPONSE_BYTES = 1024
+
+	// Retries wait BASE_RETRY_DELAY, doubling after every failed attempt up
+	// to MAX_RETRY_DELAY.
+	BASE_RETRY_DELAY = 30 * time.Second
+	MAX_RETRY_DELAY  = 6 * time.Hour
+)
+
+// Delivery is a signed POST of a payload to the URL of a subscription.
+type Delivery struct {
+	Id        int64
+	EventType string
+	Url       string
+	Secret    string
+	Payload   []byte
+}
+
+// Result is the outcome of one attempt. StatusCode is 0 when no response was
+// received, in which case Error tells why.
+type Result struct {
+	StatusCode   int
+	ResponseBody string
+	Error        string
+	Duration     time.Duration
+}
+
+// Succeeded tells whether the receiver acknowledged the delivery with a 2xx.
+func (result *Result) Succeeded() bool {
+	return result.StatusCode >= 200 && result.StatusCode < 300
+}
+
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender returns a sender that only connects to the addresses the guard
+// allows. Proxies from the environment are ignored, since connecting through
+// them would go around the guard.
+func NewSender(timeout time.Duration, guard *Guard) *Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: guard.control,
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// A redirect would send the signed payload somewhere the
+			// subscription did not name, so it is reported as is.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send makes one attempt to deliver. Failures are reported in the result, to
+// be logged and retried, never as an error.
+func (sender *Sender) Send(ctx context.Context, delivery *Delivery) *Result {
+	start := time.Now()
+	result := &Result{}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", USER_AGENT)
+	request.Header.Set(EVENT_HEADER, delivery.EventType)
+	request.Header.Set(DELIVERY_HEADER, strconv.FormatInt(delivery.Id, 10))
+	request.Header.Set(SIGNATURE_HEADER, Sign(delivery.Secret, start, delivery.Payload))
+
+	response, err := sender.client.Do(request)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, MAX_LOGGED_RESPONSE_BYTES))
+	// Drain a little more so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	result.StatusCode = response.StatusCode
+	result.ResponseBody = string(body)
+	if !result.Succeeded() {
+		result.Error = "unexpected status " + response.Status
+	}
+
+	return result
+}
+
+// RetryDelay returns how long to wait after the given failed attempt, the
+// first being 1.
+func RetryDelay(attempt int) time.Duration {
+	delay := BASE_RETRY_DELAY
+	for i := 1; i < attempt && delay < MAX_RETRY_DELAY; i++ {
+		delay *= 2
+	}
+	return min(delay, MAX_RETRY_DELAY)
+}
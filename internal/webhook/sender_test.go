@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender_Send(t *testing.T) {
+	payload, err := NewPayload(EVENT_USER_CREATED, map[string]any{"username": "john"})
+	require.NoError(t, err)
+
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// The stand-in receiver listens on loopback.
+	result := NewSender(time.Second, NewGuard(true)).Send(context.Background(), &Delivery{
+		Id:        7,
+		EventType: EVENT_USER_CREATED,
+		Url:       server.URL,
+		Secret:    "secret",
+		Payload:   payload,
+	})
+
+	assert.True(t, result.Succeeded())
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, "ok", result.ResponseBody)
+	assert.Empty(t, result.Error)
+
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, EVENT_USER_CREATED, received.Header.Get(EVENT_HEADER))
+	assert.Equal(t, "7", received.Header.Get(DELIVERY_HEADER))
+	assert.Equal(t, payload, body)
+	assert.NoError(t, Verify("secret", received.Header.Get(SIGNATURE_HEADER), body, time.Minute, time.Now()))
+
+	var decoded Payload
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, EVENT_USER_CREATED, decoded.Type)
+	assert.JSONEq(t, `{"username":"john"}`, string(decoded.Data))
+}
+
+func TestSender_Send_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/error":
+			http.Error(w, "broken", http.StatusInternalServerError)
+		case "/redirect":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	sender := NewSender(50*time.Millisecond, NewGuard(true))
+	send := func(path string) *Result {
+		return sender.Send(context.Background(), &Delivery{Id: 1, Url: server.URL + path, Secret: "secret", Payload: []byte(`{}`)})
+	}
+
+	result := send("/error")
+	assert.False(t, result.Succeeded())
+	assert.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	assert.Equal(t, "broken\n", result.ResponseBody)
+	assert.NotEmpty(t, result.Error)
+
+	result = send("/redirect")
+	assert.False(t, result.Succeeded())
+	assert.Equal(t, http.StatusFound, result.StatusCode)
+
+	result = send("/slow")
+	assert.False(t, result.Succeeded())
+	assert.Equal(t, 0, result.StatusCode)
+	assert.NotEmpty(t, result.Error)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(1))
+	assert.Equal(t, time.Minute, RetryDelay(2))
+	assert.Equal(t, 4*time.Minute, RetryDelay(4))
+	assert.Equal(t, MAX_RETRY_DELAY, RetryDelay(20))
+}
+
+func TestSender_Send_RefusesInternalAddresses(t *testing.T) {
+	reached := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer server.Close()
+
+	result := NewSender(time.Second, NewGuard(false)).Send(context.Background(), &Delivery{
+		Id:      1,
+		Url:     server.URL,
+		Secret:  "secret",
+		Payload: []byte(`{}`),
+	})
+
+	assert.False(t, result.Succeeded())
+	assert.Contains(t, result.Error, "internal address")
+	assert.False(t, reached)
+}
+
+func TestValidateEventTypes(t *testing.T) {
+	assert.NoError(t, ValidateEventTypes([]string{EVENT_USER_CREATED, EVENT_POST_CREATED}))
+	assert.Error(t, ValidateEventTypes(nil))
+	assert.Error(t, ValidateEventTypes([]string{"user.deleted"}))
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const SIGNATURE_HEADER = "X-Symphony-Signature"
+
+// Sign returns the signature header of a delivery, "t=<unix time>,v1=<hex>",
+// where v1 is the HMAC-SHA256 of "<unix time>.<body>" keyed with the secret
+// of the subscription. The time is signed too so captured deliveries can not
+// be replayed later by someone else.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + computeSignature(secret, unix, body)
+}
+
+// Verify checks a signature header made by Sign, rejecting it when it is
+// older than tolerance. It is what receivers are expected to do.
+func Verify(secret string, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var unix, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signature = value
+		}
+	}
+	if unix == "" || signature == "" {
+		return errors.New("malformed signature header")
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return errors.New("malformed signature timestamp")
+	}
+	if now.Sub(time.Unix(seconds, 0)).Abs() > tolerance {
+		return errors.New("signature timestamp is outside the tolerance")
+	}
+
+	expected := computeSignature(secret, unix, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
+
+// NewSecret generates the secret of a new subscription.
+func NewSecret() (string, error) {
+	random := make([]byte, 24)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(random), nil
+}
+
+func computeSignature(secret string, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", time.Unix(1700000000, 0), []byte(`{"type":"user.created"}`))
+
+	assert.Equal(t, "t=1700000000,v1=183b761865ab7e9c02fe7603937d181ce1482da954b1fecf912269e22500ac37", signature)
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"user.created"}`)
+	header := Sign("secret", now, body)
+
+	assert.NoError(t, Verify("secret", header, body, 5*time.Minute, now.Add(time.Minute)))
+	assert.Error(t, Verify("other", header, body, 5*time.Minute, now))
+	assert.Error(t, Verify("secret", header, []byte(`{"type":"post.created"}`), 5*time.Minute, now))
+	assert.Error(t, Verify("secret", header, body, 5*time.Minute, now.Add(10*time.Minute)))
+	assert.Error(t, Verify("secret", "v1=abc", body, 5*time.Minute, now))
+}
+
+func TestNewSecret(t *testing.T) {
+	secret1, err := NewSecret()
+	require.NoError(t, err)
+	secret2, err := NewSecret()
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^whsec_[0-9a-f]{48}$`, secret1)
+	assert.NotEqual(t, secret1, secret2)
+}
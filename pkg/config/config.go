@@ -1,6 +1,10 @@
 package config
 
-import "os"
+import (
+	"log"
+	"os"
+	"strconv"
+)
 
 // GetEnv retrieves the value of the environment variable named by key.
 // If the variable is not set, it returns the defaultValue.
@@ -14,3 +18,34 @@ func GetEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// GetEnvInt works like GetEnv for integer settings. If the variable is set
+// but is not a valid integer, it logs the problem and returns the defaultValue.
+func GetEnvInt(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q, using %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// GetEnvBool works like GetEnvInt for boolean settings.
+func GetEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q, using %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
@@ -307,11 +307,16 @@ def populate_postgresql():
         user1, user2 = random.sample(users, 2)
         
         # Create friendship in Neo4j
-        friendship_data = {
-            "username1": user1["username"],
-            "username2": user2["username"]
+        friend_request_data = {
+            "username": user1["username"],
+            "target": user2["username"]
+        }
+        make_request("POST", f"{API_BASE_URL}/api/user/send_friend_request", friend_request_data)
+        accept_data = {
+            "username": user2["username"],
+            "requester": user1["username"]
         }
-        make_request("POST", f"{API_BASE_URL}/api/user/create_friendship", friendship_data)
+        make_request("POST", f"{API_BASE_URL}/api/user/accept_friend_request", accept_data)
         
         # Create chat
         chat_data = {
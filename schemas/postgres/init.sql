@@ -1,20 +1,40 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent is only STABLE, so it can not be used in an index directly.
+CREATE FUNCTION immutable_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
 CREATE TABLE users (
     id SERIAL PRIMARY KEY,
-    username VARCHAR(50) NOT NULL,
+    username VARCHAR(50) NOT NULL UNIQUE,
     fullname VARCHAR(100) NOT NULL,
     email VARCHAR(100) NOT NULL UNIQUE,
     telephone VARCHAR(20),
+    bio VARCHAR(500),
+    avatar_url TEXT,
     birth_date DATE NOT NULL,
     register_date TIMESTAMP NOT NULL DEFAULT now(),
-    last_access TIMESTAMP NOT NULL DEFAULT now()
+    last_access TIMESTAMP NOT NULL DEFAULT now(),
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    profile_visibility VARCHAR(20) NOT NULL DEFAULT 'public' CHECK (profile_visibility IN ('public', 'friends', 'private')),
+    message_permission VARCHAR(20) NOT NULL DEFAULT 'everyone' CHECK (message_permission IN ('everyone', 'friends', 'nobody'))
 );
 
+CREATE INDEX users_username_search_idx ON users USING gin (immutable_unaccent(lower(username)) gin_trgm_ops);
+CREATE INDEX users_fullname_search_idx ON users USING gin (immutable_unaccent(lower(fullname)) gin_trgm_ops);
+
 CREATE TABLE post (
     id SERIAL PRIMARY KEY,
     user_id INTEGER NOT NULL,
     text TEXT NOT NULL,
     url_foto TEXT,
     like_count INTEGER DEFAULT 0,
+    attachment_type VARCHAR(20) CHECK (attachment_type IN ('song', 'artist', 'playlist')),
+    attachment_id VARCHAR(24),
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    CHECK ((attachment_type IS NULL) = (attachment_id IS NULL)),
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
@@ -33,10 +53,29 @@ CREATE TABLE post_comment(
     post_id INTEGER NOT NULL,
     text TEXT NOT NULL,
     commented_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
     FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE
 );
 
+CREATE TABLE post_hashtag (
+    post_id INTEGER NOT NULL,
+    tag VARCHAR(100) NOT NULL,
+    tagged_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, tag),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE
+);
+
+CREATE INDEX post_hashtag_tag_idx ON post_hashtag (tag, tagged_at);
+
+CREATE TABLE post_mention (
+    post_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (post_id, user_id),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
 CREATE TABLE music_history (
     id SERIAL PRIMARY KEY,
     user_id INTEGER NOT NULL,
@@ -49,7 +88,8 @@ CREATE TABLE community (
     id SERIAL PRIMARY KEY,
     community_name VARCHAR(100) NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    description TEXT
+    description TEXT,
+    hidden BOOLEAN NOT NULL DEFAULT FALSE
 );
 
 CREATE TABLE community_posts(
@@ -70,12 +110,19 @@ CREATE TABLE user_community (
 
 CREATE TABLE chat (
     chat_id SERIAL PRIMARY KEY,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    name VARCHAR(100),
+    is_group BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (is_group = (name IS NOT NULL))
 );
 
 CREATE TABLE chat_participants (
     user_id INTEGER NOT NULL,
     chat_id INTEGER NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'member')),
+    joined_at TIMESTAMP NOT NULL DEFAULT now(),
+    last_read_message_id INTEGER,
+    last_read_at TIMESTAMP,
     PRIMARY KEY (user_id, chat_id),
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
     FOREIGN KEY (chat_id) REFERENCES chat(chat_id) ON DELETE CASCADE
@@ -87,6 +134,152 @@ CREATE TABLE chat_message (
     chat_id INTEGER NOT NULL,
     message TEXT NOT NULL,
     sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    edited_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    attachment_type VARCHAR(20) CHECK (attachment_type IN ('song', 'artist', 'playlist')),
+    attachment_id VARCHAR(24),
+    CHECK ((attachment_type IS NULL) = (attachment_id IS NULL)),
     FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL,
     FOREIGN KEY (chat_id) REFERENCES chat(chat_id) ON DELETE CASCADE
-);
\ No newline at end of file
+);
+
+CREATE INDEX chat_message_chat_idx ON chat_message (chat_id, message_id);
+CREATE INDEX chat_message_search_idx ON chat_message USING gin (to_tsvector('simple', immutable_unaccent(message)));
+
+CREATE TABLE chat_message_edit (
+    edit_id SERIAL PRIMARY KEY,
+    message_id INTEGER NOT NULL,
+    message TEXT NOT NULL,
+    edited_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (message_id) REFERENCES chat_message(message_id) ON DELETE CASCADE
+);
+
+CREATE INDEX chat_message_edit_message_idx ON chat_message_edit (message_id, edit_id);
+
+CREATE TABLE chat_message_reaction (
+    message_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    emoji VARCHAR(32) NOT NULL,
+    reacted_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (message_id, user_id, emoji),
+    FOREIGN KEY (message_id) REFERENCES chat_message(message_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE report (
+    report_id SERIAL PRIMARY KEY,
+    reporter_id INTEGER NOT NULL,
+    content_type VARCHAR(20) NOT NULL CHECK (content_type IN ('post', 'comment', 'chat_message', 'community')),
+    content_id INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'dismissed', 'hidden', 'deleted')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    reviewed_by INTEGER,
+    reviewed_at TIMESTAMP,
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX report_open_unique_idx ON report (reporter_id, content_type, content_id) WHERE status = 'open';
+CREATE INDEX report_content_idx ON report (content_type, content_id, status);
+
+CREATE TABLE data_export (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'done', 'failed')),
+    storage_key TEXT,
+    requested_at TIMESTAMP NOT NULL DEFAULT now(),
+    completed_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_event (
+    event_id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_event_user_idx ON user_event (user_id, event_id);
+CREATE INDEX user_event_created_idx ON user_event (created_at);
+
+CREATE TABLE notification (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    target_id INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    read_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- New actors are added to the unread notification of the same type and target.
+CREATE UNIQUE INDEX notification_unread_unique_idx ON notification (user_id, type, target_id) WHERE read_at IS NULL;
+CREATE INDEX notification_user_idx ON notification (user_id, updated_at DESC);
+
+CREATE TABLE notification_actor (
+    notification_id INTEGER NOT NULL,
+    actor_id INTEGER NOT NULL,
+    acted_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (notification_id, actor_id),
+    FOREIGN KEY (notification_id) REFERENCES notification(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE notification_preference (
+    user_id INTEGER NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    muted BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (user_id, type),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE webhook_subscription (
+    subscription_id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    event_types TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Queue of deliveries. Pending ones are sent once next_attempt_at is due;
+-- claiming one pushes next_attempt_at forward, so a worker that dies while
+-- sending it leaves it to be retried. Dead ones ran out of attempts.
+CREATE TABLE webhook_delivery (
+    delivery_id BIGSERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+    replay_of BIGINT,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMP,
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscription(subscription_id) ON DELETE CASCADE,
+    FOREIGN KEY (replay_of) REFERENCES webhook_delivery(delivery_id) ON DELETE SET NULL
+);
+
+CREATE INDEX webhook_delivery_due_idx ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_delivery_subscription_idx ON webhook_delivery (subscription_id, delivery_id DESC);
+
+CREATE TABLE webhook_delivery_attempt (
+    attempt_id BIGSERIAL PRIMARY KEY,
+    delivery_id BIGINT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    response_body TEXT NOT NULL DEFAULT '',
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    attempted_at TIMESTAMP NOT NULL DEFAULT now(),
+    FOREIGN KEY (delivery_id) REFERENCES webhook_delivery(delivery_id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_delivery_attempt_delivery_idx ON webhook_delivery_attempt (delivery_id, attempt);